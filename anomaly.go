@@ -0,0 +1,363 @@
+package wallex
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Defaults of AnomalyOptions.
+const (
+	DefaultAnomalySigma        = 6
+	DefaultAnomalyMinJump      = 0.01
+	DefaultAnomalySizeMultiple = 20
+	DefaultAnomalyWindow       = 200
+	DefaultAnomalyMinSamples   = 30
+	DefaultAnomalyCooldown     = time.Minute
+	DefaultAnomalyMaxDeviation = 0.05
+)
+
+// AnomalyKind classifies an anomalous trade.
+type AnomalyKind string
+
+const (
+	// AnomalyPriceJump: the price moved from the previous trade by more
+	// than Sigma standard deviations of the recent moves.
+	AnomalyPriceJump AnomalyKind = "price_jump"
+
+	// AnomalySize: the quantity is more than SizeMultiple times the median
+	// of the recent trades.
+	AnomalySize AnomalyKind = "size"
+
+	// AnomalyReference: the price deviates from the external reference by
+	// more than MaxDeviation.
+	AnomalyReference AnomalyKind = "reference"
+)
+
+// TradeAnomaly is emitted by an AnomalyDetector for an anomalous print.
+type TradeAnomaly struct {
+	Kind   AnomalyKind
+	Symbol string
+	Trade  t.Trade
+
+	// Price and Quantity are those of Trade.
+	Price    float64
+	Quantity float64
+
+	// Reference is the price of the previous trade for a price jump, the
+	// median quantity for a size anomaly and the external price for a
+	// reference anomaly. Score is how far the trade is from it: the move
+	// in standard deviations, the multiple of the median, or the relative
+	// deviation.
+	Reference float64
+	Score     float64
+
+	DetectedAt time.Time
+}
+
+// AnomalyOptions configures an AnomalyDetector. Zero values select the
+// defaults.
+type AnomalyOptions struct {
+	// Sigma is the number of standard deviations of the recent log
+	// returns between consecutive trades beyond which a move is a jump.
+	// Defaults to DefaultAnomalySigma.
+	Sigma float64
+
+	// MinJump is the smallest relative move reported as a jump, e.g. 0.01
+	// for 1%, so that one tick on a quiet market is not one. Defaults to
+	// DefaultAnomalyMinJump.
+	MinJump float64
+
+	// SizeMultiple is the multiple of the median quantity beyond which a
+	// trade is abnormally large. Defaults to DefaultAnomalySizeMultiple.
+	SizeMultiple float64
+
+	// Window is the number of recent trades per symbol the statistics are
+	// computed over, and MinSamples the number needed before trades are
+	// judged. Default to DefaultAnomalyWindow and DefaultAnomalyMinSamples.
+	Window     int
+	MinSamples int
+
+	// Cooldown is how long after its last anomaly a symbol is considered
+	// normal again, on its next regular trade. Defaults to
+	// DefaultAnomalyCooldown.
+	Cooldown time.Duration
+
+	// Reference, when set, supplies external benchmark prices: a trade
+	// deviating from the reference of its symbol by more than
+	// MaxDeviation, e.g. 0.03 for 3%, is an AnomalyReference. References
+	// older than MaxReferenceAge are not used; zero accepts any age.
+	Reference       ReferencePrice
+	MaxDeviation    float64
+	MaxReferenceAge time.Duration
+
+	// OnAnomaly is called for every anomalous trade.
+	OnAnomaly func(TradeAnomaly)
+
+	// OnNormal is called when an anomalous symbol turns normal again.
+	OnNormal func(symbol string)
+
+	// Pause lists switches that are killed while any symbol is anomalous
+	// and resumed once all symbols are normal again.
+	Pause []TradingSwitch
+}
+
+type anomalyHistory struct {
+	last    float64
+	returns []float64
+	sizes   []float64
+	flagged time.Time
+}
+
+// AnomalyDetector flags anomalous prints in a trade stream, price jumps
+// and abnormally large trades, typically fed from a TradeTape:
+//
+//	detector := wallex.NewAnomalyDetector(wallex.AnomalyOptions{
+//	    OnAnomaly: func(a wallex.TradeAnomaly) { log.Println(a.Kind, a.Symbol, a.Price) },
+//	    Pause:     []wallex.TradingSwitch{checker},
+//	})
+//	trades := detector.ObserveTape(tape.Trades())
+//
+// Jumps are measured against the root mean square of the recent log returns
+// of the symbol, which anomalous moves are kept out of; the price level
+// itself follows every trade, so a lasting repricing is reported once.
+// Sizes are compared with the median of the recent quantities, and prices
+// with an external reference when one is configured.
+//
+// AnomalyDetector is safe for concurrent use.
+type AnomalyDetector struct {
+	opts AnomalyOptions
+
+	mu      sync.Mutex
+	symbols map[string]*anomalyHistory
+	paused  bool
+}
+
+// NewAnomalyDetector returns an AnomalyDetector.
+func NewAnomalyDetector(opts AnomalyOptions) *AnomalyDetector {
+	if opts.Sigma <= 0 {
+		opts.Sigma = DefaultAnomalySigma
+	}
+	if opts.MinJump <= 0 {
+		opts.MinJump = DefaultAnomalyMinJump
+	}
+	if opts.SizeMultiple <= 0 {
+		opts.SizeMultiple = DefaultAnomalySizeMultiple
+	}
+	if opts.Window <= 0 {
+		opts.Window = DefaultAnomalyWindow
+	}
+	if opts.MinSamples <= 0 {
+		opts.MinSamples = DefaultAnomalyMinSamples
+	}
+	if opts.MinSamples > opts.Window {
+		opts.MinSamples = opts.Window
+	}
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = DefaultAnomalyCooldown
+	}
+	if opts.MaxDeviation <= 0 {
+		opts.MaxDeviation = DefaultAnomalyMaxDeviation
+	}
+	return &AnomalyDetector{
+		opts:    opts,
+		symbols: make(map[string]*anomalyHistory),
+	}
+}
+
+// Add judges a trade and records it, returning its anomalies. Trades with
+// an unparsable price or quantity are ignored.
+func (d *AnomalyDetector) Add(tr t.Trade) []TradeAnomaly {
+	price, err := strconv.ParseFloat(tr.Price.String(), 64)
+	if err != nil || price <= 0 {
+		return nil
+	}
+	qty, err := strconv.ParseFloat(tr.Quantity.String(), 64)
+	if err != nil || qty < 0 {
+		return nil
+	}
+	now := time.Now()
+	at := tr.Timestamp.Time
+	if at.IsZero() {
+		at = now
+	}
+
+	var ref float64
+	if d.opts.Reference != nil {
+		p, refAt, ok := d.opts.Reference.ReferencePrice(context.Background(), tr.Symbol)
+		if ok && p > 0 && (d.opts.MaxReferenceAge <= 0 || now.Sub(refAt) <= d.opts.MaxReferenceAge) {
+			ref = p
+		}
+	}
+
+	d.mu.Lock()
+	h, ok := d.symbols[tr.Symbol]
+	if !ok {
+		h = &anomalyHistory{}
+		d.symbols[tr.Symbol] = h
+	}
+
+	var anomalies []TradeAnomaly
+	report := func(kind AnomalyKind, ref, score float64) {
+		anomalies = append(anomalies, TradeAnomaly{
+			Kind: kind, Symbol: tr.Symbol, Trade: tr,
+			Price: price, Quantity: qty,
+			Reference: ref, Score: score,
+			DetectedAt: now,
+		})
+	}
+
+	if h.last > 0 {
+		r := math.Log(price / h.last)
+		jump := false
+		if len(h.returns) >= d.opts.MinSamples && math.Abs(price/h.last-1) >= d.opts.MinJump {
+			score := math.Inf(1)
+			if rms := rootMeanSquare(h.returns); rms > 0 {
+				score = math.Abs(r) / rms
+			}
+			if score > d.opts.Sigma {
+				jump = true
+				report(AnomalyPriceJump, h.last, score)
+			}
+		}
+		if !jump {
+			h.returns = appendWindow(h.returns, r, d.opts.Window)
+		}
+	}
+	h.last = price
+
+	if len(h.sizes) >= d.opts.MinSamples {
+		if med := median(h.sizes); med > 0 && qty > d.opts.SizeMultiple*med {
+			report(AnomalySize, med, qty/med)
+		}
+	}
+	h.sizes = appendWindow(h.sizes, qty, d.opts.Window)
+
+	if ref > 0 {
+		if dev := math.Abs(price/ref - 1); dev > d.opts.MaxDeviation {
+			report(AnomalyReference, ref, dev)
+		}
+	}
+
+	recovered := false
+	switch {
+	case len(anomalies) > 0:
+		h.flagged = at
+	case !h.flagged.IsZero() && at.Sub(h.flagged) >= d.opts.Cooldown:
+		h.flagged = time.Time{}
+		recovered = true
+	}
+	d.mu.Unlock()
+
+	if d.opts.OnAnomaly != nil {
+		for _, a := range anomalies {
+			d.opts.OnAnomaly(a)
+		}
+	}
+	if recovered && d.opts.OnNormal != nil {
+		d.opts.OnNormal(tr.Symbol)
+	}
+	if len(anomalies) > 0 || recovered {
+		d.updatePause()
+	}
+	return anomalies
+}
+
+// ObserveTape forwards the trades of a TradeTape, adding every one of them
+// to the detector first.
+func (d *AnomalyDetector) ObserveTape(in <-chan TapeTrade) <-chan TapeTrade {
+	out := make(chan TapeTrade, cap(in))
+	go func() {
+		defer close(out)
+		for tr := range in {
+			d.Add(tr.Trade)
+			out <- tr
+		}
+	}()
+	return out
+}
+
+// Anomalous returns the symbols currently considered anomalous in sorted
+// order.
+func (d *AnomalyDetector) Anomalous() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var out []string
+	for s, h := range d.symbols {
+		if !h.flagged.IsZero() {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Reset forgets the history of symbol, e.g. after a known repricing, and
+// clears its anomalous state.
+func (d *AnomalyDetector) Reset(symbol string) {
+	d.mu.Lock()
+	delete(d.symbols, symbol)
+	d.mu.Unlock()
+	d.updatePause()
+}
+
+// updatePause kills the switches when a symbol is anomalous and resumes
+// them once none is, acting only on transitions.
+func (d *AnomalyDetector) updatePause() {
+	d.mu.Lock()
+	anomalous := false
+	var first string
+	for s, h := range d.symbols {
+		if !h.flagged.IsZero() {
+			if !anomalous || s < first {
+				first = s
+			}
+			anomalous = true
+		}
+	}
+	changed := anomalous != d.paused
+	d.paused = anomalous
+	d.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	for _, sw := range d.opts.Pause {
+		if anomalous {
+			sw.Kill("anomalous trades in " + first)
+		} else {
+			sw.Resume()
+		}
+	}
+}
+
+// appendWindow appends v to s, keeping the last n values.
+func appendWindow(s []float64, v float64, n int) []float64 {
+	if len(s) >= n {
+		s = append(s[:0], s[len(s)-n+1:]...)
+	}
+	return append(s, v)
+}
+
+func rootMeanSquare(s []float64) float64 {
+	var sum float64
+	for _, v := range s {
+		sum += v * v
+	}
+	return math.Sqrt(sum / float64(len(s)))
+}
+
+func median(s []float64) float64 {
+	sorted := append([]float64(nil), s...)
+	sort.Float64s(sorted)
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,79 @@
+package wallex
+
+import (
+	"context"
+	"iter"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// WallexAPI describes the methods of Client that call the Wallex API: the
+// endpoint methods, the iterators paging through list endpoints, and the
+// helpers built on them, such as EnsureOrder, WaitForDeposit or
+// SubmitWithdrawal.
+//
+// Code that depends on WallexAPI instead of *Client can be unit tested
+// without an HTTP layer by substituting the hand-written mock from the
+// wallexmock package:
+//
+//	func Rebalance(api wallex.WallexAPI) error { ... }
+//
+//	mock := &wallexmock.Client{
+//	    GetWalletsFunc: func() (*types.Wallets, error) { return wallets, nil },
+//	}
+//	err := Rebalance(mock)
+//
+// Endpoint methods accept RequestOptions tuning the individual call; the
+// helpers taking a context are bound to it instead.
+//
+// The low-level Request, ApiRequest and Raw helpers are intentionally not
+// part of the interface; they are transport plumbing rather than API
+// surface. Neither are client configuration and statistics, nor the
+// streaming helpers such as WatchOrderBook. The components built on a
+// Client (OrderTracker, TradeSyncer, Iceberg, TradeTape, MarketDataCache)
+// still take a *Client.
+type WallexAPI interface {
+	GetMarketsInfo(opts ...RequestOption) (*t.MarketInformation, error)
+	GetCurrencyStats(opts ...RequestOption) (*t.CurrencyStatsResponse, error)
+	GetOrderBook(symbol string, opts ...RequestOption) (*t.Depth, error)
+	GetAllOrderBooks(opts ...RequestOption) (*t.AllDepths, error)
+	GetRecentTrades(symbol string, opts ...RequestOption) (*t.Trades, error)
+	GetCandles(params t.CandleParams, opts ...RequestOption) (*t.CandleHistory, error)
+	GetWallets(opts ...RequestOption) (*t.Wallets, error)
+	CreateOrder(params t.CreateOrderParams, opts ...RequestOption) (*t.BaseOrderResponse, error)
+	CancelOrder(clientOrderId string, opts ...RequestOption) (*t.CancelOrderResponse, error)
+	GetOpenOrders(symbol string, opts ...RequestOption) (*t.OpenOrdersResponse, error)
+	GetOrderStatus(clientOrderId string, opts ...RequestOption) (*t.BaseOrderResponse, error)
+	GetUserTrades(params t.UserTradesParams, opts ...RequestOption) (*t.UserTradesResponse, error)
+	GetOrderHistory(params t.OrderHistoryParams, opts ...RequestOption) (*t.OrderHistoryResponse, error)
+	GetAssetNetworks(asset string, opts ...RequestOption) (*t.AssetNetworksResponse, error)
+	GetAccountFees(opts ...RequestOption) (*t.AccountFeesResponse, error)
+	WithdrawFiat(params t.FiatWithdrawalParams, opts ...RequestOption) (*t.FiatWithdrawalResponse, error)
+	GetFiatDeposits(params t.HistoryParams, opts ...RequestOption) (*t.FiatHistoryResponse, error)
+	GetFiatWithdrawals(params t.HistoryParams, opts ...RequestOption) (*t.FiatHistoryResponse, error)
+	GetCryptoDeposits(params t.CryptoHistoryParams, opts ...RequestOption) (*t.CryptoHistoryResponse, error)
+	WithdrawCrypto(params t.CryptoWithdrawalParams, opts ...RequestOption) (*t.CryptoWithdrawalResponse, error)
+	GetCryptoWithdrawals(params t.CryptoHistoryParams, opts ...RequestOption) (*t.CryptoHistoryResponse, error)
+	ReplaceOrder(ctx context.Context, clientOrderId string, newPrice string, newQty string) (*ReplaceOrderResult, error)
+	CreateOrders(ctx context.Context, params []t.CreateOrderParams) []CreateOrderResult
+	CancelOrdersOlderThan(ctx context.Context, symbol string, age time.Duration) ([]CancelOrderResult, error)
+	ListOpenOrders(ctx context.Context, symbols ...string) (*t.OpenOrdersResponse, error)
+	EnsureOrder(ctx context.Context, params t.CreateOrderParams) (*t.BaseOrderResponse, error)
+	ExecuteMarketWithLimit(ctx context.Context, symbol, side string, qty, maxSlippageBps float64) (*t.BaseOrderResponse, error)
+	AllocateBudget(symbol string, budget, price, feeRate float64, opts ...RequestOption) (*Allocation, error)
+	FeeBreakeven(symbol string, opts ...RequestOption) (*Breakeven, error)
+	ProbeCapabilities(ctx context.Context) (Capabilities, error)
+	UserTrades(ctx context.Context, params t.UserTradesParams) iter.Seq2[t.UserTrade, error]
+	OrderHistory(ctx context.Context, params t.OrderHistoryParams) iter.Seq2[t.BaseOrder, error]
+	FiatDeposits(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error]
+	FiatWithdrawals(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error]
+	CryptoDeposits(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error]
+	CryptoWithdrawals(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error]
+	WaitForDeposit(ctx context.Context, asset, minAmount string) (*t.CryptoTransfer, error)
+	WaitForDepositWithOptions(ctx context.Context, asset, minAmount string, opts DepositWaitOptions) (*t.CryptoTransfer, error)
+	SubmitWithdrawal(ctx context.Context, params t.CryptoWithdrawalParams, opts WithdrawalTrackOptions) (*t.CryptoTransfer, error)
+	TrackWithdrawal(ctx context.Context, withdrawal t.CryptoTransfer, opts WithdrawalTrackOptions) (*t.CryptoTransfer, error)
+}
+
+var _ WallexAPI = (*Client)(nil)
@@ -0,0 +1,179 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// BackoffPolicy computes how long to wait before the next retry or
+// reconnect attempt.
+//
+// attempt is zero for the first retry; prev is the delay returned for the
+// previous attempt (zero on the first call). Implementations must be safe
+// for concurrent use.
+type BackoffPolicy interface {
+	Next(attempt int, prev time.Duration) time.Duration
+}
+
+// DefaultBackoff returns the policy used when none is configured: exponential
+// backoff from 100ms up to 5s with full jitter.
+func DefaultBackoff() BackoffPolicy {
+	return ExponentialBackoff{
+		Base:   100 * time.Millisecond,
+		Max:    5 * time.Second,
+		Jitter: true,
+	}
+}
+
+// ConstantBackoff waits the same Delay before every attempt.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+// Next implements BackoffPolicy.
+func (b ConstantBackoff) Next(int, time.Duration) time.Duration {
+	return b.Delay
+}
+
+// ExponentialBackoff waits Base×Multiplier^attempt, capped at Max.
+//
+// With Jitter set, the delay is drawn uniformly from [0, computed delay]
+// ("full jitter"), which spreads out retries of many concurrent clients.
+type ExponentialBackoff struct {
+	Base       time.Duration
+	Max        time.Duration
+	Multiplier float64 // defaults to 2
+	Jitter     bool
+}
+
+// Next implements BackoffPolicy.
+func (b ExponentialBackoff) Next(attempt int, _ time.Duration) time.Duration {
+	mult := b.Multiplier
+	if mult <= 1 {
+		mult = 2
+	}
+
+	d := float64(b.Base)
+	for i := 0; i < attempt; i++ {
+		d *= mult
+		if b.Max > 0 && d >= float64(b.Max) {
+			d = float64(b.Max)
+			break
+		}
+	}
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+
+	if b.Jitter {
+		d = rand.Float64() * d
+	}
+	return time.Duration(d)
+}
+
+// DecorrelatedJitterBackoff implements the "decorrelated jitter" algorithm:
+// each delay is drawn uniformly from [Base, prev×3], capped at Max. It grows
+// roughly exponentially while keeping retries of concurrent callers spread
+// apart.
+type DecorrelatedJitterBackoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Next implements BackoffPolicy.
+func (b DecorrelatedJitterBackoff) Next(_ int, prev time.Duration) time.Duration {
+	if prev < b.Base {
+		prev = b.Base
+	}
+	hi := prev * 3
+	d := b.Base
+	if hi > b.Base {
+		d += time.Duration(rand.Int64N(int64(hi - b.Base)))
+	}
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// RetryBudget limits how many retries may be made across requests.
+//
+// AllowRetry is called before every retry and must not block; returning
+// false makes the failed attempt final. Implementations must be safe for
+// concurrent use. A budget shared by several processes can be implemented
+// on top of an external store.
+type RetryBudget interface {
+	AllowRetry() bool
+}
+
+// NewRetryBudget returns a RetryBudget allowing perMinute retries per
+// minute, refilled continuously, with bursts of up to perMinute retries.
+func NewRetryBudget(perMinute int) RetryBudget {
+	return retryBucket{NewRateLimiter(float64(perMinute)/60, perMinute)}
+}
+
+type retryBucket struct {
+	bucket *TokenBucket
+}
+
+func (b retryBucket) AllowRetry() bool {
+	return b.bucket.reserve() <= 0
+}
+
+// allowRetry reports whether a retry may be made when it would start
+// elapsed after the first attempt. PriorityHigh calls bypass the
+// RetryBudget. Denied retries are counted in wallex_retries_denied_total.
+func (c *Client) allowRetry(ctx context.Context, endpoint string, elapsed time.Duration) bool {
+	reason := ""
+	switch {
+	case c.MaxRetryElapsed > 0 && elapsed > c.MaxRetryElapsed:
+		reason = "elapsed"
+	case c.RetryBudget != nil && PriorityFromContext(ctx) < PriorityHigh && !c.RetryBudget.AllowRetry():
+		reason = "budget"
+	default:
+		return true
+	}
+	c.metrics().Add("wallex_retries_denied_total", 1,
+		Label{Name: "endpoint", Value: endpoint},
+		Label{Name: "reason", Value: reason},
+	)
+	return false
+}
+
+// isRetryable reports whether a failed request may be retried safely.
+//
+// Rate limiting (429) is always retryable because Wallex rejects the request
+// before processing it. Transport failures and 502/503/504 responses are only
+// retried for idempotent methods: a POST such as CreateOrder may have been
+// executed even though the response was lost, and retrying it could place a
+// duplicate order. Maintenance errors are not retried: maintenance lasts far
+// longer than any backoff.
+func isRetryable(method string, err error) bool {
+	var mErr *MaintenanceError
+	if errors.As(err, &mErr) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusTooManyRequests:
+			return true
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return idempotentMethod(method)
+		}
+		return false
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.Operation {
+		case "sending request", "reading response":
+			return idempotentMethod(method)
+		}
+	}
+	return false
+}
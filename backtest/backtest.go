@@ -0,0 +1,185 @@
+// Package backtest replays historical market data through a
+// wallex.Strategy against a simulation.Exchange.
+//
+// The simulated exchange applies the Wallex precision rules and minimums
+// of each market, locks funds like Wallex and charges maker/taker fees, so
+// a strategy that runs here runs unchanged against Client.Broker:
+//
+//	history, _ := client.GetCandles(types.CandleParams{Symbol: "BTCUSDT", Resolution: types.Resolution1h, From: from, To: to})
+//	markets, _ := client.GetMarketsInfo()
+//	result, err := backtest.Run(ctx, strategy, backtest.CandleEvents("BTCUSDT", history.Candles()), backtest.Config{
+//	    Config: simulation.Config{
+//	        Markets:  map[string]types.SymbolInfo{"BTCUSDT": markets.Result.Symbols["BTCUSDT"]},
+//	        Balances: map[string]float64{"USDT": 1_000},
+//	    },
+//	    QuoteAsset: "USDT",
+//	})
+//
+// Orders placed while handling an event execute against the following
+// events of their market: a candle's open (market orders and crossing
+// limits, as taker) or its range (resting limits, as maker at their
+// price), the price of a public trade, or the levels of a book snapshot.
+package backtest
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	wallex "github.com/darhelm/go-wallex"
+	"github.com/darhelm/go-wallex/simulation"
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Config configures a backtest. The embedded simulation.Config sets up
+// the simulated account; see simulation.Config.Latency to delay order
+// entry and cancels.
+type Config struct {
+	simulation.Config
+
+	// QuoteAsset is the asset equity and PnL are measured in.
+	QuoteAsset string
+}
+
+// EquityPoint is the account value after one event.
+type EquityPoint struct {
+	Time   time.Time
+	Equity float64
+}
+
+// Stats summarizes a backtest.
+type Stats struct {
+	Orders   int // orders submitted
+	Rejected int // orders rejected by market rules or balance
+	Fills    int
+
+	// Volume and Fees are in QuoteAsset; fees charged in a base asset are
+	// valued at the fill price.
+	Volume float64
+	Fees   float64
+
+	// Return is PnL relative to the initial equity.
+	Return float64
+
+	// MaxDrawdown is the largest peak-to-trough fall of equity, as a
+	// fraction of the peak.
+	MaxDrawdown float64
+}
+
+// Result is the outcome of a backtest.
+type Result struct {
+	InitialEquity float64
+	FinalEquity   float64
+	PnL           float64
+
+	// Balances are the final balances per asset, including funds locked
+	// by orders left open.
+	Balances map[string]float64
+
+	Fills  []simulation.Fill
+	Equity []EquityPoint
+	Stats  Stats
+}
+
+// Run replays events through strategy in order and returns the simulated
+// result. Events must be sorted by time; Merge combines several series.
+// Run stops at the first error returned by the strategy or when ctx is
+// done.
+func Run(ctx context.Context, strategy wallex.Strategy, events []wallex.MarketEvent, cfg Config) (*Result, error) {
+	ex := simulation.New(cfg.Config)
+
+	res := &Result{}
+	started := false
+	for _, ev := range events {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		switch {
+		case ev.Candle != nil:
+			ex.OnCandle(ev.Symbol, *ev.Candle)
+		case ev.Trade != nil:
+			ex.OnTrade(*ev.Trade)
+		case ev.Book != nil:
+			ex.OnBook(ev.Symbol, *ev.Book, ev.Time)
+		}
+		if !started {
+			res.InitialEquity = ex.Equity(cfg.QuoteAsset)
+			started = true
+		}
+
+		if err := strategy.OnEvent(ctx, ev, ex); err != nil {
+			return nil, err
+		}
+		res.Equity = append(res.Equity, EquityPoint{Time: ev.Time, Equity: ex.Equity(cfg.QuoteAsset)})
+	}
+
+	res.FinalEquity = ex.Equity(cfg.QuoteAsset)
+	if !started {
+		res.InitialEquity = res.FinalEquity
+	}
+	res.PnL = res.FinalEquity - res.InitialEquity
+	res.Balances = ex.Balances()
+	res.Fills = ex.Fills()
+	res.Stats = stats(res, ex, cfg)
+	return res, nil
+}
+
+func stats(res *Result, ex *simulation.Exchange, cfg Config) Stats {
+	s := Stats{Fills: len(res.Fills)}
+	s.Orders, s.Rejected = ex.OrderCounts()
+	for _, f := range res.Fills {
+		market := cfg.Markets[f.Symbol]
+		rate := ex.Price(market.QuoteAsset, cfg.QuoteAsset)
+		s.Volume += f.Price * f.Qty * rate
+		fee := f.Fee
+		if f.FeeAsset == market.BaseAsset {
+			fee *= f.Price
+		}
+		s.Fees += fee * rate
+	}
+	if res.InitialEquity > 0 {
+		s.Return = res.PnL / res.InitialEquity
+	}
+
+	peak := res.InitialEquity
+	for _, p := range res.Equity {
+		peak = math.Max(peak, p.Equity)
+		if peak > 0 {
+			s.MaxDrawdown = math.Max(s.MaxDrawdown, (peak-p.Equity)/peak)
+		}
+	}
+	return s
+}
+
+// CandleEvents converts candles of symbol to events. Each event is stamped
+// with the candle's open time and delivered as a complete candle.
+func CandleEvents(symbol string, candles t.Candles) []wallex.MarketEvent {
+	events := make([]wallex.MarketEvent, len(candles))
+	for i := range candles {
+		events[i] = wallex.MarketEvent{Symbol: symbol, Time: candles[i].Time, Candle: &candles[i]}
+	}
+	return events
+}
+
+// TradeEvents converts public trades to events, sorted by time.
+func TradeEvents(trades []t.Trade) []wallex.MarketEvent {
+	events := make([]wallex.MarketEvent, len(trades))
+	for i := range trades {
+		events[i] = wallex.MarketEvent{Symbol: trades[i].Symbol, Time: trades[i].Timestamp.Time, Trade: &trades[i]}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
+
+// Merge combines event series into one, ordered by time. Events with equal
+// times keep the order of the series they came from.
+func Merge(series ...[]wallex.MarketEvent) []wallex.MarketEvent {
+	var out []wallex.MarketEvent
+	for _, s := range series {
+		out = append(out, s...)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out
+}
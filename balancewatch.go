@@ -0,0 +1,284 @@
+package wallex
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// BalanceChangeCause is the BalanceWatcher's best guess at why a balance
+// changed. Wallex balances carry no history, so the cause is inferred from
+// the shape of the change and, when enabled, from the user's trades.
+type BalanceChangeCause string
+
+const (
+	// CauseDeposit is an increase of the total that no trade explains.
+	CauseDeposit BalanceChangeCause = "deposit"
+
+	// CauseWithdrawal is a decrease of the total that released locked
+	// funds and that no trade explains: a withdrawal that settled.
+	CauseWithdrawal BalanceChangeCause = "withdrawal"
+
+	// CauseTrade is a change explained by trades of a market of the asset.
+	CauseTrade BalanceChangeCause = "trade"
+
+	// CauseLock is a change of the locked amount only, e.g. an order being
+	// placed or cancelled, or a withdrawal being requested.
+	CauseLock BalanceChangeCause = "lock"
+
+	// CauseUnknown is any other change.
+	CauseUnknown BalanceChangeCause = "unknown"
+)
+
+// BalanceChanged describes a change of one asset's balance.
+type BalanceChanged struct {
+	Asset string
+
+	// Previous is the balance when the last change of the asset was
+	// reported (or first observed); Current is the new balance.
+	Previous t.Balance
+	Current  t.Balance
+
+	// Delta and LockedDelta are Current minus Previous of the total and the
+	// locked amount.
+	Delta       float64
+	LockedDelta float64
+
+	Cause      BalanceChangeCause
+	ObservedAt time.Time
+}
+
+// BalanceWatcherOptions tunes a BalanceWatcher.
+type BalanceWatcherOptions struct {
+	// MinChange is the smallest absolute change of an asset's total or
+	// locked amount that is reported, keyed by asset. Assets without an
+	// entry use DefaultMinChange. Smaller changes accumulate until they
+	// cross the threshold, so dust from fees and rounding never fires
+	// events but a slow drift eventually does.
+	MinChange        map[string]float64
+	DefaultMinChange float64
+
+	// ClassifyTrades fetches the user's recent trades on every change so
+	// trading can be told apart from deposits and withdrawals. It costs one
+	// extra request per poll with changes.
+	ClassifyTrades bool
+}
+
+// BalanceWatcher polls the wallet balances, diffs them against the last
+// reported snapshot and emits a BalanceChanged for every asset whose change
+// exceeds its threshold. The first poll only records the baseline.
+//
+// BalanceWatcher is safe for concurrent use.
+type BalanceWatcher struct {
+	client *Client
+	opts   BalanceWatcherOptions
+
+	mu        sync.Mutex
+	reference map[string]t.Balance
+	lastPoll  time.Time
+	callbacks []func(BalanceChanged)
+
+	// pollMu serializes Poll so callbacks observe changes in order.
+	pollMu sync.Mutex
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewBalanceWatcher returns a BalanceWatcher. Register callbacks with
+// OnChange and call Poll or Run.
+func NewBalanceWatcher(c *Client, opts BalanceWatcherOptions) *BalanceWatcher {
+	return &BalanceWatcher{
+		client: c,
+		opts:   opts,
+		stop:   make(chan struct{}),
+	}
+}
+
+// OnChange registers fn to be called for every reported change.
+func (w *BalanceWatcher) OnChange(fn func(BalanceChanged)) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.callbacks = append(w.callbacks, fn)
+}
+
+// Balance returns the last reported balance of asset.
+func (w *BalanceWatcher) Balance(asset string) (t.Balance, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	b, ok := w.reference[asset]
+	return b, ok
+}
+
+// Poll fetches the balances once, fires callbacks for changes in asset
+// order and returns them.
+func (w *BalanceWatcher) Poll(ctx context.Context) ([]BalanceChanged, error) {
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	started := time.Now()
+	wallets, err := w.client.getWallets(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mu.Lock()
+	first := w.reference == nil
+	if first {
+		w.reference = make(map[string]t.Balance, len(wallets.Result.Balances))
+	}
+	since := w.lastPoll
+	w.lastPoll = started
+
+	var changes []BalanceChanged
+	for asset, cur := range wallets.Result.Balances {
+		prev, known := w.reference[asset]
+		if first || !known && cur.Total() == 0 {
+			w.reference[asset] = cur
+			continue
+		}
+		ch := BalanceChanged{
+			Asset:       asset,
+			Previous:    prev,
+			Current:     cur,
+			Delta:       cur.Total() - prev.Total(),
+			LockedDelta: cur.LockedAmount() - prev.LockedAmount(),
+			ObservedAt:  started,
+		}
+		threshold := w.minChange(asset)
+		if ch.Delta == 0 && ch.LockedDelta == 0 || math.Abs(ch.Delta) < threshold && math.Abs(ch.LockedDelta) < threshold {
+			continue
+		}
+		w.reference[asset] = cur
+		changes = append(changes, ch)
+	}
+	// Assets that disappeared from the response are treated as zero.
+	for asset, prev := range w.reference {
+		if _, ok := wallets.Result.Balances[asset]; ok || prev.Total() == 0 && prev.LockedAmount() == 0 {
+			continue
+		}
+		if prev.Total() < w.minChange(asset) && prev.LockedAmount() < w.minChange(asset) {
+			continue
+		}
+		cur := t.Balance{Asset: asset, FaName: prev.FaName, Fiat: prev.Fiat}
+		w.reference[asset] = cur
+		changes = append(changes, BalanceChanged{
+			Asset:       asset,
+			Previous:    prev,
+			Current:     cur,
+			Delta:       -prev.Total(),
+			LockedDelta: -prev.LockedAmount(),
+			ObservedAt:  started,
+		})
+	}
+	callbacks := append([]func(BalanceChanged){}, w.callbacks...)
+	w.mu.Unlock()
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Asset < changes[j].Asset })
+
+	traded := map[string]bool{}
+	if w.opts.ClassifyTrades {
+		traded = w.tradedAssets(ctx, changes, since)
+	}
+	for i := range changes {
+		changes[i].Cause = classifyBalanceChange(changes[i], traded[changes[i].Asset])
+		w.client.metrics().Gauge("wallex_balance", changes[i].Current.Total(), Label{Name: "asset", Value: changes[i].Asset})
+	}
+
+	for _, ch := range changes {
+		for _, fn := range callbacks {
+			fn(ch)
+		}
+	}
+	return changes, nil
+}
+
+func (w *BalanceWatcher) minChange(asset string) float64 {
+	if v, ok := w.opts.MinChange[asset]; ok {
+		return v
+	}
+	return w.opts.DefaultMinChange
+}
+
+// tradedAssets reports which of the changed assets were traded since the
+// given time. A failed lookup classifies nothing as traded.
+func (w *BalanceWatcher) tradedAssets(ctx context.Context, changes []BalanceChanged, since time.Time) map[string]bool {
+	traded := make(map[string]bool)
+	trades, err := w.client.getUserTrades(ctx, t.UserTradesParams{})
+	if err != nil {
+		return traded
+	}
+	for _, tr := range trades.Result.AccountLatestTrades {
+		if tr.Timestamp.Before(since) {
+			continue
+		}
+		for _, ch := range changes {
+			if strings.HasPrefix(tr.Symbol, ch.Asset) || strings.HasSuffix(tr.Symbol, ch.Asset) {
+				traded[ch.Asset] = true
+			}
+		}
+	}
+	return traded
+}
+
+func classifyBalanceChange(ch BalanceChanged, traded bool) BalanceChangeCause {
+	switch {
+	case traded:
+		return CauseTrade
+	case ch.Delta == 0 && ch.LockedDelta != 0:
+		return CauseLock
+	case ch.Delta > 0:
+		return CauseDeposit
+	case ch.Delta < 0 && ch.LockedDelta < 0:
+		return CauseWithdrawal
+	}
+	return CauseUnknown
+}
+
+// Run calls Poll every interval until ctx is done, the watcher is closed or
+// the client is shut down. Poll errors are passed to onError when it is
+// non-nil.
+func (w *BalanceWatcher) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	w.loops.Add(1)
+	defer w.loops.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if _, err := w.Poll(ctx); err != nil && onError != nil && ctx.Err() == nil {
+		onError(err)
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			return
+		case <-w.client.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.Poll(ctx); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Close implements Closer. It stops all Run loops and waits for them.
+func (w *BalanceWatcher) Close(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+	return waitGroupDone(ctx, &w.loops)
+}
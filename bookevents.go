@@ -0,0 +1,162 @@
+package wallex
+
+import (
+	"sort"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// BookEventKind classifies a significant order book change.
+type BookEventKind string
+
+const (
+	// BookLargeAdded: the quantity at a level grew by a large amount,
+	// typically a large order placed.
+	BookLargeAdded BookEventKind = "large_added"
+
+	// BookLargeRemoved: the quantity at a level shrank by a large amount
+	// or the level vanished, through a cancel or a fill.
+	BookLargeRemoved BookEventKind = "large_removed"
+
+	// BookSweep: the best levels of one side were taken out at once, the
+	// best price moving through SweepLevels levels or more.
+	BookSweep BookEventKind = "sweep"
+)
+
+// BookEvent is a significant change between two snapshots of a book.
+type BookEvent struct {
+	Kind BookEventKind
+
+	// Side is "bid" or "ask".
+	Side string
+
+	// Price is the price of the level; for a sweep, the best price before
+	// it, and ToPrice the best price after it.
+	Price   float64
+	ToPrice float64
+
+	// Quantity is the quantity added or removed; for a sweep, the total
+	// quantity of the levels taken out. Notional is its quote value.
+	Quantity float64
+	Notional float64
+
+	// Levels is the number of levels swept.
+	Levels int
+}
+
+// BookEventOptions sets the thresholds of DetectBookEvents. A zero
+// threshold disables the corresponding detection.
+type BookEventOptions struct {
+	// LargeQuantity and LargeNotional are the change of a single level,
+	// in the base asset or in its quote value, from which it is reported
+	// as BookLargeAdded or BookLargeRemoved. Reaching either is enough.
+	LargeQuantity float64
+	LargeNotional float64
+
+	// SweepLevels is the number of levels the best price must move
+	// through for a BookSweep.
+	SweepLevels int
+}
+
+func (o BookEventOptions) enabled() bool {
+	return o.LargeQuantity > 0 || o.LargeNotional > 0 || o.SweepLevels > 0
+}
+
+func (o BookEventOptions) large(qty, price float64) bool {
+	return o.LargeQuantity > 0 && qty >= o.LargeQuantity ||
+		o.LargeNotional > 0 && qty*price >= o.LargeNotional
+}
+
+// DetectBookEvents compares two consecutive snapshots of a book and
+// returns the significant changes of each side, bids first, a sweep
+// before the level changes, which go from the best price down. Levels swept
+// are not reported again as removed.
+//
+// Only the price range both snapshots cover is compared, so levels merely
+// moving in or out of a truncated depth are not mistaken for orders.
+func DetectBookEvents(prev, next t.OrderBook, opts BookEventOptions) []BookEvent {
+	if !opts.enabled() {
+		return nil
+	}
+	var events []BookEvent
+	events = detectSide(events, "bid", prev.Bid, next.Bid, opts, func(a, b float64) bool { return a > b })
+	events = detectSide(events, "ask", prev.Ask, next.Ask, opts, func(a, b float64) bool { return a < b })
+	return events
+}
+
+// detectSide appends the events of one side; better reports whether price
+// a is better than b on that side.
+func detectSide(events []BookEvent, side string, prev, next []t.Order, opts BookEventOptions, better func(a, b float64) bool) []BookEvent {
+	if len(prev) == 0 || len(next) == 0 {
+		return events
+	}
+
+	// swept holds the levels of prev better than the new best price when
+	// they count as a sweep.
+	swept := 0
+	if opts.SweepLevels > 0 {
+		best := next[0].Price
+		n := 0
+		for n < len(prev) && better(prev[n].Price, best) {
+			n++
+		}
+		if n >= opts.SweepLevels {
+			ev := BookEvent{Kind: BookSweep, Side: side, Price: prev[0].Price, ToPrice: best, Levels: n}
+			for _, l := range prev[:n] {
+				q := l.Quantity.Float64()
+				ev.Quantity += q
+				ev.Notional += q * l.Price
+			}
+			events = append(events, ev)
+			swept = n
+		}
+	}
+
+	if opts.LargeQuantity <= 0 && opts.LargeNotional <= 0 {
+		return events
+	}
+
+	// Compare down to the shallower of the two depths: beyond it, one of
+	// the snapshots does not show the book.
+	limit := prev[len(prev)-1].Price
+	if p := next[len(next)-1].Price; better(p, limit) {
+		limit = p
+	}
+
+	before := make(map[float64]float64, len(prev))
+	var prices []float64
+	for _, l := range prev[swept:] {
+		if better(limit, l.Price) {
+			break
+		}
+		if _, ok := before[l.Price]; !ok {
+			prices = append(prices, l.Price)
+		}
+		before[l.Price] += l.Quantity.Float64()
+	}
+	after := make(map[float64]float64, len(next))
+	for _, l := range next {
+		if better(limit, l.Price) {
+			break
+		}
+		if _, ok := after[l.Price]; !ok {
+			if _, ok := before[l.Price]; !ok {
+				prices = append(prices, l.Price)
+			}
+		}
+		after[l.Price] += l.Quantity.Float64()
+	}
+	sort.Slice(prices, func(i, j int) bool { return better(prices[i], prices[j]) })
+
+	for _, price := range prices {
+		delta := after[price] - before[price]
+		kind := BookLargeAdded
+		if delta < 0 {
+			kind, delta = BookLargeRemoved, -delta
+		}
+		if delta > 0 && opts.large(delta, price) {
+			events = append(events, BookEvent{Kind: kind, Side: side, Price: price, Quantity: delta, Notional: delta * price})
+		}
+	}
+	return events
+}
@@ -0,0 +1,79 @@
+package wallex
+
+import (
+	"context"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Breakeven is the minimum spread at which a round trip, buying and then
+// selling the same quantity, breaks even after fees in a market. Spreads
+// are relative to the mid price, e.g. 0.005 for 50 bps.
+type Breakeven struct {
+	Symbol   string
+	MakerFee float64
+	TakerFee float64
+
+	// MakerMaker is the spread needed when both legs are maker orders,
+	// as when quoting both sides; MakerTaker when one leg rests and the
+	// other crosses; TakerTaker when both cross.
+	MakerMaker float64
+	MakerTaker float64
+	TakerTaker float64
+}
+
+// BreakevenSpread returns the spread, relative to the mid price, between
+// a buy paying buyFee and a sell paying sellFee at which the round trip
+// breaks even: the ask must be at least (1+buyFee)/(1-sellFee) times the
+// bid. For equal fees f it is close to 2f.
+func BreakevenSpread(buyFee, sellFee float64) float64 {
+	return 2 * (buyFee + sellFee) / (2 + buyFee - sellFee)
+}
+
+// NewBreakeven returns the breakeven spreads of symbol for its fee.
+func NewBreakeven(symbol string, fee t.SymbolFee) Breakeven {
+	maker, taker := fee.MakerRate(), fee.TakerRate()
+	return Breakeven{
+		Symbol:     symbol,
+		MakerFee:   maker,
+		TakerFee:   taker,
+		MakerMaker: BreakevenSpread(maker, maker),
+		MakerTaker: BreakevenSpread(maker, taker),
+		TakerTaker: BreakevenSpread(taker, taker),
+	}
+}
+
+// ShouldQuote reports whether quoting both sides at spread, e.g. the
+// QuoteConfig.Spread of a Quoter or the current market spread, captures at
+// least minEdge, a spread fraction, beyond the maker round-trip fees.
+func (b Breakeven) ShouldQuote(spread, minEdge float64) bool {
+	return spread >= b.MakerMaker+minEdge
+}
+
+// FeeBreakeven fetches the account's fees from GET /v1/account/fee and
+// returns the breakeven spreads of symbol.
+//
+// Authentication: REQUIRED.
+func (c *Client) FeeBreakeven(symbol string, opts ...RequestOption) (*Breakeven, error) {
+	return c.feeBreakeven(callContext(opts), symbol)
+}
+
+func (c *Client) feeBreakeven(ctx context.Context, symbol string) (*Breakeven, error) {
+	symbol, err := c.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	fees, err := c.getAccountFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	fee, ok := fees.Get(symbol)
+	if !ok {
+		return nil, &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "no fee reported for symbol " + symbol, Err: nil},
+			Symbol:        symbol,
+		}
+	}
+	b := NewBreakeven(symbol, fee)
+	return &b, nil
+}
@@ -0,0 +1,119 @@
+package wallex
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// Allocation is the largest order a quote budget buys in a market.
+type Allocation struct {
+	Symbol string
+
+	// Price is the price the allocation was computed at.
+	Price float64
+
+	// Quantity is the order quantity, rounded down to the step size, and
+	// QuantityString the same formatted for CreateOrderParams.Quantity.
+	Quantity       float64
+	QuantityString string
+
+	// Notional is Quantity × Price, Fee the estimated fee on it and Total
+	// their sum, which never exceeds the budget. Leftover is the part of
+	// the budget not spent.
+	Notional float64
+	Fee      float64
+	Total    float64
+	Leftover float64
+}
+
+// AllocateBudget computes the largest quantity of info's market that a
+// budget in the quote asset buys at price, e.g. how much BTC 10,000,000 TMN
+// buys at the current ask.
+//
+// The fee is estimated as feeRate × notional, e.g. 0.002, and reserved out
+// of the budget; it is zero for zero-fee markets. The quantity is rounded
+// down to the step size, so the order fits the budget after rounding. When
+// the budget cannot buy the market minimums, the returned error wraps
+// CodeInvalidQuantity (below minQty) or CodeMinNotional and tells the
+// budget needed.
+func AllocateBudget(info t.SymbolInfo, budget, price, feeRate float64) (*Allocation, error) {
+	if budget <= 0 || price <= 0 || feeRate < 0 || feeRate >= 1 {
+		return nil, &GoWallexError{
+			Message: "budget and price must be positive and the fee rate in [0, 1)",
+			Err:     nil,
+		}
+	}
+	if info.IsZeroFee {
+		feeRate = 0
+	}
+
+	decimals := int(info.StepSize)
+	step := math.Pow10(-decimals)
+	qty := u.RoundDown(budget/(price*(1+feeRate)), decimals)
+	// Rounding of the division can leave the total a hair above budget.
+	for qty > 0 && qty*price*(1+feeRate) > budget {
+		qty = u.RoundDown(qty-step, decimals)
+	}
+
+	needed := math.Max(info.MinQty.Float64()*price, float64(info.MinNotional)) * (1 + feeRate)
+	switch {
+	case qty <= 0 || qty < info.MinQty.Float64():
+		return nil, budgetError(info, needed, CodeInvalidQuantity)
+	case qty*price < float64(info.MinNotional):
+		return nil, budgetError(info, needed, CodeMinNotional)
+	}
+
+	notional := qty * price
+	fee := notional * feeRate
+	return &Allocation{
+		Symbol:         info.Symbol,
+		Price:          price,
+		Quantity:       qty,
+		QuantityString: strconv.FormatFloat(qty, 'f', decimals, 64),
+		Notional:       notional,
+		Fee:            fee,
+		Total:          notional + fee,
+		Leftover:       budget - notional - fee,
+	}, nil
+}
+
+func budgetError(info t.SymbolInfo, needed float64, code ErrorCode) *GoWallexError {
+	return &GoWallexError{
+		Message: "budget below the minimum order of " + info.Symbol + ": at least " +
+			strconv.FormatFloat(math.Ceil(needed), 'f', 0, 64) + " " + info.QuoteAsset + " needed",
+		Err: code,
+	}
+}
+
+// AllocateBudget loads symbol from GET /v1/markets and runs AllocateBudget
+// on it. A price <= 0 uses the best ask of the market statistics, the price
+// a buy is expected to fill at.
+func (c *Client) AllocateBudget(symbol string, budget, price, feeRate float64, opts ...RequestOption) (*Allocation, error) {
+	return c.allocateBudget(callContext(opts), symbol, budget, price, feeRate)
+}
+
+func (c *Client) allocateBudget(ctx context.Context, symbol string, budget, price, feeRate float64) (*Allocation, error) {
+	symbol, err := c.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	markets, err := c.getMarketsInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := markets.Get(symbol)
+	if !ok {
+		return nil, &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "unknown symbol " + symbol, Err: nil},
+			Symbol:        symbol,
+		}
+	}
+	if price <= 0 {
+		price = info.Stats.AskPriceFloat()
+	}
+	return AllocateBudget(info, budget, price, feeRate)
+}
@@ -0,0 +1,123 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Permission is the state of an API key permission that may not be known.
+type Permission int
+
+const (
+	// PermissionUnknown means the permission could not be determined.
+	PermissionUnknown Permission = iota
+
+	// PermissionGranted means the key has the permission.
+	PermissionGranted
+
+	// PermissionDenied means the key lacks the permission.
+	PermissionDenied
+)
+
+// String returns "unknown", "granted" or "denied".
+func (p Permission) String() string {
+	switch p {
+	case PermissionGranted:
+		return "granted"
+	case PermissionDenied:
+		return "denied"
+	}
+	return "unknown"
+}
+
+// Capabilities describes what the configured API key is allowed to do.
+type Capabilities struct {
+	// Read allows balances, orders and trade history to be queried.
+	Read bool
+
+	// Trade allows orders to be created and cancelled. Only write requests
+	// check it, and ProbeCapabilities sends none, so it is always
+	// PermissionUnknown.
+	Trade Permission
+
+	// Withdraw allows withdrawals to be submitted. Like Trade, it is always
+	// PermissionUnknown.
+	Withdraw Permission
+
+	// CheckedAt is when the capabilities were probed.
+	CheckedAt time.Time
+}
+
+// Capabilities returns the result of the last ProbeCapabilities call, or
+// false if the key has not been probed yet.
+func (c *Client) Capabilities() (Capabilities, bool) {
+	c.caps.mu.RLock()
+	defer c.caps.mu.RUnlock()
+	if c.caps.caps == nil {
+		return Capabilities{}, false
+	}
+	return *c.caps.caps, true
+}
+
+// capabilityCache holds the result of the last ProbeCapabilities call.
+type capabilityCache struct {
+	mu   sync.RWMutex
+	caps *Capabilities
+}
+
+// ProbeCapabilities determines the permissions of the configured API key and
+// caches them for Capabilities.
+//
+// Wallex does not expose a capabilities endpoint, so the key is probed with
+// read-only requests to GET /v1/account/balances and GET
+// /v1/account/openOrders. Read is set when both succeed and cleared when
+// either is refused with 401 or 403. Any other error, including 429, 5xx
+// and maintenance responses, leaves the permission undetermined and is
+// returned.
+//
+// No write request is ever sent, so the trade and withdrawal permissions,
+// which only write endpoints check, are reported as PermissionUnknown.
+//
+// Authentication: REQUIRED.
+func (c *Client) ProbeCapabilities(ctx context.Context) (Capabilities, error) {
+	if err := assertAuth(c.ApiKey()); err != nil {
+		return Capabilities{}, err
+	}
+
+	var caps Capabilities
+	caps.Read = true
+	for _, endpoint := range []string{"/account/balances", "/account/openOrders"} {
+		granted, err := c.probe(ctx, endpoint)
+		if err != nil {
+			return Capabilities{}, err
+		}
+		caps.Read = caps.Read && granted
+	}
+	caps.CheckedAt = time.Now()
+
+	c.caps.mu.Lock()
+	c.caps.caps = &caps
+	c.caps.mu.Unlock()
+
+	return caps, nil
+}
+
+// probe reports whether the key is permitted to GET endpoint. Only a
+// successful response grants the permission and only 401 or 403 denies it;
+// every other error is returned.
+func (c *Client) probe(ctx context.Context, endpoint string) (bool, error) {
+	err := c.ApiRequestContext(ctx, MethodGet, endpoint, "v1", true, nil, nil)
+	if err == nil {
+		return true, nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) &&
+		(apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return false, nil
+	}
+	return false, err
+}
@@ -0,0 +1,284 @@
+// Package chaos provides an http.RoundTripper that injects Wallex failure
+// modes according to a scenario script, so applications can verify how
+// they cope with timeouts, rate limiting, server errors, malformed bodies
+// and success=false responses.
+//
+// Plug a Transport into the client under test:
+//
+//	tr := chaos.NewTransport(nil,
+//	    chaos.Step{Path: "/v1/account/orders", Fault: chaos.Fault{Kind: chaos.RateLimited}, Times: 2},
+//	    chaos.Step{Fault: chaos.Fault{Kind: chaos.ServerError, Status: 503}, Probability: 0.1, Times: -1},
+//	)
+//	client, _ := wallex.NewClient(wallex.ClientOptions{
+//	    ApiKey:     key,
+//	    HttpClient: &http.Client{Transport: tr},
+//	})
+//
+// Each request is matched against the steps in order; the first matching
+// step with uses left injects its fault. Requests matching no step are
+// passed to the next transport. Transport.Injected lists what was injected.
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Kind is a failure mode.
+type Kind string
+
+const (
+	// Timeout hangs the request until its context is done, or fails it
+	// with a timeout error after Fault.Delay when set.
+	Timeout Kind = "timeout"
+
+	// RateLimited answers 429 Too Many Requests with a Retry-After header.
+	RateLimited Kind = "rate_limited"
+
+	// ServerError answers Fault.Status, 503 by default, with a Wallex
+	// error body.
+	ServerError Kind = "server_error"
+
+	// MalformedJSON answers 200 OK with a truncated JSON body.
+	MalformedJSON Kind = "malformed_json"
+
+	// Unsuccessful answers 200 OK with "success": false, as Wallex does
+	// for some rejected requests.
+	Unsuccessful Kind = "unsuccessful"
+
+	// Latency delays the request by Fault.Delay, then passes it on.
+	Latency Kind = "latency"
+)
+
+// Fault describes one injected failure.
+type Fault struct {
+	Kind Kind
+
+	// Status is the HTTP status of ServerError faults.
+	Status int
+
+	// Delay is how long Timeout and Latency faults wait.
+	Delay time.Duration
+
+	// Message is the error message in the response body. A default
+	// depending on Kind is used when empty.
+	Message string
+}
+
+// Step is one entry of a scenario script.
+type Step struct {
+	// Method and Path select the requests the step applies to. Empty
+	// values match any request; Path matches as a prefix of the URL path,
+	// e.g. "/v1/account".
+	Method string
+	Path   string
+
+	Fault Fault
+
+	// Times is how many requests the step injects its fault into: zero
+	// means once, negative means without limit.
+	Times int
+
+	// Probability, when in (0, 1), injects the fault into that share of
+	// matching requests only. Requests left alone do not use up Times.
+	Probability float64
+}
+
+func (s *Step) matches(req *http.Request) bool {
+	if s.Method != "" && !strings.EqualFold(s.Method, req.Method) {
+		return false
+	}
+	return s.Path == "" || strings.HasPrefix(req.URL.Path, s.Path)
+}
+
+// Injection records a fault injected into a request.
+type Injection struct {
+	Method string
+	Path   string
+	Kind   Kind
+	Time   time.Time
+}
+
+// Transport is an http.RoundTripper that injects the faults of a scenario.
+// It is safe for concurrent use.
+type Transport struct {
+	next http.RoundTripper
+
+	mu       sync.Mutex
+	steps    []Step
+	used     []int
+	injected []Injection
+}
+
+// NewTransport creates a Transport playing steps in front of next.
+// A nil next uses http.DefaultTransport.
+func NewTransport(next http.RoundTripper, steps ...Step) *Transport {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Transport{
+		next:  next,
+		steps: steps,
+		used:  make([]int, len(steps)),
+	}
+}
+
+// Injected returns the faults injected so far, oldest first.
+func (tr *Transport) Injected() []Injection {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	return append([]Injection(nil), tr.injected...)
+}
+
+// Done reports whether every step with a limited number of uses has been
+// used up, i.e. the scenario has been played to its end.
+func (tr *Transport) Done() bool {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	for i, s := range tr.steps {
+		if s.Times >= 0 && tr.used[i] < max(s.Times, 1) {
+			return false
+		}
+	}
+	return true
+}
+
+// Reset restarts the scenario and clears the injection log.
+func (tr *Transport) Reset() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.used = make([]int, len(tr.steps))
+	tr.injected = nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (tr *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault, ok := tr.pick(req)
+	if !ok {
+		return tr.next.RoundTrip(req)
+	}
+
+	switch fault.Kind {
+	case Latency:
+		if err := sleep(req.Context(), fault.Delay); err != nil {
+			return nil, err
+		}
+		return tr.next.RoundTrip(req)
+	case Timeout:
+		drain(req)
+		if fault.Delay <= 0 {
+			<-req.Context().Done()
+			return nil, req.Context().Err()
+		}
+		if err := sleep(req.Context(), fault.Delay); err != nil {
+			return nil, err
+		}
+		return nil, timeoutError{}
+	case RateLimited:
+		drain(req)
+		resp := response(req, http.StatusTooManyRequests, errorBody(fault.Message, "Too Many Attempts."))
+		resp.Header.Set("Retry-After", "1")
+		return resp, nil
+	case ServerError:
+		drain(req)
+		status := fault.Status
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		return response(req, status, errorBody(fault.Message, http.StatusText(status))), nil
+	case MalformedJSON:
+		drain(req)
+		return response(req, http.StatusOK, []byte(`{"success":true,"message":"The operation was successful","result":{"`)), nil
+	case Unsuccessful:
+		drain(req)
+		return response(req, http.StatusOK, errorBody(fault.Message, "The operation was not successful")), nil
+	}
+	return tr.next.RoundTrip(req)
+}
+
+// pick returns the fault to inject into req, if any, and uses up its step.
+func (tr *Transport) pick(req *http.Request) (Fault, bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	for i := range tr.steps {
+		s := &tr.steps[i]
+		if s.Times >= 0 && tr.used[i] >= max(s.Times, 1) {
+			continue
+		}
+		if !s.matches(req) {
+			continue
+		}
+		if s.Probability > 0 && s.Probability < 1 && rand.Float64() >= s.Probability {
+			continue
+		}
+		tr.used[i]++
+		tr.injected = append(tr.injected, Injection{
+			Method: req.Method,
+			Path:   req.URL.Path,
+			Kind:   s.Fault.Kind,
+			Time:   time.Now(),
+		})
+		return s.Fault, true
+	}
+	return Fault{}, false
+}
+
+// timeoutError mimics the error net/http returns when a request times out.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "chaos: injected timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func drain(req *http.Request) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+}
+
+func errorBody(message, fallback string) []byte {
+	if message == "" {
+		message = fallback
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"success": false,
+		"message": message,
+		"result":  map[string]interface{}{},
+	})
+	return body
+}
+
+func response(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -2,10 +2,14 @@ package wallex
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	t "github.com/darhelm/go-wallex/types"
@@ -16,11 +20,17 @@ import (
 const (
 	// BaseUrl is the root URL for the Wallex Market API.
 	BaseUrl = "https://api.wallex.ir"
+
+	// DefaultBatchConcurrency is the default number of concurrent requests
+	// issued by batch helpers.
+	DefaultBatchConcurrency = 5
 )
 
 // ClientOptions represents the configuration options for creating a new API client.
 // These options allow customization of the HTTP client, authentication tokens,
 // API credentials, and automatic authentication/refresh behaviors.
+//
+// Printing or logging ClientOptions never reveals ApiKey.
 type ClientOptions struct {
 	// HttpClient is the custom HTTP client to be used for API requests.
 	// If nil, the default HTTP client is used.
@@ -29,6 +39,31 @@ type ClientOptions struct {
 	// Timeout specifies the request timeout duration for the HTTP client.
 	Timeout time.Duration
 
+	// MaxIdleConnsPerHost, IdleConnTimeout and TLSHandshakeTimeout tune
+	// the connection pool of the HTTP client built by NewClient. They are
+	// ignored when HttpClient is set. Defaults: DefaultMaxIdleConnsPerHost,
+	// DefaultIdleConnTimeout and DefaultTLSHandshakeTimeout.
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	TLSHandshakeTimeout time.Duration
+
+	// KeepAlive, if positive, starts a ConnectionWarmer that keeps
+	// KeepAliveConns connections (default: DefaultKeepAliveConns) open
+	// by pinging the API whenever the client was idle for KeepAlive.
+	// The warmer stops on Shutdown.
+	KeepAlive      time.Duration
+	KeepAliveConns int
+
+	// EndpointTimeouts overrides the timeout of individual endpoints, keyed
+	// by method and versioned path (see EndpointAllDepths and friends). The
+	// timeout applies to each attempt. Endpoints not listed use Timeout,
+	// which still caps every request when set.
+	EndpointTimeouts map[string]time.Duration
+
+	// DisableCompression stops the client from requesting gzip/deflate
+	// compressed responses.
+	DisableCompression bool
+
 	// BaseUrl is the base URL of the API. Defaults to the constant BaseUrl
 	// if not provided.
 	BaseUrl string
@@ -37,23 +72,200 @@ type ClientOptions struct {
 
 	// ApiKey is the token used for authenticated API requests.
 	ApiKey string
+
+	// KeyProvider, if set, supplies the API key of every request instead
+	// of ApiKey, e.g. from a secret store. Without it the key can be
+	// rotated with Client.SetApiKey.
+	KeyProvider KeyProvider
+
+	// RateLimiter optionally throttles every outgoing request.
+	// If nil, requests are not throttled client-side.
+	RateLimiter RateLimiter
+
+	// BatchConcurrency caps the number of in-flight requests issued by batch
+	// helpers such as CreateOrders. Defaults to DefaultBatchConcurrency.
+	BatchConcurrency int
+
+	// MaxRetries is the number of times a failed request is retried.
+	// Zero (the default) disables retries.
+	MaxRetries int
+
+	// Backoff computes the delay between retries.
+	// Defaults to DefaultBackoff().
+	Backoff BackoffPolicy
+
+	// MaxRetryElapsed, if positive, caps the total time a request may
+	// spend retrying: a retry whose backoff would end later than
+	// MaxRetryElapsed after the first attempt is not made.
+	MaxRetryElapsed time.Duration
+
+	// RetryBudget optionally limits retries across all requests of the
+	// client, e.g. NewRetryBudget(60) for at most 60 retries per minute,
+	// so a flapping API cannot multiply the request volume. It may be
+	// shared between clients.
+	RetryBudget RetryBudget
+
+	// ValidateSymbols makes market and order methods normalize symbols
+	// ("btc/usdt" → "BTCUSDT") and reject unknown ones locally with an
+	// *UnknownSymbolError instead of sending them to Wallex.
+	ValidateSymbols bool
+
+	// PreTrade optionally validates every order before CreateOrder sends
+	// it, e.g. a *risk.Checker enforcing local risk limits.
+	PreTrade PreTradeCheck
+
+	// Metrics receives measurements published by the client and its
+	// components. If nil, measurements are discarded.
+	Metrics Metrics
+
+	// Logger optionally logs every request attempt with its correlation
+	// ID: successes at debug level, failures at warn level.
+	Logger *slog.Logger
+
+	// MaxResponseAge, if positive, enables staleness checks on market-data
+	// responses: the Age and Date headers of unauthenticated responses and
+	// the newest trade of GET /v1/trades are compared against it. The local
+	// clock must be reasonably synchronized, and trades on illiquid markets
+	// can legitimately be old, so choose a generous threshold.
+	MaxResponseAge time.Duration
+
+	// StalePolicy selects whether stale responses are rejected with a
+	// *StaleResponseError (the default) or only flagged.
+	StalePolicy StalePolicy
+
+	// ConditionalRequests makes GET /v1/markets and GET
+	// /v1/currencies/stats revalidate their last response with ETag /
+	// Last-Modified when the server provides them, so an unchanged payload
+	// costs a 304 instead of a full transfer.
+	ConditionalRequests bool
+
+	// OnError, if set, is called with every error before it is returned,
+	// and with every failed attempt that is retried. It runs synchronously
+	// on the calling goroutine and must not block.
+	OnError func(ctx context.Context, ev ErrorEvent)
+
+	// ReadOnly makes the client refuse every request that could change
+	// account state (any method other than GET) with ErrReadOnly, e.g. for
+	// dashboards and reporting jobs holding a trading key.
+	ReadOnly bool
+
+	// Recorder, if set, receives every market-data response, e.g. a
+	// *recorder.Recorder writing them to disk for replay.
+	Recorder MarketRecorder
+
+	// DetectSchemaDrift compares every decoded response with its raw JSON
+	// and records fields unknown to the Go types or missing from the
+	// response; see Client.SchemaDrift. It costs a second decode per
+	// response and is meant for diagnostics.
+	DetectSchemaDrift bool
+
+	// ProbeCapabilities makes NewClient call ProbeCapabilities so that
+	// Capabilities is populated from the start. NewClient fails if the
+	// probe cannot be completed.
+	ProbeCapabilities bool
 }
 
 // Client represents the API client for interacting with the Wallex Market API.
 // It manages authentication, base URL, and API requests.
+//
+// The API key is redacted from everything the client emits: its String and
+// LogValue forms, returned errors, log records and OnError events.
+//
+// A Client is safe for concurrent use by multiple goroutines, and so are
+// the components built on it. Its internal state (symbol cache, latency
+// and schema drift records, conditional request validators, lifecycle)
+// is synchronized and shared with the clients returned by With.
+//
+// The exported fields are configuration: they are read without locking by
+// every request and must not be modified once the client is in use.
+// Derive a client with different settings with With instead, or pass a
+// RequestOption to change a single call. The base URL, API key and HTTP
+// client are not exported and can only be changed that way.
 type Client struct {
-	// HttpClient is the HTTP client used for API requests.
-	// Defaults to the Go standard library's http.DefaultClient.
-	HttpClient *http.Client
-
-	// BaseUrl is the base URL of the API used by this client.
-	// Defaults to the constant BaseUrl.
-	BaseUrl string
+	// httpClient, baseUrl and keys are fixed at construction; see the
+	// accessors HttpClient, BaseUrl and ApiKey, and With.
+	httpClient *http.Client
+	baseUrl    string
+	keys       KeyProvider
 
 	Version string
 
-	// ApiKey is the API key for authentication.
-	ApiKey string
+	// RateLimiter throttles outgoing requests. Nil disables throttling.
+	RateLimiter RateLimiter
+
+	// BatchConcurrency caps in-flight requests of batch helpers.
+	BatchConcurrency int
+
+	// MaxRetries is the number of retries for transient failures.
+	MaxRetries int
+
+	// Backoff computes the delay between retries.
+	Backoff BackoffPolicy
+
+	// MaxRetryElapsed caps the total retry time of a request when positive.
+	MaxRetryElapsed time.Duration
+
+	// RetryBudget limits retries client-wide. Nil disables the limit.
+	RetryBudget RetryBudget
+
+	// EndpointTimeouts holds per-endpoint attempt timeouts.
+	EndpointTimeouts map[string]time.Duration
+
+	// DisableCompression disables gzip/deflate response compression.
+	DisableCompression bool
+
+	// PreTrade validates orders before they are sent. Nil disables it.
+	PreTrade PreTradeCheck
+
+	// Metrics receives measurements. Nil discards them.
+	Metrics Metrics
+
+	// Logger logs request attempts. Nil disables logging.
+	Logger *slog.Logger
+
+	// OnError is called with every error before it is returned.
+	OnError func(ctx context.Context, ev ErrorEvent)
+
+	// MaxResponseAge enables market-data staleness checks when positive.
+	MaxResponseAge time.Duration
+
+	// StalePolicy selects how stale responses are handled.
+	StalePolicy StalePolicy
+
+	// ConditionalRequests enables ETag/Last-Modified revalidation.
+	ConditionalRequests bool
+
+	// DetectSchemaDrift enables schema drift detection.
+	DetectSchemaDrift bool
+
+	// Recorder receives market-data responses. Nil disables recording.
+	Recorder MarketRecorder
+
+	// ReadOnly makes the client refuse requests that could change account
+	// state; see ErrReadOnly.
+	ReadOnly bool
+
+	// symbols validates symbols when ValidateSymbols was requested.
+	symbols *SymbolResolver
+
+	// The state below is shared by a client and its clones (see With).
+
+	cond  *condCache
+	drift *driftRecorder
+	caps  *capabilityCache
+
+	// latency records per-endpoint attempt latencies.
+	latency *latencyRecorder
+	stats   *statsRecorder
+
+	// hold queues requests while a HealthMonitor with HoldRequests reports
+	// an outage.
+	hold *holdGate
+
+	// lastRequest is the unix nano time of the last request sent.
+	lastRequest *atomic.Int64
+
+	life *lifecycle
 }
 
 // NewClient creates a new Wallex API client.
@@ -64,9 +276,31 @@ type Client struct {
 // Parameters:
 //   - opts.HttpClient: Optional custom HTTP client (default: http.DefaultClient).
 //   - opts.Timeout: Request timeout used if a custom client is not provided.
+//   - opts.MaxIdleConnsPerHost, opts.IdleConnTimeout, opts.TLSHandshakeTimeout:
+//     Connection pool tuning if a custom client is not provided.
+//   - opts.KeepAlive, opts.KeepAliveConns: Keep idle connections warm.
+//   - opts.EndpointTimeouts: Per-endpoint timeouts overriding opts.Timeout.
+//   - opts.DisableCompression: Do not request compressed responses.
 //   - opts.BaseUrl: Override API base URL (default: https://api.wallex.ir).
 //   - opts.Version: Optional API version prefix.
 //   - opts.ApiKey: API key for authenticated endpoints.
+//   - opts.KeyProvider: Source of the API key overriding opts.ApiKey.
+//   - opts.RateLimiter: Optional limiter applied to every request.
+//   - opts.BatchConcurrency: Concurrency of batch helpers (default: 5).
+//   - opts.MaxRetries: Retries for transient failures (default: 0).
+//   - opts.Backoff: Delay policy between retries (default: DefaultBackoff()).
+//   - opts.MaxRetryElapsed, opts.RetryBudget: Per-request and client-wide retry limits.
+//   - opts.ValidateSymbols: Normalize and validate symbols locally.
+//   - opts.PreTrade: Local pre-trade check run before every CreateOrder.
+//   - opts.Metrics: Hook receiving client and component measurements.
+//   - opts.Logger: Structured logger for request attempts.
+//   - opts.OnError: Hook called with every error before it is returned.
+//   - opts.MaxResponseAge, opts.StalePolicy: Stale market-data detection.
+//   - opts.ConditionalRequests: Revalidate large market-data payloads.
+//   - opts.ReadOnly: Refuse state-changing requests locally.
+//   - opts.DetectSchemaDrift: Report response fields unknown to or missing from the Go types.
+//   - opts.Recorder: Capture market-data responses for replay.
+//   - opts.ProbeCapabilities: Probe API key permissions on startup.
 //
 // Behavior:
 //   - Does NOT perform login (Wallex has no login endpoint).
@@ -76,31 +310,94 @@ type Client struct {
 //   - *Client ready to make Wallex API requests.
 func NewClient(opts ClientOptions) (*Client, error) {
 	client := &Client{
-		BaseUrl: BaseUrl,
-		ApiKey:  opts.ApiKey,
+		baseUrl:          BaseUrl,
+		RateLimiter:      opts.RateLimiter,
+		BatchConcurrency: DefaultBatchConcurrency,
+		cond:             new(condCache),
+		drift:            new(driftRecorder),
+		caps:             new(capabilityCache),
+		latency:          new(latencyRecorder),
+		stats:            newStatsRecorder(),
+		hold:             new(holdGate),
+		lastRequest:      new(atomic.Int64),
+		life:             new(lifecycle),
+	}
+
+	if opts.BatchConcurrency > 0 {
+		client.BatchConcurrency = opts.BatchConcurrency
+	}
+
+	client.keys = opts.KeyProvider
+	if client.keys == nil {
+		client.keys = NewRotatingKey(opts.ApiKey)
+	}
+
+	client.MaxRetries = opts.MaxRetries
+	client.MaxRetryElapsed = opts.MaxRetryElapsed
+	client.RetryBudget = opts.RetryBudget
+	client.DisableCompression = opts.DisableCompression
+	client.PreTrade = opts.PreTrade
+	client.Metrics = opts.Metrics
+	client.Logger = opts.Logger
+	client.OnError = opts.OnError
+	client.MaxResponseAge = opts.MaxResponseAge
+	client.StalePolicy = opts.StalePolicy
+	client.ConditionalRequests = opts.ConditionalRequests
+	client.DetectSchemaDrift = opts.DetectSchemaDrift
+	client.Recorder = opts.Recorder
+	client.ReadOnly = opts.ReadOnly
+	if len(opts.EndpointTimeouts) > 0 {
+		client.EndpointTimeouts = make(map[string]time.Duration, len(opts.EndpointTimeouts))
+		for k, v := range opts.EndpointTimeouts {
+			client.EndpointTimeouts[k] = v
+		}
+	}
+	client.Backoff = opts.Backoff
+	if client.Backoff == nil {
+		client.Backoff = DefaultBackoff()
+	}
+
+	if opts.ValidateSymbols {
+		client.symbols = NewSymbolResolver(client, DefaultSymbolCacheTTL)
 	}
 
 	if opts.BaseUrl != "" {
-		client.BaseUrl = opts.BaseUrl
+		client.baseUrl = opts.BaseUrl
 	}
 
 	if opts.HttpClient != nil {
-		client.HttpClient = opts.HttpClient
+		client.httpClient = opts.HttpClient
 	} else {
-		client.HttpClient = &http.Client{
-			Timeout: opts.Timeout,
+		client.httpClient = &http.Client{
+			Timeout:   opts.Timeout,
+			Transport: newTransport(opts),
 		}
 	}
 
+	if opts.ProbeCapabilities {
+		if _, err := client.ProbeCapabilities(context.Background()); err != nil {
+			return nil, &GoWallexError{
+				Message: "failed to probe API key capabilities",
+				Err:     err,
+			}
+		}
+	}
+
+	if opts.KeepAlive > 0 {
+		warmer := NewConnectionWarmer(client, opts.KeepAlive, opts.KeepAliveConns)
+		client.Register(warmer)
+		go warmer.Run(context.Background())
+	}
+
 	return client, nil
 }
 
-// assertAuth ensures the client contains a non-empty API key.
+// assertAuth ensures key, the API key of a request, is non-empty.
 //
 // Used internally by authenticated requests.
-// Returns an error if ApiKey is empty.
-func assertAuth(client *Client) error {
-	if client.ApiKey == "" {
+// Returns an error if the API key is empty.
+func assertAuth(key string) error {
+	if key == "" {
 		return &GoWallexError{
 			Message: "API Key is empty",
 			Err:     nil,
@@ -109,6 +406,25 @@ func assertAuth(client *Client) error {
 	return nil
 }
 
+// BaseUrl returns the base URL of the API used by the client, e.g. the
+// constant BaseUrl.
+func (c *Client) BaseUrl() string {
+	return c.baseUrl
+}
+
+// ApiKey returns the API key new requests authenticate with, or "" if the
+// client has none or its KeyProvider failed. Treat the result as a secret.
+func (c *Client) ApiKey() string {
+	key, _ := c.apiKey(context.Background())
+	return key
+}
+
+// HttpClient returns the HTTP client used for API requests. It is shared
+// with the clients returned by With and must not be modified.
+func (c *Client) HttpClient() *http.Client {
+	return c.httpClient
+}
+
 // createApiURI constructs the full Wallex API URL by combining:
 //
 //	BaseUrl + "/" + version + endpoint
@@ -120,15 +436,35 @@ func assertAuth(client *Client) error {
 //	createApiURI("/depth?symbol=BTCUSDT", "v1")
 //	→ "https://api.wallex.ir/v1/depth?symbol=BTCUSDT"
 func (c *Client) createApiURI(endpoint string, version string) string {
-	return fmt.Sprintf("%s/%s%s", c.BaseUrl, version, endpoint)
+	return c.baseUrl + "/" + version + endpoint
 }
 
 // Request performs an HTTP request to the Wallex API.
 //
+// It is equivalent to RequestContext with a background context carrying
+// opts.
+func (c *Client) Request(method string, url string, auth bool, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.RequestContext(callContext(opts), method, url, auth, body, result)
+}
+
+// RequestContext performs an HTTP request to the Wallex API bound to ctx.
+//
 // Capabilities:
-//   - GET: URL-encoded query parameters generated from `body`.
-//   - POST: JSON-encoded request body.
+//   - GET: URL-encoded query parameters generated from `body` (see
+//     utils.EncodeParams).
+//   - POST, PUT, PATCH, DELETE: JSON-encoded request body.
+//   - A *utils.Params or url.Values body is sent as query parameters
+//     with any method, e.g. for DELETE endpoints taking a query.
 //   - Adds X-API-Key header when auth=true.
+//   - Tags the request with a correlation ID (see WithRequestID) sent as
+//     the X-Request-ID header and recorded in returned errors.
+//   - Requests gzip/deflate responses and decodes them, unless
+//     DisableCompression is set.
+//   - Waits on the client RateLimiter, if configured.
+//   - Bounds each attempt by the endpoint timeout from EndpointTimeouts or
+//     WithRequestTimeout, if any.
+//   - Retries transient failures up to MaxRetries times, sleeping according
+//     to the client BackoffPolicy (see isRetryable).
 //   - Parses Wallex-style success/error envelopes.
 //   - Unmarshals successful JSON responses into `result`.
 //
@@ -143,42 +479,142 @@ func (c *Client) createApiURI(endpoint string, version string) string {
 //   - nil on success
 //   - *RequestError for network/JSON failures
 //   - *APIError for Wallex server-side errors
-func (c *Client) Request(method string, url string, auth bool, body interface{}, result interface{}) error {
+//   - an error wrapping ErrReadOnly for non-GET requests of a ReadOnly client
+func (c *Client) RequestContext(ctx context.Context, method string, url string, auth bool, body interface{}, result interface{}) error {
 	var reqBody []byte
 	var err error
 
-	if method == "GET" {
-		if body != nil {
-			urlParams, err := u.StructToURLParams(body)
-			if err != nil {
-				return &RequestError{
-					GoWallexError: GoWallexError{
-						Message: "failed to convert struct to URL params",
-						Err:     err,
-					},
-					Operation: "preparing request parameters",
-				}
+	c.stats.request(c.endpointKey(method, url))
+	if c.ReadOnly && methodHasBody(method) {
+		return c.reportLocal(ctx, method, c.endpointKey(method, url), &GoWallexError{
+			Message: method + " request refused",
+			Err:     ErrReadOnly,
+		})
+	}
+
+	switch {
+	case body == nil:
+	case !methodHasBody(method) || isQueryParams(body):
+		query, err := u.EncodeParams(body)
+		if err != nil {
+			return c.reportLocal(ctx, method, c.endpointKey(method, url), &RequestError{
+				GoWallexError: GoWallexError{
+					Message: "failed to encode URL params",
+					Err:     err,
+				},
+				Operation: "preparing request parameters",
+			})
+		}
+		if len(query) > 0 {
+			sep := "?"
+			if strings.Contains(url, "?") {
+				sep = "&"
 			}
-			url += "?" + urlParams
+			url += sep + query.Encode()
 		}
+	default:
+		buf := getBuffer()
+		defer putBuffer(buf)
+		if err = json.NewEncoder(buf).Encode(body); err != nil {
+			return c.reportLocal(ctx, method, c.endpointKey(method, url), &RequestError{
+				GoWallexError: GoWallexError{
+					Message: "failed to marshal request body",
+					Err:     err,
+				},
+				Operation: "preparing request body",
+			})
+		}
+		reqBody = buf.Bytes()
+	}
+
+	if err := c.hold.wait(ctx); err != nil {
+		return c.reportLocal(ctx, method, c.endpointKey(method, url), &GoWallexError{
+			Message: "request held during outage",
+			Err:     err,
+		})
 	}
 
-	if method == "POST" {
-		if body != nil {
-			reqBody, err = json.Marshal(body)
-			if err != nil {
-				return &RequestError{
-					GoWallexError: GoWallexError{
-						Message: "failed to marshal request body",
-						Err:     err,
-					},
-					Operation: "preparing request body",
-				}
+	// The key is resolved once so that retries, and requests in flight
+	// while the key is rotated, keep the key they started with.
+	key, err := c.apiKey(ctx)
+	if err != nil && auth {
+		return c.reportLocal(ctx, method, c.endpointKey(method, url), &GoWallexError{
+			Message: "failed to obtain API key",
+			Err:     err,
+		})
+	}
+
+	id := requestID(ctx)
+	attempt := 0
+	first := time.Now()
+	var delay time.Duration
+	for {
+		c.stats.attempt(attempt)
+		start := time.Now()
+		var roundTrip time.Duration
+		err = c.doRequest(ctx, method, url, id, auth, key, reqBody, result, &roundTrip)
+		took := time.Since(start)
+		endpoint := c.endpointKey(method, url)
+		if err != nil {
+			err = redactError(annotateError(err, id, endpoint), key)
+		}
+		if roundTrip > 0 {
+			c.recordLatency(endpoint, roundTrip, err)
+		}
+		c.logAttempt(ctx, method, url, id, attempt, took, err)
+		if err == nil {
+			return nil
+		}
+
+		final := attempt >= c.MaxRetries || retryDisabled(ctx) || !isRetryable(method, err)
+		if !final {
+			backoff := c.Backoff
+			if backoff == nil {
+				backoff = DefaultBackoff()
 			}
+			delay = backoff.Next(attempt, delay)
+			final = !c.allowRetry(ctx, endpoint, time.Since(first)+delay)
+		}
+		c.reportError(ctx, ErrorEvent{
+			Err:       err,
+			Method:    method,
+			Endpoint:  endpoint,
+			RequestID: id,
+			Attempt:   attempt,
+			Final:     final,
+			Took:      took,
+		})
+		if final {
+			return err
+		}
+		attempt++
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			c.reportError(ctx, ErrorEvent{Err: err, Method: method, Endpoint: endpoint, RequestID: id, Attempt: attempt - 1, Final: true, Took: took})
+			return err
+		case <-timer.C:
 		}
 	}
+}
+
+// doRequest performs a single HTTP attempt of RequestContext, sending key
+// when auth is set. roundTrip receives the time from sending the request to
+// reading the whole response, or stays zero when nothing was sent.
+func (c *Client) doRequest(ctx context.Context, method string, url string, requestID string, auth bool, key string, reqBody []byte, result interface{}, roundTrip *time.Duration) error {
+	if d := c.attemptTimeout(ctx, method, url); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(reqBody))
+	var bodyReader io.Reader = http.NoBody
+	if len(reqBody) > 0 {
+		bodyReader = bytes.NewReader(reqBody)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
 	if err != nil {
 		return &RequestError{
 			GoWallexError: GoWallexError{
@@ -189,21 +625,55 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 		}
 	}
 
+	if h, ok := ctx.Value(headersKey{}).(http.Header); ok {
+		for k, vs := range h {
+			req.Header[k] = append([]string(nil), vs...)
+		}
+	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(RequestIDHeader, requestID)
+	if !c.DisableCompression {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
 
 	if auth {
-		if err := assertAuth(c); err != nil {
+		if err := assertAuth(key); err != nil {
 			return &GoWallexError{
 				Message: "authentication validation failed",
 				Err:     err,
 			}
 		}
 
-		req.Header.Set("X-API-Key", c.ApiKey)
+		req.Header.Set("X-API-Key", key)
+	}
+
+	var cached condEntry
+	var haveCached bool
+	conditional := c.conditional(method, url, auth)
+	if conditional {
+		cached, haveCached = c.setValidators(req, url)
 	}
 
-	resp, err := c.HttpClient.Do(req)
+	if c.RateLimiter != nil {
+		waitStart := time.Now()
+		err := c.RateLimiter.Wait(ctx)
+		c.stats.rateLimitWait(time.Since(waitStart))
+		if err != nil {
+			return &RequestError{
+				GoWallexError: GoWallexError{
+					Message: "rate limiter wait aborted",
+					Err:     err,
+				},
+				Operation: "waiting for rate limiter",
+			}
+		}
+	}
+
+	c.touch()
+	sent := time.Now()
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		*roundTrip = time.Since(sent)
 		return &RequestError{
 			GoWallexError: GoWallexError{
 				Message: "failed to send request",
@@ -215,8 +685,26 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
+	wire := &countingReader{ReadCloser: resp.Body}
+	resp.Body = wire
+
+	respReader, err := decodeBody(resp)
+	if err != nil {
+		*roundTrip = time.Since(sent)
+		return &RequestError{
+			GoWallexError: GoWallexError{
+				Message: "failed to decompress response body",
+				Err:     err,
+			},
+			Operation: "reading response",
+		}
+	}
 
-	respBody, err := io.ReadAll(resp.Body)
+	buf := getBuffer()
+	defer putBuffer(buf)
+	_, err = buf.ReadFrom(respReader)
+	*roundTrip = time.Since(sent)
+	c.stats.transfer(int64(len(reqBody)), wire.n)
 	if err != nil {
 		return &RequestError{
 			GoWallexError: GoWallexError{
@@ -227,10 +715,28 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 		}
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return parseErrorResponse(resp.StatusCode, respBody)
+	respBody := buf.Bytes()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && haveCached:
+		respBody = cached.body
+		c.metrics().Add("wallex_conditional_hits_total", 1, Label{Name: "endpoint", Value: c.endpointKey(method, url)})
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		apiErr := parseErrorResponse(resp.StatusCode, respBody)
+		if isMaintenance(apiErr) {
+			return &MaintenanceError{APIError: *apiErr, RetryAfter: retryAfter(resp.Header)}
+		}
+		return apiErr
+	case conditional:
+		c.cond.store(url, resp.Header, respBody)
 	}
 
+	if !auth {
+		if err := c.checkResponseHeaders(ctx, c.endpointKey(method, url), resp.Header); err != nil {
+			return err
+		}
+	}
+	c.recordResponse(method, url, auth, respBody)
+
 	if result != nil {
 		if err = json.Unmarshal(respBody, result); err != nil {
 			return &RequestError{
@@ -241,6 +747,7 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 				Operation: "parsing response",
 			}
 		}
+		c.checkSchema(ctx, c.endpointKey(method, url), respBody, result)
 	}
 
 	return nil
@@ -250,16 +757,22 @@ func (c *Client) Request(method string, url string, auth bool, body interface{},
 // then executes the request through Request().
 //
 // It simply forwards:
-//   - method ("GET", "POST", "DELETE")
+//   - method (MethodGet, MethodPost, MethodPut, MethodPatch, MethodDelete)
 //   - endpoint (e.g. "/account/orders")
 //   - version (e.g. "v1")
 //   - auth flag
 //   - body and output result pointer
 //
 // Most Wallex endpoints live under version "v1" unless documented otherwise.
-func (c *Client) ApiRequest(method, endpoint string, version string, auth bool, body interface{}, result interface{}) error {
+func (c *Client) ApiRequest(method, endpoint string, version string, auth bool, body interface{}, result interface{}, opts ...RequestOption) error {
+	return c.ApiRequestContext(callContext(opts), method, endpoint, version, auth, body, result)
+}
+
+// ApiRequestContext is like ApiRequest but executes the request with ctx,
+// allowing cancellation and deadlines to propagate to the HTTP call.
+func (c *Client) ApiRequestContext(ctx context.Context, method, endpoint string, version string, auth bool, body interface{}, result interface{}) error {
 	url := c.createApiURI(endpoint, version)
-	return c.Request(method, url, auth, body, result)
+	return c.RequestContext(ctx, method, url, auth, body, result)
 }
 
 // GetMarketsInfo retrieves metadata for all trading symbols on Wallex.
@@ -277,15 +790,44 @@ func (c *Client) ApiRequest(method, endpoint string, version string, auth bool,
 //
 // Authentication: NOT required.
 // Rate Limit: 100 requests/sec (global Wallex limit).
-func (c *Client) GetMarketsInfo() (*t.MarketInformation, error) {
+func (c *Client) GetMarketsInfo(opts ...RequestOption) (*t.MarketInformation, error) {
+	return c.getMarketsInfo(callContext(opts))
+}
+
+func (c *Client) getMarketsInfo(ctx context.Context) (*t.MarketInformation, error) {
 	var marketInfo *t.MarketInformation
-	err := c.ApiRequest("GET", "/markets", "v1", false, nil, &marketInfo)
+	err := c.ApiRequestContext(ctx, MethodGet, "/markets", "v1", false, nil, &marketInfo)
 	if err != nil {
 		return nil, err
 	}
 	return marketInfo, nil
 }
 
+// GetCurrencyStats retrieves global market statistics (price, market cap,
+// dominance, supply, price changes) for every listed currency.
+//
+// Endpoint:
+//
+//	GET /v1/currencies/stats
+//
+// The payload is large and changes slowly; enable ClientOptions
+// .ConditionalRequests to revalidate it cheaply.
+//
+// Authentication: NOT required.
+// Rate Limit: 100 requests/sec.
+func (c *Client) GetCurrencyStats(opts ...RequestOption) (*t.CurrencyStatsResponse, error) {
+	return c.getCurrencyStats(callContext(opts))
+}
+
+func (c *Client) getCurrencyStats(ctx context.Context) (*t.CurrencyStatsResponse, error) {
+	var stats *t.CurrencyStatsResponse
+	err := c.ApiRequestContext(ctx, MethodGet, "/currencies/stats", "v1", false, nil, &stats)
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
 // GetOrderBook retrieves the current order book for a specific market.
 //
 // Endpoint:
@@ -299,9 +841,18 @@ func (c *Client) GetMarketsInfo() (*t.MarketInformation, error) {
 // Example:
 //
 //	depth, _ := client.GetOrderBook("BTCUSDT")
-func (c *Client) GetOrderBook(symbol string) (*t.Depth, error) {
+func (c *Client) GetOrderBook(symbol string, opts ...RequestOption) (*t.Depth, error) {
+	return c.getOrderBook(callContext(opts), symbol)
+}
+
+func (c *Client) getOrderBook(ctx context.Context, symbol string) (*t.Depth, error) {
+	symbol, err := c.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
 	var depth *t.Depth
-	err := c.ApiRequest("GET", fmt.Sprintf("/depth?symbol=%s", symbol), "v1", false, nil, &depth)
+	err = c.ApiRequestContext(ctx, MethodGet, "/depth", "v1", false, u.NewParams().Set("symbol", symbol), &depth)
 	if err != nil {
 		return nil, err
 	}
@@ -318,11 +869,20 @@ func (c *Client) GetOrderBook(symbol string) (*t.Depth, error) {
 //
 //	result: map[symbol]OrderBook
 //
+// Books are decoded on first access:
+//
+//	books, _ := client.GetAllOrderBooks()
+//	btc, ok := books.Result.Get("BTCUSDT")
+//
 // Authentication: NOT required.
 // Rate Limit: 100 requests/sec (heavy endpoint).
-func (c *Client) GetAllOrderBooks() (*t.AllDepths, error) {
+func (c *Client) GetAllOrderBooks(opts ...RequestOption) (*t.AllDepths, error) {
+	return c.getAllOrderBooks(callContext(opts))
+}
+
+func (c *Client) getAllOrderBooks(ctx context.Context) (*t.AllDepths, error) {
 	var depths *t.AllDepths
-	err := c.ApiRequest("GET", "/depth/all", "v2", false, nil, &depths)
+	err := c.ApiRequestContext(ctx, MethodGet, "/depth/all", "v2", false, nil, &depths)
 	if err != nil {
 		return nil, err
 	}
@@ -344,15 +904,70 @@ func (c *Client) GetAllOrderBooks() (*t.AllDepths, error) {
 //
 // Authentication: NOT required.
 // Rate Limit: 100 requests/sec.
-func (c *Client) GetRecentTrades(symbol string) (*t.Trades, error) {
+func (c *Client) GetRecentTrades(symbol string, opts ...RequestOption) (*t.Trades, error) {
+	return c.getRecentTrades(callContext(opts), symbol)
+}
+
+func (c *Client) getRecentTrades(ctx context.Context, symbol string) (*t.Trades, error) {
+	symbol, err := c.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
 	var trades *t.Trades
-	err := c.ApiRequest("GET", fmt.Sprintf("/trades?symbol=%s", symbol), "v1", false, nil, &trades)
+	err = c.ApiRequestContext(ctx, MethodGet, "/trades", "v1", false, u.NewParams().Set("symbol", symbol), &trades)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.checkFreshness(ctx, EndpointTrades, "trade timestamp", trades.Newest(), time.Now()); err != nil {
+		c.reportError(ctx, ErrorEvent{Err: err, Method: MethodGet, Endpoint: EndpointTrades, Final: true, Local: true})
+		return nil, err
+	}
 	return trades, nil
 }
 
+// GetCandles retrieves OHLCV candles of a market in TradingView UDF format.
+//
+// Endpoint:
+//
+//	GET /v1/udf/history?symbol={SYMBOL}&resolution={RES}&from={UNIX}&to={UNIX}
+//
+// Use CandleHistory.Candles to convert the response into a series, and
+// Candles.Resample / Candles.FillGaps to derive other resolutions.
+//
+// Authentication: NOT required.
+// Rate Limit: 100 requests/sec.
+func (c *Client) GetCandles(params t.CandleParams, opts ...RequestOption) (*t.CandleHistory, error) {
+	return c.getCandles(callContext(opts), params)
+}
+
+func (c *Client) getCandles(ctx context.Context, params t.CandleParams) (*t.CandleHistory, error) {
+	if params.Symbol == "" || params.Resolution == "" {
+		return nil, c.reportLocal(ctx, MethodGet, EndpointCandles, &GoWallexError{
+			Message: "symbol and resolution are required for getting candles",
+			Err:     nil,
+		})
+	}
+	symbol, err := c.resolveSymbol(ctx, params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	params.Symbol = symbol
+
+	var history *t.CandleHistory
+	err = c.ApiRequestContext(ctx, MethodGet, "/udf/history", "v1", false, params, &history)
+	if err != nil {
+		return nil, err
+	}
+	if history.Status == "error" {
+		return nil, c.reportLocal(ctx, MethodGet, EndpointCandles, &GoWallexError{
+			Message: "candle history error: " + history.Message,
+			Err:     nil,
+		})
+	}
+	return history, nil
+}
+
 // GetWallets retrieves the authenticated user's wallet balances.
 //
 // Endpoint:
@@ -363,9 +978,13 @@ func (c *Client) GetRecentTrades(symbol string) (*t.Trades, error) {
 //
 // Authentication: REQUIRED (X-API-Key).
 // Rate Limit: 100 requests/sec.
-func (c *Client) GetWallets() (*t.Wallets, error) {
+func (c *Client) GetWallets(opts ...RequestOption) (*t.Wallets, error) {
+	return c.getWallets(callContext(opts))
+}
+
+func (c *Client) getWallets(ctx context.Context) (*t.Wallets, error) {
 	var wallets *t.Wallets
-	err := c.ApiRequest("GET", "/account/balances", "v1", true, nil, &wallets)
+	err := c.ApiRequestContext(ctx, MethodGet, "/account/balances", "v1", true, nil, &wallets)
 	if err != nil {
 		return nil, err
 	}
@@ -385,14 +1004,44 @@ func (c *Client) GetWallets() (*t.Wallets, error) {
 //   - price     (only for LIMIT)
 //   - quantity
 //
+// If the client has a PreTrade check, it runs first and a rejection (e.g. a
+// *risk.RiskError) is returned without contacting Wallex.
+//
 // Returns:
 //   - BaseOrder with server-evaluated order status.
 //
 // Authentication: REQUIRED.
 // Rate Limit: 100 req/sec.
-func (c *Client) CreateOrder(params t.CreateOrderParams) (*t.BaseOrderResponse, error) {
+func (c *Client) CreateOrder(params t.CreateOrderParams, opts ...RequestOption) (*t.BaseOrderResponse, error) {
+	return c.createOrder(callContext(opts), params)
+}
+
+func (c *Client) createOrder(ctx context.Context, params t.CreateOrderParams) (*t.BaseOrderResponse, error) {
+	opID, err := c.beginOp(inflightOp{op: "CreateOrder", target: params.Symbol, side: params.Side, qty: params.Quantity, price: params.Price})
+	if err != nil {
+		return nil, c.reportLocal(ctx, MethodPost, EndpointCreateOrder, err)
+	}
+	defer c.endOp(opID)
+
+	symbol, err := c.resolveSymbol(ctx, params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	params.Symbol = symbol
+	if params.ClientOrderId != "" {
+		if err := validateClientOrderId(params.ClientOrderId); err != nil {
+			return nil, c.reportLocal(ctx, MethodPost, EndpointCreateOrder, err)
+		}
+	}
+
+	if c.PreTrade != nil {
+		if err := c.PreTrade.Check(ctx, params); err != nil {
+			return nil, c.reportLocal(ctx, MethodPost, EndpointCreateOrder, err)
+		}
+	}
+
 	var orderStatus *t.BaseOrderResponse
-	err := c.ApiRequest("POST", "/account/orders", "v1", true, params, &orderStatus)
+	err = c.ApiRequestContext(ctx, MethodPost, "/account/orders", "v1", true, params, &orderStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -413,9 +1062,23 @@ func (c *Client) CreateOrder(params t.CreateOrderParams) (*t.BaseOrderResponse,
 //
 // If clientOrderId is invalid or order already closed,
 // Wallex returns success=false with an API error.
-func (c *Client) CancelOrder(clientOrderId string) (*t.CancelOrderResponse, error) {
+func (c *Client) CancelOrder(clientOrderId string, opts ...RequestOption) (*t.CancelOrderResponse, error) {
+	return c.cancelOrder(callContext(opts), clientOrderId)
+}
+
+func (c *Client) cancelOrder(ctx context.Context, clientOrderId string) (*t.CancelOrderResponse, error) {
+	opID, err := c.beginOp(inflightOp{op: "CancelOrder", target: clientOrderId})
+	if err != nil {
+		return nil, c.reportLocal(ctx, MethodDelete, EndpointCancelOrder, err)
+	}
+	defer c.endOp(opID)
+
+	if err := validateClientOrderId(clientOrderId); err != nil {
+		return nil, c.reportLocal(ctx, MethodDelete, EndpointCancelOrder, err)
+	}
+
 	var cancelOrderStatus *t.CancelOrderResponse
-	err := c.ApiRequest("DELETE", fmt.Sprintf("/account/orders?clientOrderId=%s", clientOrderId), "v1", true, nil, &cancelOrderStatus)
+	err = c.ApiRequestContext(ctx, MethodDelete, "/account/orders", "v1", true, u.NewParams().Set("clientOrderId", clientOrderId), &cancelOrderStatus)
 	if err != nil {
 		return nil, err
 	}
@@ -429,20 +1092,32 @@ func (c *Client) CancelOrder(clientOrderId string) (*t.CancelOrderResponse, erro
 //	GET /v1/account/openOrders
 //	GET /v1/account/openOrders?symbol={SYMBOL}
 //
+// An empty symbol returns open orders across all markets; see also
+// ListOpenOrders.
+//
 // Returns:
 //   - A list of BaseOrder objects.
 //
 // Authentication: REQUIRED.
 // Rate Limit: 100 req/sec.
-func (c *Client) GetOpenOrders(symbol string) (*t.OpenOrdersResponse, error) {
+func (c *Client) GetOpenOrders(symbol string, opts ...RequestOption) (*t.OpenOrdersResponse, error) {
+	return c.getOpenOrders(callContext(opts), symbol)
+}
+
+func (c *Client) getOpenOrders(ctx context.Context, symbol string) (*t.OpenOrdersResponse, error) {
+	symbol, err := c.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
 	var orders *t.OpenOrdersResponse
 
-	var endPoint = "/account/openOrders"
+	params := u.NewParams()
 	if symbol != "" {
-		endPoint = fmt.Sprintf("%s?symbol=%s", endPoint, symbol)
+		params.Set("symbol", symbol)
 	}
 
-	err := c.ApiRequest("GET", endPoint, "v1", true, nil, &orders)
+	err = c.ApiRequestContext(ctx, MethodGet, "/account/openOrders", "v1", true, params, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -468,16 +1143,24 @@ func (c *Client) GetOpenOrders(symbol string) (*t.OpenOrdersResponse, error) {
 // Errors:
 //   - Missing or invalid clientOrderId
 //   - Order does not belong to this API key
-func (c *Client) GetOrderStatus(clientOrderId string) (*t.BaseOrderResponse, error) {
+func (c *Client) GetOrderStatus(clientOrderId string, opts ...RequestOption) (*t.BaseOrderResponse, error) {
+	return c.getOrderStatus(callContext(opts), clientOrderId)
+}
+
+func (c *Client) getOrderStatus(ctx context.Context, clientOrderId string) (*t.BaseOrderResponse, error) {
 	var orders *t.BaseOrderResponse
 	if clientOrderId == "" {
-		return nil, &GoWallexError{
+		return nil, c.reportLocal(ctx, MethodGet, EndpointOrderStatus, &GoWallexError{
 			Message: "client order id is required for getting order status",
 			Err:     nil,
-		}
+		})
+	}
+
+	if err := validateClientOrderId(clientOrderId); err != nil {
+		return nil, c.reportLocal(ctx, MethodGet, EndpointOrderStatus, err)
 	}
 
-	err := c.ApiRequest("GET", fmt.Sprintf("/account/orders/%s", clientOrderId), "v1", true, nil, &orders)
+	err := c.ApiRequestContext(ctx, MethodGet, "/account/orders/"+url.PathEscape(clientOrderId), "v1", true, nil, &orders)
 	if err != nil {
 		return nil, err
 	}
@@ -502,11 +1185,246 @@ func (c *Client) GetOrderStatus(clientOrderId string) (*t.BaseOrderResponse, err
 //
 // Authentication: REQUIRED.
 // Rate Limit: 100 req/sec.
-func (c *Client) GetUserTrades(params t.UserTradesParams) (*t.UserTradesResponse, error) {
+func (c *Client) GetUserTrades(params t.UserTradesParams, opts ...RequestOption) (*t.UserTradesResponse, error) {
+	return c.getUserTrades(callContext(opts), params)
+}
+
+func (c *Client) getUserTrades(ctx context.Context, params t.UserTradesParams) (*t.UserTradesResponse, error) {
+	symbol, err := c.resolveSymbol(ctx, params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	params.Symbol = symbol
+
 	var trades *t.UserTradesResponse
-	err := c.ApiRequest("GET", "/account/trades", "v1", true, params, &trades)
+	err = c.ApiRequestContext(ctx, MethodGet, "/account/trades", "v1", true, params, &trades)
 	if err != nil {
 		return nil, err
 	}
 	return trades, nil
 }
+
+// GetOrderHistory retrieves a page of the account's order history,
+// including filled and canceled orders.
+//
+// Endpoint:
+//
+//	GET /v1/account/orders/history
+//
+// Optional filters:
+//   - symbol
+//   - side ("BUY" / "SELL")
+//   - page, per_page
+//
+// Authentication: REQUIRED.
+// Rate Limit: 100 req/sec.
+func (c *Client) GetOrderHistory(params t.OrderHistoryParams, opts ...RequestOption) (*t.OrderHistoryResponse, error) {
+	return c.getOrderHistory(callContext(opts), params)
+}
+
+func (c *Client) getOrderHistory(ctx context.Context, params t.OrderHistoryParams) (*t.OrderHistoryResponse, error) {
+	symbol, err := c.resolveSymbol(ctx, params.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	params.Symbol = symbol
+
+	var orders *t.OrderHistoryResponse
+	err = c.ApiRequestContext(ctx, MethodGet, "/account/orders/history", "v1", true, params, &orders)
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// GetAssetNetworks retrieves the networks an asset can be deposited and
+// withdrawn on, including minimum withdrawal amounts and fees.
+//
+// Endpoint:
+//
+//	GET /v1/account/networks?asset={ASSET}
+//
+// Withdrawal automation should validate amounts with
+// AssetNetwork.CheckWithdrawal before submitting.
+//
+// Authentication: REQUIRED.
+// Rate Limit: 100 req/sec.
+func (c *Client) GetAssetNetworks(asset string, opts ...RequestOption) (*t.AssetNetworksResponse, error) {
+	return c.getAssetNetworks(callContext(opts), asset)
+}
+
+func (c *Client) getAssetNetworks(ctx context.Context, asset string) (*t.AssetNetworksResponse, error) {
+	if asset == "" {
+		return nil, c.reportLocal(ctx, MethodGet, EndpointNetworks, &GoWallexError{
+			Message: "asset is required for getting asset networks",
+			Err:     nil,
+		})
+	}
+
+	if err := validateSymbol("asset", asset); err != nil {
+		return nil, c.reportLocal(ctx, MethodGet, EndpointNetworks, err)
+	}
+
+	var networks *t.AssetNetworksResponse
+	err := c.ApiRequestContext(ctx, MethodGet, "/account/networks", "v1", true, u.NewParams().Set("asset", asset), &networks)
+	if err != nil {
+		return nil, err
+	}
+	return networks, nil
+}
+
+// GetAccountFees retrieves the account's maker and taker fee rates in
+// every market, which depend on its recent trading volume.
+//
+// Endpoint:
+//
+//	GET /v1/account/fee
+//
+// Use FeeBreakeven to derive the spread a round trip needs to be
+// profitable.
+//
+// Authentication: REQUIRED.
+// Rate Limit: 100 req/sec.
+func (c *Client) GetAccountFees(opts ...RequestOption) (*t.AccountFeesResponse, error) {
+	return c.getAccountFees(callContext(opts))
+}
+
+func (c *Client) getAccountFees(ctx context.Context) (*t.AccountFeesResponse, error) {
+	var fees *t.AccountFeesResponse
+	err := c.ApiRequestContext(ctx, MethodGet, "/account/fee", "v1", true, nil, &fees)
+	if err != nil {
+		return nil, err
+	}
+	return fees, nil
+}
+
+// WithdrawFiat requests a Toman withdrawal to a registered IBAN.
+//
+// Endpoint:
+//
+//	POST /v1/account/money-withdrawal
+//
+// The returned transfer usually starts in status PENDING; track it with
+// GetFiatWithdrawals.
+//
+// Authentication: REQUIRED (withdrawal permission).
+// Rate Limit: 100 req/sec.
+func (c *Client) WithdrawFiat(params t.FiatWithdrawalParams, opts ...RequestOption) (*t.FiatWithdrawalResponse, error) {
+	return c.withdrawFiat(callContext(opts), params)
+}
+
+func (c *Client) withdrawFiat(ctx context.Context, params t.FiatWithdrawalParams) (*t.FiatWithdrawalResponse, error) {
+	if params.Iban == "" || params.Value == "" {
+		return nil, c.reportLocal(ctx, MethodPost, EndpointWithdrawFiat, &GoWallexError{
+			Message: "iban and value are required for fiat withdrawal",
+			Err:     nil,
+		})
+	}
+
+	var withdrawal *t.FiatWithdrawalResponse
+	err := c.ApiRequestContext(ctx, MethodPost, "/account/money-withdrawal", "v1", true, params, &withdrawal)
+	if err != nil {
+		return nil, err
+	}
+	return withdrawal, nil
+}
+
+// GetFiatDeposits retrieves a page of the user's Toman deposit history.
+//
+// Endpoint:
+//
+//	GET /v1/account/money-deposit
+//
+// Authentication: REQUIRED.
+// Rate Limit: 100 req/sec.
+func (c *Client) GetFiatDeposits(params t.HistoryParams, opts ...RequestOption) (*t.FiatHistoryResponse, error) {
+	return c.getFiatHistory(callContext(opts), "/account/money-deposit", params)
+}
+
+// GetFiatWithdrawals retrieves a page of the user's Toman withdrawal history.
+//
+// Endpoint:
+//
+//	GET /v1/account/money-withdrawal
+//
+// Authentication: REQUIRED.
+// Rate Limit: 100 req/sec.
+func (c *Client) GetFiatWithdrawals(params t.HistoryParams, opts ...RequestOption) (*t.FiatHistoryResponse, error) {
+	return c.getFiatHistory(callContext(opts), "/account/money-withdrawal", params)
+}
+
+// GetCryptoDeposits retrieves a page of the user's on-chain deposit history,
+// optionally filtered by asset.
+//
+// Endpoint:
+//
+//	GET /v1/account/crypto-deposit
+//
+// Authentication: REQUIRED.
+// Rate Limit: 100 req/sec.
+func (c *Client) GetCryptoDeposits(params t.CryptoHistoryParams, opts ...RequestOption) (*t.CryptoHistoryResponse, error) {
+	return c.getCryptoHistory(callContext(opts), "/account/crypto-deposit", params)
+}
+
+// WithdrawCrypto requests an on-chain withdrawal.
+//
+// Endpoint:
+//
+//	POST /v1/account/crypto-withdrawal
+//
+// The returned transfer usually starts in status PENDING; track it with
+// GetCryptoWithdrawals or TrackWithdrawal. Validate the amount with
+// AssetNetwork.CheckWithdrawal first, or use SubmitWithdrawal.
+//
+// Authentication: REQUIRED (withdrawal permission).
+// Rate Limit: 100 req/sec.
+func (c *Client) WithdrawCrypto(params t.CryptoWithdrawalParams, opts ...RequestOption) (*t.CryptoWithdrawalResponse, error) {
+	return c.withdrawCrypto(callContext(opts), params)
+}
+
+func (c *Client) withdrawCrypto(ctx context.Context, params t.CryptoWithdrawalParams) (*t.CryptoWithdrawalResponse, error) {
+	if params.Asset == "" || params.Network == "" || params.Address == "" || params.Amount == "" {
+		return nil, c.reportLocal(ctx, MethodPost, EndpointCryptoWithdraw, &GoWallexError{
+			Message: "asset, network, address and amount are required for crypto withdrawal",
+			Err:     nil,
+		})
+	}
+
+	var withdrawal *t.CryptoWithdrawalResponse
+	err := c.ApiRequestContext(ctx, MethodPost, "/account/crypto-withdrawal", "v1", true, params, &withdrawal)
+	if err != nil {
+		return nil, err
+	}
+	return withdrawal, nil
+}
+
+// GetCryptoWithdrawals retrieves a page of the user's on-chain withdrawal
+// history, optionally filtered by asset.
+//
+// Endpoint:
+//
+//	GET /v1/account/crypto-withdrawal
+//
+// Authentication: REQUIRED.
+// Rate Limit: 100 req/sec.
+func (c *Client) GetCryptoWithdrawals(params t.CryptoHistoryParams, opts ...RequestOption) (*t.CryptoHistoryResponse, error) {
+	return c.getCryptoHistory(callContext(opts), "/account/crypto-withdrawal", params)
+}
+
+func (c *Client) getCryptoHistory(ctx context.Context, endpoint string, params t.CryptoHistoryParams) (*t.CryptoHistoryResponse, error) {
+	var history *t.CryptoHistoryResponse
+	err := c.ApiRequestContext(ctx, MethodGet, endpoint, "v1", true, params, &history)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+func (c *Client) getFiatHistory(ctx context.Context, endpoint string, params t.HistoryParams) (*t.FiatHistoryResponse, error) {
+	var history *t.FiatHistoryResponse
+	err := c.ApiRequestContext(ctx, MethodGet, endpoint, "v1", true, params, &history)
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
@@ -0,0 +1,113 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// ErrReadOnly is wrapped by the error a ReadOnly client returns instead of
+// sending a request that could change account state.
+var ErrReadOnly = errors.New("wallex: client is read-only")
+
+// Option overrides a setting of a client created by Client.With.
+type Option func(c *Client)
+
+// With returns a copy of the client with opts applied, for giving a
+// subsystem scoped behavior, e.g. a read-only client for a dashboard:
+//
+//	reports := client.With(wallex.WithReadOnly(true), wallex.WithLogger(reportLog))
+//
+// The copy shares the HTTP transport, rate limiter, retry budget, caches,
+// statistics and lifecycle with c: closing either closes both. Changing
+// the exported fields of one does not affect the other.
+func (c *Client) With(opts ...Option) *Client {
+	clone := *c
+	if c.EndpointTimeouts != nil {
+		clone.EndpointTimeouts = make(map[string]time.Duration, len(c.EndpointTimeouts))
+		for k, v := range c.EndpointTimeouts {
+			clone.EndpointTimeouts[k] = v
+		}
+	}
+	for _, opt := range opts {
+		if opt != nil {
+			opt(&clone)
+		}
+	}
+	return &clone
+}
+
+// WithReadOnly sets Client.ReadOnly.
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Client) { c.ReadOnly = readOnly }
+}
+
+// WithHTTPTimeout sets the timeout of every HTTP attempt, like
+// ClientOptions.Timeout. The copy keeps using the same transport and
+// connection pool.
+func WithHTTPTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		var hc http.Client
+		if c.httpClient != nil {
+			hc = *c.httpClient
+		}
+		hc.Timeout = d
+		c.httpClient = &hc
+	}
+}
+
+// WithHTTPClient makes the copy send its requests through hc, e.g. one
+// with a transport dedicated to a subsystem.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithApiKey makes the copy authenticate with key, e.g. to act on a
+// sub-account through the same transport and rate limiter. The copy's key
+// is rotated independently of c's.
+func WithApiKey(key string) Option {
+	return func(c *Client) { c.keys = NewRotatingKey(key) }
+}
+
+// WithEndpointTimeout sets the attempt timeout of one endpoint, e.g.
+// EndpointDepth; see ClientOptions.EndpointTimeouts.
+func WithEndpointTimeout(endpoint string, d time.Duration) Option {
+	return func(c *Client) {
+		if c.EndpointTimeouts == nil {
+			c.EndpointTimeouts = make(map[string]time.Duration)
+		}
+		c.EndpointTimeouts[endpoint] = d
+	}
+}
+
+// WithMaxRetries sets Client.MaxRetries.
+func WithMaxRetries(n int) Option {
+	return func(c *Client) { c.MaxRetries = n }
+}
+
+// WithLogger sets Client.Logger; nil disables logging.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) { c.Logger = l }
+}
+
+// WithMetrics sets Client.Metrics.
+func WithMetrics(m Metrics) Option {
+	return func(c *Client) { c.Metrics = m }
+}
+
+// WithOnError sets Client.OnError.
+func WithOnError(fn func(ctx context.Context, ev ErrorEvent)) Option {
+	return func(c *Client) { c.OnError = fn }
+}
+
+// WithRecorder sets Client.Recorder; nil disables recording.
+func WithRecorder(r MarketRecorder) Option {
+	return func(c *Client) { c.Recorder = r }
+}
+
+// WithPreTrade sets Client.PreTrade.
+func WithPreTrade(check PreTradeCheck) Option {
+	return func(c *Client) { c.PreTrade = check }
+}
@@ -0,0 +1,43 @@
+package wallex
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is sent with every request unless compression is disabled.
+// Setting the header explicitly turns off net/http's transparent gzip
+// handling, so responses are always decoded by decodeBody. This also covers
+// custom transports with DisableCompression set.
+const acceptEncoding = "gzip, deflate"
+
+// decodeBody wraps the response body in a decompressor matching its
+// Content-Encoding. Unknown or missing encodings are returned unchanged.
+func decodeBody(resp *http.Response) (io.Reader, error) {
+	enc := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	switch enc {
+	case "gzip", "x-gzip":
+		return gzip.NewReader(resp.Body)
+	case "deflate":
+		// "deflate" is specified as zlib-wrapped, but some servers send raw
+		// DEFLATE streams. The zlib header is detected by peeking.
+		br := bufio.NewReader(resp.Body)
+		hdr, err := br.Peek(2)
+		if err == nil && isZlibHeader(hdr) {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return resp.Body, nil
+	}
+}
+
+// isZlibHeader reports whether b starts with a valid zlib header (RFC 1950).
+func isZlibHeader(b []byte) bool {
+	return b[0]&0x0f == 8 && (uint16(b[0])<<8|uint16(b[1]))%31 == 0
+}
@@ -0,0 +1,169 @@
+package wallex
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-wallex/fixtures"
+	"github.com/darhelm/go-wallex/types"
+)
+
+// fixtureServer serves the bundled fixtures for the endpoints the
+// concurrency test exercises.
+func fixtureServer(tb testing.TB) *httptest.Server {
+	tb.Helper()
+	routes := map[string]string{
+		"/v1/markets":              fixtures.Markets,
+		"/v1/depth":                fixtures.Depth,
+		"/v1/trades":               fixtures.Trades,
+		"/v1/account/balances":     fixtures.Wallets,
+		"/v1/account/openOrders":   fixtures.OpenOrders,
+		"/v1/account/trades":       fixtures.UserTrades,
+		"/v1/account/orders/probe": fixtures.OrderStatus,
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, ok := routes[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("ETag", `"`+name+`"`)
+		_, _ = w.Write(fixtures.MustLoad(name))
+	}))
+	tb.Cleanup(srv.Close)
+	return srv
+}
+
+// TestConcurrentUse drives a client, its clones and the stateful components
+// built on it from many goroutines. Run it with -race.
+func TestConcurrentUse(t *testing.T) {
+	srv := fixtureServer(t)
+	client, err := NewClient(ClientOptions{
+		BaseUrl:             srv.URL,
+		ApiKey:              "key",
+		ValidateSymbols:     true,
+		ConditionalRequests: true,
+		DetectSchemaDrift:   true,
+		MaxRetries:          1,
+		RateLimiter:         NewRateLimiter(10000, 100),
+		Logger:              slog.New(slog.NewTextHandler(io.Discard, nil)),
+		OnError:             func(context.Context, ErrorEvent) {},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cache := NewMarketDataCache(client, MarketDataOptions{Interval: time.Millisecond})
+	updates, unsubscribe := cache.Subscribe("BTCUSDT", 1)
+	defer unsubscribe()
+	go cache.Run(ctx, nil)
+
+	tape := NewTradeTape(client, TradeTapeOptions{Symbols: []string{"BTCUSDT"}, Interval: time.Millisecond})
+	client.Register(tape)
+	go tape.Run(ctx)
+
+	tracker := NewOrderTracker()
+	tracker.OnTransition(func(OrderTransition) {})
+	stats := NewTradeStatsEngine(time.Minute)
+	detector := NewAnomalyDetector(AnomalyOptions{})
+	refs := NewReferencePrices()
+	go stats.Consume(ctx, detector.ObserveTape(tape.Trades()))
+	cursors := NewMemoryCursorStore()
+
+	const workers = 8
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c := client
+			if i%2 == 1 {
+				c = client.With(WithReadOnly(true), WithEndpointTimeout(EndpointDepth, time.Second))
+			}
+			for j := 0; j < 20; j++ {
+				if _, err := c.GetMarketsInfo(WithTimeout(5 * time.Second)); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := c.GetOrderBook("btc-usdt", WithTimeout(5*time.Second)); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := c.GetWallets(WithTimeout(5 * time.Second)); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := c.GetOrderStatus("probe", WithTimeout(5*time.Second)); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := cache.Fetch(ctx, "BTCUSDT", time.Millisecond); err != nil {
+					t.Error(err)
+					return
+				}
+				cache.LastQuote("BTCUSDT")
+				_, _ = cache.PriceSource(PriceMark).Price(ctx, "BTCUSDT")
+
+				trades, err := c.GetRecentTrades("BTCUSDT", WithTimeout(5*time.Second))
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				for _, tr := range trades.Result.LatestTrades {
+					stats.Add(tr)
+					detector.Add(tr)
+				}
+				stats.Snapshot("BTCUSDT", time.Minute)
+				detector.Anomalous()
+
+				status := "NEW"
+				if j%2 == 1 {
+					status = "PARTIALLY_FILLED"
+				}
+				tracker.Update(types.BaseOrder{ClientOrderId: "order", Symbol: "BTCUSDT", Status: status})
+				tracker.Active()
+				tracker.State("order")
+
+				refs.Set("BTCUSDT", 63000+float64(j), time.Now())
+				_, _, _ = refs.ReferencePrice(ctx, "BTCUSDT")
+				_ = cursors.SaveCursor(ctx, "key", TradeCursor{Timestamp: time.Now()})
+				_, _ = cursors.LoadCursor(ctx, "key")
+
+				if i == 0 && j%5 == 0 {
+					_ = client.SetApiKey(ctx, "key")
+					client.symbols.Invalidate()
+					client.ResetLatency()
+				}
+				c.Latency()
+				c.Stats()
+				c.SchemaDrift()
+				c.Capabilities()
+				_ = c.String()
+			}
+		}(i)
+	}
+	go func() {
+		for range updates {
+		}
+	}()
+	wg.Wait()
+
+	cancel()
+	if err := client.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Close(context.Background()); err != nil && !strings.Contains(err.Error(), "closed") {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,74 @@
+package wallex
+
+import (
+	"net/http"
+	"sync"
+)
+
+// conditionalEndpoints are the large, slowly changing endpoints for which
+// conditional GETs are issued when Client.ConditionalRequests is set.
+var conditionalEndpoints = map[string]bool{
+	EndpointMarkets:       true,
+	EndpointCurrencyStats: true,
+}
+
+// condEntry is the last full response of a conditional endpoint together
+// with its validators.
+type condEntry struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// condCache stores validators and bodies per request URL.
+type condCache struct {
+	mu      sync.Mutex
+	entries map[string]condEntry
+}
+
+func (cc *condCache) get(url string) (condEntry, bool) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	e, ok := cc.entries[url]
+	return e, ok
+}
+
+// store remembers body if the response carries a validator. body is
+// copied, since the caller's buffer is pooled.
+func (cc *condCache) store(url string, h http.Header, body []byte) {
+	etag, lastModified := h.Get("ETag"), h.Get("Last-Modified")
+	if etag == "" && lastModified == "" {
+		return
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if cc.entries == nil {
+		cc.entries = make(map[string]condEntry)
+	}
+	cc.entries[url] = condEntry{
+		etag:         etag,
+		lastModified: lastModified,
+		body:         append([]byte(nil), body...),
+	}
+}
+
+// conditional reports whether a request may be sent conditionally.
+func (c *Client) conditional(method, url string, auth bool) bool {
+	return c.ConditionalRequests && method == http.MethodGet && !auth && conditionalEndpoints[c.endpointKey(method, url)]
+}
+
+// setValidators adds If-None-Match / If-Modified-Since from the cached
+// response of url, and returns the cached entry.
+func (c *Client) setValidators(req *http.Request, url string) (condEntry, bool) {
+	e, ok := c.cond.get(url)
+	if !ok {
+		return e, false
+	}
+	if e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		req.Header.Set("If-Modified-Since", e.lastModified)
+	}
+	return e, true
+}
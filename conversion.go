@@ -0,0 +1,242 @@
+package wallex
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// ConversionStep is one market hop of a ConversionRoute.
+type ConversionStep struct {
+	// Symbol is the market used for this hop.
+	Symbol string
+
+	// From and To are the assets converted from and into.
+	From string
+	To   string
+
+	// Inverted is true when the hop sells the quote asset for the base
+	// asset, i.e. the market price is inverted.
+	Inverted bool
+
+	// Rate is the amount of To received per unit of From.
+	Rate float64
+}
+
+// ConversionRoute describes how an implied rate between two assets was
+// derived.
+type ConversionRoute struct {
+	From  string
+	To    string
+	Steps []ConversionStep
+
+	// Rate is the product of all step rates: units of To per unit of From.
+	Rate float64
+
+	// Liquidity is the smallest 24h quote volume among the route's markets,
+	// valued in TMN. Routes are ranked by it.
+	Liquidity float64
+}
+
+// ImpliedRate computes the price of from expressed in to using the current
+// market statistics, e.g. ImpliedRate(markets, "ETH", "BTC").
+//
+// A direct market is used when one exists. Otherwise every two-hop route
+// through a shared asset (typically TMN or USDT) is considered and the most
+// liquid one is selected. Prices are the bid/ask mid-point, falling back to
+// the last traded price.
+func ImpliedRate(markets *t.MarketInformation, from, to string) (*ConversionRoute, error) {
+	if from == to {
+		return &ConversionRoute{From: from, To: to, Rate: 1}, nil
+	}
+
+	g := newMarketGraph(markets)
+	routes := g.routes(from, to)
+	if len(routes) == 0 {
+		return nil, &GoWallexError{
+			Message: "no conversion route from " + from + " to " + to,
+			Err:     nil,
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if len(routes[i].Steps) != len(routes[j].Steps) {
+			return len(routes[i].Steps) < len(routes[j].Steps)
+		}
+		return routes[i].Liquidity > routes[j].Liquidity
+	})
+	return &routes[0], nil
+}
+
+type marketEdge struct {
+	info   t.SymbolInfo
+	price  float64
+	volume float64 // 24h quote volume in TMN
+}
+
+type marketGraph struct {
+	edges map[string][]marketEdge // keyed by asset, both directions
+}
+
+func newMarketGraph(markets *t.MarketInformation) *marketGraph {
+	g := &marketGraph{edges: make(map[string][]marketEdge)}
+
+	usdtTmn := 0.0
+	if info, ok := markets.Get("USDTTMN"); ok {
+		usdtTmn = marketPrice(info)
+	}
+
+	for _, info := range markets.AllSymbols() {
+		price := marketPrice(info)
+		if price <= 0 {
+			continue
+		}
+
+		volume := info.Stats.QuoteVolumeDayFloat()
+		if info.QuoteAsset == "USDT" {
+			volume *= usdtTmn
+		}
+
+		e := marketEdge{info: info, price: price, volume: volume}
+		g.edges[info.BaseAsset] = append(g.edges[info.BaseAsset], e)
+		g.edges[info.QuoteAsset] = append(g.edges[info.QuoteAsset], e)
+	}
+	return g
+}
+
+// step converts from asset over edge e, returning the step and the asset
+// reached.
+func (e marketEdge) step(from string) ConversionStep {
+	if e.info.BaseAsset == from {
+		return ConversionStep{Symbol: e.info.Symbol, From: from, To: e.info.QuoteAsset, Rate: e.price}
+	}
+	return ConversionStep{Symbol: e.info.Symbol, From: from, To: e.info.BaseAsset, Inverted: true, Rate: 1 / e.price}
+}
+
+func (g *marketGraph) routes(from, to string) []ConversionRoute {
+	var out []ConversionRoute
+
+	for _, first := range g.edges[from] {
+		s1 := first.step(from)
+		if s1.To == to {
+			out = append(out, ConversionRoute{
+				From: from, To: to,
+				Steps:     []ConversionStep{s1},
+				Rate:      s1.Rate,
+				Liquidity: first.volume,
+			})
+			continue
+		}
+
+		for _, second := range g.edges[s1.To] {
+			if second.info.Symbol == first.info.Symbol {
+				continue
+			}
+			s2 := second.step(s1.To)
+			if s2.To != to {
+				continue
+			}
+			out = append(out, ConversionRoute{
+				From: from, To: to,
+				Steps:     []ConversionStep{s1, s2},
+				Rate:      s1.Rate * s2.Rate,
+				Liquidity: min(first.volume, second.volume),
+			})
+		}
+	}
+	return out
+}
+
+// marketPrice returns the mid-price of a market, or its last price when the
+// book side prices are unavailable.
+func marketPrice(info t.SymbolInfo) float64 {
+	if mid := info.Stats.MidPrice(); mid > 0 {
+		return mid
+	}
+	return info.Stats.LastPriceFloat()
+}
+
+// DefaultConversionTTL is how long a Converter reuses fetched market prices.
+const DefaultConversionTTL = 15 * time.Second
+
+// Converter converts amounts between assets using live Wallex prices.
+//
+// Market information is fetched on demand and cached for the configured
+// TTL; resolved routes are cached alongside it and discarded on refresh.
+// Converter is safe for concurrent use.
+type Converter struct {
+	client *Client
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	markets   *t.MarketInformation
+	routes    map[[2]string]*ConversionRoute
+	fetchedAt time.Time
+}
+
+// NewConverter creates a Converter. A ttl <= 0 uses DefaultConversionTTL.
+func NewConverter(client *Client, ttl time.Duration) *Converter {
+	if ttl <= 0 {
+		ttl = DefaultConversionTTL
+	}
+	return &Converter{client: client, ttl: ttl}
+}
+
+// Convert converts amount of from into to, e.g.
+// Convert(ctx, 0.5, "BTC", "TMN").
+//
+// The result is rounded down to the precision Wallex uses for the target
+// asset in the final market of the route. The route used is returned for
+// display or auditing.
+func (cv *Converter) Convert(ctx context.Context, amount float64, from, to string) (float64, *ConversionRoute, error) {
+	route, markets, err := cv.Route(ctx, from, to)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	value := amount * route.Rate
+	if len(route.Steps) > 0 {
+		last := route.Steps[len(route.Steps)-1]
+		if info, ok := markets.Get(last.Symbol); ok {
+			precision := info.QuotePrecision
+			if last.Inverted {
+				precision = info.BaseAssetPrecision
+			}
+			value = u.RoundDown(value, int(precision))
+		}
+	}
+	return value, route, nil
+}
+
+// Route returns the cached conversion route between two assets, refreshing
+// market prices when the cache has expired.
+func (cv *Converter) Route(ctx context.Context, from, to string) (*ConversionRoute, *t.MarketInformation, error) {
+	cv.mu.Lock()
+	defer cv.mu.Unlock()
+
+	if cv.markets == nil || time.Since(cv.fetchedAt) >= cv.ttl {
+		markets, err := cv.client.getMarketsInfo(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		cv.markets = markets
+		cv.routes = make(map[[2]string]*ConversionRoute)
+		cv.fetchedAt = time.Now()
+	}
+
+	key := [2]string{from, to}
+	if route, ok := cv.routes[key]; ok {
+		return route, cv.markets, nil
+	}
+
+	route, err := ImpliedRate(cv.markets, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	cv.routes[key] = route
+	return route, cv.markets, nil
+}
@@ -0,0 +1,122 @@
+package wallex
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// DefaultDepositPollInterval is the polling interval of WaitForDeposit.
+const DefaultDepositPollInterval = 15 * time.Second
+
+// DepositWaitOptions tunes WaitForDepositWithOptions.
+type DepositWaitOptions struct {
+	// Interval between polls of the deposit history. Defaults to
+	// DefaultDepositPollInterval.
+	Interval time.Duration
+
+	// Network, if set, only accepts deposits on that network, e.g. "TRC20".
+	Network string
+
+	// Since only accepts deposits created at or after Since. Zero accepts
+	// any deposit that was not yet confirmed when waiting started.
+	Since time.Time
+
+	// OnProgress is called whenever a matching deposit that is not yet
+	// confirmed appears or gains confirmations, so funding workflows can
+	// report "1/2 confirmations".
+	OnProgress func(t.CryptoTransfer)
+}
+
+// WaitForDeposit blocks until a new confirmed deposit of at least minAmount
+// of asset shows up in GET /v1/account/crypto-deposit, and returns it.
+//
+// Deposits already confirmed when WaitForDeposit is called are ignored, so
+// the same deposit is never returned for two consecutive waits; deposits
+// still confirming at that time do count once they are confirmed. Rejected
+// and canceled deposits are skipped. Request errors are not fatal: polling
+// continues until ctx is done, in which case the last request error, or
+// ctx.Err(), is returned.
+//
+// Authentication: REQUIRED.
+func (c *Client) WaitForDeposit(ctx context.Context, asset string, minAmount string) (*t.CryptoTransfer, error) {
+	return c.WaitForDepositWithOptions(ctx, asset, minAmount, DepositWaitOptions{})
+}
+
+// WaitForDepositWithOptions is WaitForDeposit with explicit options.
+func (c *Client) WaitForDepositWithOptions(ctx context.Context, asset string, minAmount string, opts DepositWaitOptions) (*t.CryptoTransfer, error) {
+	minimum, err := strconv.ParseFloat(minAmount, 64)
+	if err != nil {
+		return nil, &GoWallexError{
+			Message: "invalid minimum deposit amount " + strconv.Quote(minAmount),
+			Err:     err,
+		}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultDepositPollInterval
+	}
+
+	params := t.CryptoHistoryParams{Asset: asset}
+	matches := func(d t.CryptoTransfer) bool {
+		return d.Asset == asset &&
+			(opts.Network == "" || d.Network == opts.Network) &&
+			(opts.Since.IsZero() || !d.CreatedAt.Before(opts.Since)) &&
+			d.Amount.Float() >= minimum
+	}
+
+	// seen holds confirmed deposits present at start; progress the last
+	// reported confirmation count of pending ones.
+	var seen map[int64]bool
+	progress := make(map[int64]int)
+
+	var lastErr error
+	for {
+		history, err := c.getCryptoHistory(ctx, "/account/crypto-deposit", params)
+		switch {
+		case err != nil:
+			lastErr = err
+		case seen == nil:
+			seen = make(map[int64]bool)
+			for _, d := range history.Result {
+				if d.Status == t.CryptoStatusDone && opts.Since.IsZero() {
+					seen[d.ID] = true
+				}
+			}
+			fallthrough
+		default:
+			lastErr = nil
+			for _, d := range history.Result {
+				if seen[d.ID] || !matches(d) {
+					continue
+				}
+				switch {
+				case d.Status == t.CryptoStatusDone:
+					return &d, nil
+				case d.Status.IsFinal():
+					seen[d.ID] = true
+				default:
+					if n, ok := progress[d.ID]; (!ok || n != d.Confirmations) && opts.OnProgress != nil {
+						opts.OnProgress(d)
+					}
+					progress[d.ID] = d.Confirmations
+				}
+			}
+		}
+
+		timer := time.NewTimer(opts.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, ctx.Err()
+		case <-c.Done():
+			timer.Stop()
+			return nil, ErrClientClosed
+		case <-timer.C:
+		}
+	}
+}
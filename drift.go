@@ -0,0 +1,280 @@
+package wallex
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"log/slog"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDriftLogInterval is the minimum time between two schema drift
+// log records of the same endpoint.
+const DefaultDriftLogInterval = time.Minute
+
+// DriftKind classifies a difference between a response and the type it is
+// decoded into.
+type DriftKind string
+
+const (
+	// DriftUnknown is a JSON field the Go type has no field for; its value
+	// is discarded.
+	DriftUnknown DriftKind = "unknown"
+
+	// DriftMissing is a field of the Go type absent from the JSON; it is
+	// left at its zero value.
+	DriftMissing DriftKind = "missing"
+)
+
+// SchemaDrift aggregates the occurrences of one drifted field.
+type SchemaDrift struct {
+	// Endpoint is the endpoint key, e.g. EndpointMarkets.
+	Endpoint string
+
+	// Path locates the field in the response, e.g. "result.symbols.*.fairPrice";
+	// "*" stands for any map key and "[]" for any array element.
+	Path string
+
+	Kind      DriftKind
+	Count     uint64
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+type driftKey struct {
+	endpoint string
+	path     string
+	kind     DriftKind
+}
+
+// driftRecorder aggregates schema drift per endpoint and field. Its zero
+// value is ready to use.
+type driftRecorder struct {
+	mu      sync.Mutex
+	fields  map[driftKey]*SchemaDrift
+	lastLog map[string]time.Time
+	pending map[string][]string
+}
+
+// checkSchema compares the raw response body of endpoint with the type of
+// result when DetectSchemaDrift is enabled. Newly seen drift is counted in
+// wallex_schema_drift_total and logged at warn level, at most once per
+// endpoint every DefaultDriftLogInterval.
+func (c *Client) checkSchema(ctx context.Context, endpoint string, body []byte, result interface{}) {
+	if !c.DetectSchemaDrift || result == nil {
+		return
+	}
+	var raw interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return
+	}
+	var found []driftKey
+	compareSchema(raw, reflect.TypeOf(result), "", func(path string, kind DriftKind) {
+		found = append(found, driftKey{endpoint: endpoint, path: path, kind: kind})
+	})
+	if len(found) == 0 {
+		return
+	}
+
+	now := time.Now()
+	fresh, report := c.drift.record(endpoint, found, now)
+	for _, k := range fresh {
+		c.metrics().Add("wallex_schema_drift_total", 1,
+			Label{Name: "endpoint", Value: endpoint},
+			Label{Name: "kind", Value: string(k.kind)},
+		)
+	}
+	if len(report) == 0 || c.Logger == nil || !c.Logger.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+	c.Logger.LogAttrs(ctx, slog.LevelWarn, "wallex schema drift",
+		slog.String("endpoint", endpoint),
+		slog.Any("fields", report),
+	)
+}
+
+// record counts found and returns the keys seen for the first time, and
+// the fields to log now ("kind path"), which include those held back by
+// the rate limit since the last log of endpoint.
+func (r *driftRecorder) record(endpoint string, found []driftKey, now time.Time) (fresh []driftKey, report []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fields == nil {
+		r.fields = make(map[driftKey]*SchemaDrift)
+		r.lastLog = make(map[string]time.Time)
+		r.pending = make(map[string][]string)
+	}
+
+	for _, k := range found {
+		d, ok := r.fields[k]
+		if !ok {
+			d = &SchemaDrift{Endpoint: k.endpoint, Path: k.path, Kind: k.kind, FirstSeen: now}
+			r.fields[k] = d
+			fresh = append(fresh, k)
+			r.pending[endpoint] = append(r.pending[endpoint], string(k.kind)+" "+k.path)
+		}
+		d.Count++
+		d.LastSeen = now
+	}
+
+	if len(r.pending[endpoint]) == 0 || now.Sub(r.lastLog[endpoint]) < DefaultDriftLogInterval {
+		return fresh, nil
+	}
+	report = r.pending[endpoint]
+	delete(r.pending, endpoint)
+	r.lastLog[endpoint] = now
+	return fresh, report
+}
+
+// SchemaDrift returns the drift detected so far, sorted by endpoint and
+// path. It is empty unless DetectSchemaDrift is enabled.
+func (c *Client) SchemaDrift() []SchemaDrift {
+	c.drift.mu.Lock()
+	defer c.drift.mu.Unlock()
+
+	out := make([]SchemaDrift, 0, len(c.drift.fields))
+	for _, d := range c.drift.fields {
+		out = append(out, *d)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Endpoint != out[j].Endpoint {
+			return out[i].Endpoint < out[j].Endpoint
+		}
+		if out[i].Path != out[j].Path {
+			return out[i].Path < out[j].Path
+		}
+		return out[i].Kind < out[j].Kind
+	})
+	return out
+}
+
+// ResetSchemaDrift discards the drift detected so far.
+func (c *Client) ResetSchemaDrift() {
+	c.drift.mu.Lock()
+	c.drift.fields = nil
+	c.drift.lastLog = nil
+	c.drift.pending = nil
+	c.drift.mu.Unlock()
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+// compareSchema walks the decoded JSON value raw alongside typ and reports
+// fields present on one side only. Types with custom unmarshaling are
+// treated as opaque.
+func compareSchema(raw interface{}, typ reflect.Type, path string, report func(path string, kind DriftKind)) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if raw == nil || reflect.PointerTo(typ).Implements(jsonUnmarshalerType) || reflect.PointerTo(typ).Implements(textUnmarshalerType) {
+		return
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		fields := jsonFields(typ)
+		seen := make(map[string]bool, len(fields))
+		for key, v := range obj {
+			f, ok := fields[key]
+			if !ok {
+				f, ok = foldField(fields, key)
+			}
+			if !ok {
+				report(joinPath(path, key), DriftUnknown)
+				continue
+			}
+			seen[f.name] = true
+			compareSchema(v, f.typ, joinPath(path, key), report)
+		}
+		for name, f := range fields {
+			if !seen[name] && !f.omitempty {
+				report(joinPath(path, name), DriftMissing)
+			}
+		}
+	case reflect.Map:
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return
+		}
+		for _, v := range obj {
+			compareSchema(v, typ.Elem(), joinPath(path, "*"), report)
+		}
+	case reflect.Slice, reflect.Array:
+		arr, ok := raw.([]interface{})
+		if !ok {
+			return
+		}
+		for _, v := range arr {
+			compareSchema(v, typ.Elem(), path+"[]", report)
+		}
+	}
+}
+
+type jsonField struct {
+	name      string
+	typ       reflect.Type
+	omitempty bool
+}
+
+// jsonFields returns the JSON fields of a struct type by name, flattening
+// embedded structs like encoding/json does.
+func jsonFields(typ reflect.Type) map[string]jsonField {
+	fields := make(map[string]jsonField)
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for k, f := range jsonFields(ft) {
+				if _, ok := fields[k]; !ok {
+					fields[k] = f
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = jsonField{name: name, typ: sf.Type, omitempty: strings.Contains(opts, "omitempty")}
+	}
+	return fields
+}
+
+// foldField matches key case-insensitively, as encoding/json does.
+func foldField(fields map[string]jsonField, key string) (jsonField, bool) {
+	for name, f := range fields {
+		if strings.EqualFold(name, key) {
+			return f, true
+		}
+	}
+	return jsonField{}, false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
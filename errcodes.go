@@ -0,0 +1,160 @@
+package wallex
+
+import (
+	"net/http"
+	"sync"
+	"unicode"
+)
+
+// ErrorCode is the meaning of a Wallex error, independent of its numeric
+// code. It implements error so that it can be matched with errors.Is:
+//
+//	alloc, err := client.AllocateBudget("BTCUSDT", budget, price, feeRate)
+//	if errors.Is(err, wallex.CodeMinNotional) {
+//	    // increase the budget
+//	}
+//
+// APIErrors match the code their numeric code is registered with (see
+// RegisterErrorCode) or, for rate limits and maintenance, their HTTP
+// status. Local checks such as AllocateBudget and ExecuteMarketWithLimit
+// wrap the code of the rejection they anticipate.
+type ErrorCode string
+
+// Error codes known to the SDK.
+const (
+	// CodeInvalidAPIKey is Wallex code 1201.
+	CodeInvalidAPIKey ErrorCode = "invalid_api_key"
+
+	// CodeInsufficientBalance, CodeMinNotional and CodeInvalidQuantity are
+	// returned by local checks; their Wallex numbers are not documented and
+	// can be registered.
+	CodeInsufficientBalance ErrorCode = "insufficient_balance"
+	CodeMinNotional         ErrorCode = "min_notional"
+	CodeInvalidQuantity     ErrorCode = "invalid_quantity"
+
+	// CodeRateLimited and CodeMaintenance are derived from the HTTP status.
+	CodeRateLimited ErrorCode = "rate_limited"
+	CodeMaintenance ErrorCode = "maintenance"
+)
+
+// codeDescriptions are the English descriptions of the error codes.
+var codeDescriptions = map[ErrorCode]string{
+	CodeInvalidAPIKey:       "invalid API key",
+	CodeInsufficientBalance: "insufficient balance",
+	CodeMinNotional:         "order value is below the market minimum",
+	CodeInvalidQuantity:     "quantity is invalid or off the step size",
+	CodeRateLimited:         "rate limit exceeded",
+	CodeMaintenance:         "Wallex is in maintenance",
+}
+
+// Error returns the English description of the code.
+func (c ErrorCode) Error() string {
+	if d, ok := codeDescriptions[c]; ok {
+		return d
+	}
+	return string(c)
+}
+
+// ErrorCodeInfo describes a numeric Wallex error code.
+type ErrorCodeInfo struct {
+	// Number is the code Wallex returns in the "code" field.
+	Number int16
+
+	// Code is its meaning.
+	Code ErrorCode
+
+	// Description is the English message. Defaults to the description of
+	// Code.
+	Description string
+
+	// Persian is the message Wallex returns in Persian, for UIs.
+	Persian string
+}
+
+// errorCodes is the registry of numeric codes. It only holds codes whose
+// number is documented; applications register the others they meet.
+var errorCodes = struct {
+	sync.RWMutex
+	byNumber map[int16]ErrorCodeInfo
+}{
+	byNumber: map[int16]ErrorCodeInfo{
+		1201: {Number: 1201, Code: CodeInvalidAPIKey, Description: "invalid API key format"},
+	},
+}
+
+// RegisterErrorCode adds or replaces the entry of info.Number, so that
+// APIErrors carrying that code match info.Code with errors.Is and expose
+// the messages through CodeInfo and LocalizedMessage.
+func RegisterErrorCode(info ErrorCodeInfo) {
+	if info.Description == "" {
+		info.Description = info.Code.Error()
+	}
+	errorCodes.Lock()
+	errorCodes.byNumber[info.Number] = info
+	errorCodes.Unlock()
+}
+
+// LookupErrorCode returns the registry entry of a numeric code.
+func LookupErrorCode(number int16) (ErrorCodeInfo, bool) {
+	errorCodes.RLock()
+	defer errorCodes.RUnlock()
+	info, ok := errorCodes.byNumber[number]
+	return info, ok
+}
+
+// CodeInfo returns the registry entry of the error's numeric code. Errors
+// without a registered code are described by their HTTP status where that
+// is unambiguous (429 and maintenance responses); otherwise ok is false.
+func (e *APIError) CodeInfo() (ErrorCodeInfo, bool) {
+	if e.Code != 0 {
+		if info, ok := LookupErrorCode(e.Code); ok {
+			return info, true
+		}
+	}
+	var code ErrorCode
+	switch {
+	case isMaintenance(e):
+		code = CodeMaintenance
+	case e.StatusCode == http.StatusTooManyRequests:
+		code = CodeRateLimited
+	default:
+		return ErrorCodeInfo{}, false
+	}
+	return ErrorCodeInfo{Number: e.Code, Code: code, Description: code.Error()}, true
+}
+
+// Is reports whether target is the ErrorCode of the error, so that
+// errors.Is(err, CodeMinNotional) works through wrapping.
+func (e *APIError) Is(target error) bool {
+	code, ok := target.(ErrorCode)
+	if !ok {
+		return false
+	}
+	info, ok := e.CodeInfo()
+	return ok && info.Code == code
+}
+
+// LocalizedMessage returns the error message in lang, "fa" or "en", for
+// display. For "fa" it is the registered Persian message; for "en", the
+// server's message unless that is in Persian, then the registered
+// description. Otherwise it is Message as returned by Wallex.
+func (e *APIError) LocalizedMessage(lang string) string {
+	info, ok := e.CodeInfo()
+	switch {
+	case lang == "fa" && ok && info.Persian != "":
+		return info.Persian
+	case lang == "en" && ok && isPersian(e.Message):
+		return info.Description
+	}
+	return e.Message
+}
+
+// isPersian reports whether s contains Arabic-script letters.
+func isPersian(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Arabic, r) {
+			return true
+		}
+	}
+	return false
+}
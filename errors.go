@@ -3,7 +3,9 @@ package wallex
 import (
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"strconv"
+	"strings"
 
 	t "github.com/darhelm/go-wallex/types"
 )
@@ -34,6 +36,43 @@ func (e *GoWallexError) Unwrap() error { return e.Err }
 type RequestError struct {
 	GoWallexError
 	Operation string
+
+	// Endpoint is the method and path of the request, e.g.
+	// "POST /v1/account/orders", without query string.
+	Endpoint string
+
+	// RequestID is the correlation ID sent in the X-Request-ID header.
+	RequestID string
+}
+
+func (e *RequestError) Error() string {
+	return withRequestIDSuffix(e.GoWallexError.Error(), e.RequestID)
+}
+
+// OrderStateError reports that an order helper could not proceed because the
+// order was not in the state the operation requires, e.g. an order that was
+// already filled when ReplaceOrder attempted to cancel it.
+type OrderStateError struct {
+	GoWallexError
+	ClientOrderId string
+	Status        string
+}
+
+// UnknownSymbolError is returned when a symbol does not match any Wallex
+// market. Suggestion holds the closest known symbol, or "" if none is close.
+type UnknownSymbolError struct {
+	GoWallexError
+	Symbol     string
+	Suggestion string
+}
+
+// InvalidParamError is returned when a parameter that ends up in the
+// request URL, such as a symbol or clientOrderId, contains characters that
+// could alter the request. The request is not sent.
+type InvalidParamError struct {
+	GoWallexError
+	Param string
+	Value string
 }
 
 // APIError represents any non-2xx error response returned by the Wallex API.
@@ -83,6 +122,108 @@ type APIError struct {
 
 	// Map of all parsed key->values for inspection (similar to go-bitpin)
 	Fields map[string][]string
+
+	// Endpoint is the method and path of the request, e.g.
+	// "POST /v1/account/orders", without query string.
+	Endpoint string
+
+	// RequestID is the correlation ID sent in the X-Request-ID header.
+	// Quote it when contacting Wallex support.
+	RequestID string
+}
+
+// ErrorDetails is the structured, log-friendly form of an APIError.
+type ErrorDetails struct {
+	StatusCode int                 `json:"status"`
+	Code       int16               `json:"code,omitempty"`
+	Message    string              `json:"message"`
+	Endpoint   string              `json:"endpoint,omitempty"`
+	RequestID  string              `json:"request_id,omitempty"`
+	Fields     map[string][]string `json:"fields,omitempty"`
+}
+
+// sensitiveFields are error fields whose values are replaced by
+// redactedValue in ErrorDetails, case-insensitively.
+var sensitiveFields = []string{"key", "token", "secret", "password", "iban", "signature", "authorization"}
+
+const redactedValue = "[REDACTED]"
+
+// Details returns a structured copy of the error suitable for logging.
+// Fields whose name suggests a credential or account identifier (API keys,
+// tokens, IBANs, ...) have their values redacted, and the "code" and
+// "message" fields, which duplicate Code and Message, are omitted.
+func (e *APIError) Details() ErrorDetails {
+	d := ErrorDetails{
+		StatusCode: e.StatusCode,
+		Code:       e.Code,
+		Message:    e.Message,
+		Endpoint:   e.Endpoint,
+		RequestID:  e.RequestID,
+	}
+	for k, v := range e.Fields {
+		if k == "code" || k == "message" {
+			continue
+		}
+		if d.Fields == nil {
+			d.Fields = make(map[string][]string)
+		}
+		if isSensitiveField(k) {
+			v = []string{redactedValue}
+		} else {
+			v = append([]string(nil), v...)
+		}
+		d.Fields[k] = v
+	}
+	return d
+}
+
+// MarshalJSON encodes the error as its Details, so APIError values can be
+// passed directly to JSON loggers.
+func (e *APIError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.Details())
+}
+
+// LogValue implements slog.LogValuer, logging the error as its Details.
+func (e *APIError) LogValue() slog.Value {
+	d := e.Details()
+	attrs := []slog.Attr{
+		slog.Int("status", d.StatusCode),
+		slog.String("message", d.Message),
+	}
+	if d.Code != 0 {
+		attrs = append(attrs, slog.Int("code", int(d.Code)))
+	}
+	if d.Endpoint != "" {
+		attrs = append(attrs, slog.String("endpoint", d.Endpoint))
+	}
+	if d.RequestID != "" {
+		attrs = append(attrs, slog.String("request_id", d.RequestID))
+	}
+	if len(d.Fields) > 0 {
+		attrs = append(attrs, slog.Any("fields", d.Fields))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, s := range sensitiveFields {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *APIError) Error() string {
+	return withRequestIDSuffix(e.GoWallexError.Error(), e.RequestID)
+}
+
+func withRequestIDSuffix(msg, requestID string) string {
+	if requestID == "" {
+		return msg
+	}
+	return msg + " (request_id=" + requestID + ")"
 }
 
 // parseErrorResponse creates an APIError from a Wallex non-2xx response.
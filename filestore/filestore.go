@@ -0,0 +1,427 @@
+// Package filestore provides a persistent, dependency-free implementation of
+// the wallex OrderStore, TradeStore, CursorStore and ScheduleStore
+// interfaces.
+//
+// All state lives in a single directory:
+//
+//	orders.json    - snapshots of open orders keyed by clientOrderId
+//	cursors.json   - TradeSyncer cursors keyed by syncer key
+//	schedules.json - pending scheduled orders keyed by schedule id
+//	trades.jsonl   - append-only user trade log, one JSON object per line
+//
+// Snapshot files are replaced atomically: the new content is written to a
+// temporary file and synced, renamed over the old file, and the directory
+// is synced so that the rename itself survives a crash. Appended trades are
+// synced before AppendTrades returns; a line torn by a crash mid-append is
+// cut off when the store is next opened. The store is meant for a single
+// process; it is safe for concurrent use within that process but does not
+// lock the directory against other processes.
+//
+// orders.json is rewritten on every order update. Like every OrderStore it
+// only holds orders that can still change: the OrderTracker never polls a
+// terminal order again and has nothing to restore.
+//
+// The store uses plain files rather than SQLite or Bolt so that the module
+// keeps no third-party dependencies. A database-backed store only has to
+// implement the same interfaces and can live in a module of its own.
+package filestore
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	wallex "github.com/darhelm/go-wallex"
+	t "github.com/darhelm/go-wallex/types"
+)
+
+const (
+	ordersFile  = "orders.json"
+	cursorsFile = "cursors.json"
+	schedFile   = "schedules.json"
+	tradesFile  = "trades.jsonl"
+)
+
+// Store is a directory-backed store.
+type Store struct {
+	dir string
+
+	mu        sync.Mutex
+	orders    map[string]t.BaseOrder
+	cursors   map[string]wallex.TradeCursor
+	schedules map[string]wallex.ScheduledOrder
+	keys      map[string]struct{}
+}
+
+var (
+	_ wallex.OrderStore    = (*Store)(nil)
+	_ wallex.TradeStore    = (*Store)(nil)
+	_ wallex.CursorStore   = (*Store)(nil)
+	_ wallex.ScheduleStore = (*Store)(nil)
+)
+
+// Open opens (creating if needed) a store rooted at dir and loads its state.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("filestore: create directory: %w", err)
+	}
+	// Temporary files are left behind only by a crash mid-write; the file
+	// they were to replace is intact.
+	if tmps, err := filepath.Glob(filepath.Join(dir, "*.tmp")); err == nil {
+		for _, tmp := range tmps {
+			_ = os.Remove(tmp)
+		}
+	}
+
+	s := &Store{
+		dir:       dir,
+		orders:    make(map[string]t.BaseOrder),
+		cursors:   make(map[string]wallex.TradeCursor),
+		schedules: make(map[string]wallex.ScheduledOrder),
+		keys:      make(map[string]struct{}),
+	}
+
+	if err := s.readJSON(ordersFile, &s.orders); err != nil {
+		return nil, err
+	}
+	if err := s.readJSON(cursorsFile, &s.cursors); err != nil {
+		return nil, err
+	}
+	if err := s.readJSON(schedFile, &s.schedules); err != nil {
+		return nil, err
+	}
+
+	trades, size, err := s.readTrades()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repairTrades(size); err != nil {
+		return nil, err
+	}
+	for _, tr := range trades {
+		s.keys[wallex.TradeKey(tr)] = struct{}{}
+	}
+
+	return s, nil
+}
+
+// Dir returns the directory the store lives in.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// SaveOrder implements wallex.OrderStore.
+func (s *Store) SaveOrder(_ context.Context, order t.BaseOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if wallex.IsTerminalStatus(order.Status) {
+		return s.deleteOrder(order.ClientOrderId)
+	}
+	orders := maps.Clone(s.orders)
+	orders[order.ClientOrderId] = order
+	if err := s.writeJSON(ordersFile, orders); err != nil {
+		return err
+	}
+	s.orders = orders
+	return nil
+}
+
+// LoadOrders implements wallex.OrderStore.
+func (s *Store) LoadOrders(_ context.Context) ([]t.BaseOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]t.BaseOrder, 0, len(s.orders))
+	for _, o := range s.orders {
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// DeleteOrder implements wallex.OrderStore.
+func (s *Store) DeleteOrder(_ context.Context, clientOrderId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deleteOrder(clientOrderId)
+}
+
+func (s *Store) deleteOrder(clientOrderId string) error {
+	if _, ok := s.orders[clientOrderId]; !ok {
+		return nil
+	}
+	orders := maps.Clone(s.orders)
+	delete(orders, clientOrderId)
+	if err := s.writeJSON(ordersFile, orders); err != nil {
+		return err
+	}
+	s.orders = orders
+	return nil
+}
+
+// LoadCursor implements wallex.CursorStore.
+func (s *Store) LoadCursor(_ context.Context, key string) (*wallex.TradeCursor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur, ok := s.cursors[key]
+	if !ok {
+		return nil, nil
+	}
+	return &cur, nil
+}
+
+// SaveCursor implements wallex.CursorStore.
+func (s *Store) SaveCursor(_ context.Context, key string, cursor wallex.TradeCursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cursors := maps.Clone(s.cursors)
+	cursors[key] = cursor
+	if err := s.writeJSON(cursorsFile, cursors); err != nil {
+		return err
+	}
+	s.cursors = cursors
+	return nil
+}
+
+// SaveSchedule implements wallex.ScheduleStore.
+func (s *Store) SaveSchedule(_ context.Context, so wallex.ScheduledOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	schedules := maps.Clone(s.schedules)
+	schedules[so.ID] = so
+	if err := s.writeJSON(schedFile, schedules); err != nil {
+		return err
+	}
+	s.schedules = schedules
+	return nil
+}
+
+// LoadSchedules implements wallex.ScheduleStore.
+func (s *Store) LoadSchedules(_ context.Context) ([]wallex.ScheduledOrder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]wallex.ScheduledOrder, 0, len(s.schedules))
+	for _, so := range s.schedules {
+		out = append(out, so)
+	}
+	return out, nil
+}
+
+// DeleteSchedule implements wallex.ScheduleStore.
+func (s *Store) DeleteSchedule(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[id]; !ok {
+		return nil
+	}
+	schedules := maps.Clone(s.schedules)
+	delete(schedules, id)
+	if err := s.writeJSON(schedFile, schedules); err != nil {
+		return err
+	}
+	s.schedules = schedules
+	return nil
+}
+
+// AppendTrades implements wallex.TradeStore.
+func (s *Store) AppendTrades(_ context.Context, trades []t.UserTrade) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := filepath.Join(s.dir, tradesFile)
+	_, statErr := os.Stat(path)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("filestore: open trades: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if errors.Is(statErr, fs.ErrNotExist) {
+		if err := s.syncDir(); err != nil {
+			return err
+		}
+	}
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return fmt.Errorf("filestore: open trades: %w", err)
+	}
+
+	if err := s.appendTrades(f, trades); err != nil {
+		// Cut off whatever part of the batch reached the file, so that
+		// later appends do not follow a torn line.
+		_ = f.Truncate(size)
+		return err
+	}
+	return nil
+}
+
+// appendTrades writes the trades not stored yet to f, once per key, and
+// syncs it. The keys of the written trades are recorded only once the write
+// succeeded.
+func (s *Store) appendTrades(f *os.File, trades []t.UserTrade) error {
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	added := make(map[string]struct{})
+	for _, tr := range trades {
+		k := wallex.TradeKey(tr)
+		if _, ok := s.keys[k]; ok {
+			continue
+		}
+		if _, ok := added[k]; ok {
+			continue
+		}
+		if err := enc.Encode(tr); err != nil {
+			return fmt.Errorf("filestore: encode trade: %w", err)
+		}
+		added[k] = struct{}{}
+	}
+	if err := w.Flush(); err != nil {
+		return fmt.Errorf("filestore: write trades: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("filestore: sync trades: %w", err)
+	}
+
+	maps.Copy(s.keys, added)
+	return nil
+}
+
+// LoadTrades implements wallex.TradeStore.
+func (s *Store) LoadTrades(_ context.Context, since time.Time) ([]t.UserTrade, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trades, _, err := s.readTrades()
+	if err != nil {
+		return nil, err
+	}
+	return wallex.FilterTradesSince(trades, since), nil
+}
+
+// readTrades decodes the trade log. A final line without its newline, torn
+// by a crash mid-append, is not returned; size is the length of the log up
+// to the end of its last complete line. Any complete line that fails to
+// decode is an error.
+func (s *Store) readTrades() (trades []t.UserTrade, size int64, err error) {
+	f, err := os.Open(filepath.Join(s.dir, tradesFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("filestore: open trades: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	r := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		data, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return trades, size, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("filestore: read trades: %w", err)
+		}
+		var tr t.UserTrade
+		if err := json.Unmarshal(data, &tr); err != nil {
+			return nil, 0, fmt.Errorf("filestore: decode %s line %d: %w", tradesFile, line, err)
+		}
+		trades = append(trades, tr)
+		size += int64(len(data))
+	}
+}
+
+// repairTrades truncates the trade log to size, cutting off a line torn by
+// a crash mid-append. The syncer cursor was not advanced for the trades on
+// it, so they are fetched again.
+func (s *Store) repairTrades(size int64) error {
+	path := filepath.Join(s.dir, tradesFile)
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("filestore: stat trades: %w", err)
+	}
+	if info.Size() == size {
+		return nil
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("filestore: open trades: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	if err := f.Truncate(size); err != nil {
+		return fmt.Errorf("filestore: repair trades: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("filestore: sync trades: %w", err)
+	}
+	return nil
+}
+
+func (s *Store) readJSON(name string, v interface{}) error {
+	data, err := os.ReadFile(filepath.Join(s.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("filestore: read %s: %w", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("filestore: decode %s: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Store) writeJSON(name string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("filestore: encode %s: %w", name, err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("filestore: create temp file: %w", err)
+	}
+	tmpName := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("filestore: write %s: %w", name, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("filestore: sync %s: %w", name, err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("filestore: close %s: %w", name, err)
+	}
+	if err := os.Rename(tmpName, filepath.Join(s.dir, name)); err != nil {
+		_ = os.Remove(tmpName)
+		return fmt.Errorf("filestore: replace %s: %w", name, err)
+	}
+	return s.syncDir()
+}
+
+// syncDir flushes the directory entries, making file creations and renames
+// durable.
+func (s *Store) syncDir() error {
+	d, err := os.Open(s.dir)
+	if err != nil {
+		return fmt.Errorf("filestore: open directory: %w", err)
+	}
+	defer func() { _ = d.Close() }()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("filestore: sync directory: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package filestore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-wallex/types"
+)
+
+var base = time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func trade(i int) types.UserTrade {
+	return types.UserTrade{
+		Symbol:    "BTCUSDT",
+		Quantity:  "0.5",
+		Price:     types.StringOrNumber(strings.Repeat("1", i+1)),
+		Fee:       "0.001",
+		FeeAsset:  "BTC",
+		IsBuyer:   true,
+		Timestamp: base.Add(time.Duration(i) * time.Minute),
+	}
+}
+
+func line(tb testing.TB, tr types.UserTrade) string {
+	tb.Helper()
+	data, err := json.Marshal(tr)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	return string(data) + "\n"
+}
+
+func TestOpenRecoversTornTradeLog(t *testing.T) {
+	cases := []struct {
+		name    string
+		log     func(testing.TB) string
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "empty",
+			log:  func(testing.TB) string { return "" },
+			want: 0,
+		},
+		{
+			name: "complete",
+			log:  func(tb testing.TB) string { return line(tb, trade(0)) + line(tb, trade(1)) },
+			want: 2,
+		},
+		{
+			name: "torn last line",
+			log: func(tb testing.TB) string {
+				torn := line(tb, trade(1))
+				return line(tb, trade(0)) + torn[:len(torn)/2]
+			},
+			want: 1,
+		},
+		{
+			name: "torn only line",
+			log: func(tb testing.TB) string {
+				torn := line(tb, trade(0))
+				return torn[:len(torn)-1]
+			},
+			want: 0,
+		},
+		{
+			name:    "corrupt middle line",
+			log:     func(tb testing.TB) string { return line(tb, trade(0)) + "{\"symbol\":\n" + line(tb, trade(1)) },
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tradesFile), []byte(tc.log(t)), 0o600); err != nil {
+				t.Fatal(err)
+			}
+
+			s, err := Open(dir)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("Open succeeded on a corrupt trade log")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Trades appended after the repair must survive a reopen.
+			if err := s.AppendTrades(ctx, []types.UserTrade{trade(2), trade(3)}); err != nil {
+				t.Fatal(err)
+			}
+			s, err = Open(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.LoadTrades(ctx, time.Time{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != tc.want+2 {
+				t.Fatalf("loaded %d trades, want %d", len(got), tc.want+2)
+			}
+			if last := got[len(got)-1]; !last.Timestamp.Equal(trade(3).Timestamp) {
+				t.Errorf("last trade at %s, want %s", last.Timestamp, trade(3).Timestamp)
+			}
+		})
+	}
+}
+
+func TestAppendTradesSkipsStored(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AppendTrades(ctx, []types.UserTrade{trade(0), trade(1)}); err != nil {
+		t.Fatal(err)
+	}
+	s, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AppendTrades(ctx, []types.UserTrade{trade(1), trade(2), trade(2)}); err != nil {
+		t.Fatal(err)
+	}
+	s, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.LoadTrades(ctx, trade(1).Timestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("loaded %d trades since the second, want 2", len(got))
+	}
+}
+
+func TestSaveOrder(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	open := types.BaseOrder{ClientOrderId: "a", Status: types.OrderStatusNew}
+	done := types.BaseOrder{ClientOrderId: "b", Status: types.OrderStatusNew}
+	for _, o := range []types.BaseOrder{open, done} {
+		if err := s.SaveOrder(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	done.Status = types.OrderStatusFilled
+	if err := s.SaveOrder(ctx, done); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err = Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orders, err := s.LoadOrders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || orders[0].ClientOrderId != "a" {
+		t.Fatalf("orders = %+v, want only the open order", orders)
+	}
+
+	// A failed write must leave the loaded state unchanged.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveOrder(ctx, types.BaseOrder{ClientOrderId: "c", Status: types.OrderStatusNew}); err == nil {
+		t.Fatal("SaveOrder succeeded without a directory")
+	}
+	if err := s.DeleteOrder(ctx, "a"); err == nil {
+		t.Fatal("DeleteOrder succeeded without a directory")
+	}
+	orders, err = s.LoadOrders(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 1 || orders[0].ClientOrderId != "a" {
+		t.Errorf("orders after failed writes = %+v, want only the open order", orders)
+	}
+}
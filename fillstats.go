@@ -0,0 +1,81 @@
+package wallex
+
+import (
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// FillStats aggregates the executions of one order.
+type FillStats struct {
+	// Fills is the number of executions the order was filled in.
+	Fills int
+
+	// Quantity and Notional are the executed base quantity and its quote
+	// value; AvgPrice is Notional / Quantity.
+	Quantity float64
+	Notional float64
+	AvgPrice float64
+
+	// Fees are the fees paid, by asset.
+	Fees map[string]float64
+
+	// FirstFill and LastFill are the times of the first and last
+	// executions.
+	FirstFill time.Time
+	LastFill  time.Time
+
+	// Duration is the time from the creation of the order to its last
+	// execution, or zero if either is unknown.
+	Duration time.Duration
+
+	// Estimated is set when the order carried no fills, so the totals come
+	// from its executed quantity and sum, and fees and times are unknown.
+	Estimated bool
+}
+
+// ComputeFillStats aggregates the fills of an order snapshot, as returned
+// by GetOrderStatus or CancelOrder. Orders without executions return zero
+// stats.
+func ComputeFillStats(order t.BaseOrder) FillStats {
+	var st FillStats
+	if len(order.Fills) == 0 {
+		if !hasExecuted(order) {
+			return st
+		}
+		st.Quantity = order.ExecutedQty.Float()
+		st.Notional = order.ExecutedSum.Float()
+		if st.Notional == 0 {
+			st.Notional = st.Quantity * order.ExecutedPrice.Float()
+		}
+		st.Estimated = true
+	}
+
+	for _, f := range order.Fills {
+		st.Fills++
+		st.Quantity += f.Quantity.Float()
+		st.Notional += f.Notional()
+		if fee := f.Commission.Float(); fee != 0 {
+			if st.Fees == nil {
+				st.Fees = make(map[string]float64)
+			}
+			st.Fees[f.CommissionAsset] += fee
+		}
+		if ts := f.Timestamp.Time; !ts.IsZero() {
+			if st.FirstFill.IsZero() || ts.Before(st.FirstFill) {
+				st.FirstFill = ts
+			}
+			if ts.After(st.LastFill) {
+				st.LastFill = ts
+			}
+		}
+	}
+
+	if st.Quantity > 0 {
+		st.AvgPrice = st.Notional / st.Quantity
+	}
+	if created := order.CreatedAt.Time; !created.IsZero() && !st.LastFill.IsZero() && st.LastFill.After(created) {
+		st.Duration = st.LastFill.Sub(created)
+	}
+	return st
+}
@@ -0,0 +1,122 @@
+// Package fixtures exposes canonical Wallex API response payloads.
+//
+// The samples under testdata/ mirror real responses captured from the public
+// and authenticated Wallex endpoints, including the loosely typed corners of
+// the API ("-" placeholders, [] instead of {}, numbers as strings). They are
+// embedded into the binary so consumers and contributors can validate their
+// decoding against the same payloads the SDK is built for.
+//
+// Example:
+//
+//	var markets types.MarketInformation
+//	if err := fixtures.Decode(fixtures.Markets, &markets); err != nil {
+//	    log.Fatal(err)
+//	}
+package fixtures
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strings"
+)
+
+//go:embed testdata/*.json
+var files embed.FS
+
+// Names of the bundled fixtures, usable with Load, MustLoad and Decode.
+const (
+	// Markets is a GET /v1/markets response.
+	Markets = "markets"
+
+	// CurrencyStats is a GET /v1/currencies/stats response.
+	CurrencyStats = "currency_stats"
+
+	// Depth is a GET /v1/depth response (prices as number-strings).
+	Depth = "depth"
+
+	// AllDepths is a GET /v2/depth/all response (prices as numbers).
+	AllDepths = "depth_all"
+
+	// Trades is a GET /v1/trades response.
+	Trades = "trades"
+
+	// Candles is a GET /v1/udf/history response of 1m bars with one gap.
+	Candles = "candles"
+
+	// OrderCreate is a POST /v1/account/orders response.
+	OrderCreate = "order_create"
+
+	// OrderStatus is a GET /v1/account/orders/{clientOrderId} response.
+	OrderStatus = "order_status"
+
+	// OrderCancel is a DELETE /v1/account/orders response.
+	OrderCancel = "order_cancel"
+
+	// OpenOrders is a GET /v1/account/openOrders response.
+	OpenOrders = "open_orders"
+
+	// UserTrades is a GET /v1/account/trades response.
+	UserTrades = "user_trades"
+
+	// Wallets is a GET /v1/account/balances response.
+	Wallets = "wallets"
+
+	// CryptoDeposits is a GET /v1/account/crypto-deposit response.
+	CryptoDeposits = "crypto_deposits"
+
+	// ErrorCode is the standard error envelope with success, code and result.
+	ErrorCode = "error_code"
+
+	// ErrorDetail is the bare {"detail": "..."} error shape.
+	ErrorDetail = "error_detail"
+
+	// ErrorMessage is the bare {"message": "..."} error shape.
+	ErrorMessage = "error_message"
+)
+
+// Load returns the raw JSON bytes of the named fixture.
+//
+// The name may be given with or without the ".json" suffix.
+func Load(name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".json")
+	data, err := files.ReadFile("testdata/" + name + ".json")
+	if err != nil {
+		return nil, fmt.Errorf("fixture %q not found: %w", name, err)
+	}
+	return data, nil
+}
+
+// MustLoad is like Load but panics if the fixture does not exist.
+func MustLoad(name string) []byte {
+	data, err := Load(name)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// Decode loads the named fixture and unmarshals it into v.
+func Decode(name string, v interface{}) error {
+	data, err := Load(name)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode fixture %q: %w", name, err)
+	}
+	return nil
+}
+
+// Names returns the names of all bundled fixtures in sorted order.
+func Names() []string {
+	entries, _ := fs.ReadDir(files, "testdata")
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,317 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Health monitor defaults used by NewHealthMonitor.
+const (
+	DefaultHealthInterval     = 15 * time.Second
+	DefaultHealthFailures     = 3
+	DefaultHealthRecoveries   = 2
+	DefaultHealthErrorRatio   = 0.5
+	DefaultHealthMinRequests  = 5
+	DefaultHealthProbeTimeout = 10 * time.Second
+)
+
+// HealthState is the state reported by a HealthMonitor.
+type HealthState struct {
+	Healthy bool
+
+	// Since is when the monitor entered the state.
+	Since time.Time
+
+	// Reason describes the failure that made the service unhealthy; empty
+	// while healthy.
+	Reason string
+
+	// Maintenance reports that Wallex announced maintenance, rather than
+	// failing unannounced.
+	Maintenance bool
+}
+
+// HealthOptions configures a HealthMonitor.
+type HealthOptions struct {
+	// Interval is the time between checks. Defaults to
+	// DefaultHealthInterval.
+	Interval time.Duration
+
+	// Probe is called by every check; an error counts as a failed check.
+	// Defaults to fetching GET /v1/markets, which fails during maintenance
+	// and outages. Probes run with a timeout of DefaultHealthProbeTimeout.
+	Probe func(ctx context.Context) error
+
+	// ErrorRatio and MinRequests make the client's own traffic count too:
+	// a check fails when, since the previous check, at least MinRequests
+	// calls were made and at least ErrorRatio of them failed with a server,
+	// network or timeout error (see Stats). Defaults to
+	// DefaultHealthErrorRatio and DefaultHealthMinRequests.
+	ErrorRatio  float64
+	MinRequests int
+
+	// Failures is the number of consecutive failed checks after which the
+	// service is considered down. Defaults to DefaultHealthFailures.
+	Failures int
+
+	// Recoveries is the number of consecutive passed checks after which a
+	// down service is considered back. Defaults to
+	// DefaultHealthRecoveries.
+	Recoveries int
+
+	// Pause lists switches that are killed while the service is down and
+	// resumed when it is back.
+	Pause []TradingSwitch
+
+	// HoldRequests queues the requests of the client and its clones while
+	// the service is down: calls below PriorityHigh wait for recovery, or
+	// for their context, instead of failing. PriorityHigh calls, e.g.
+	// urgent cancels, and the monitor's own requests are sent regardless.
+	HoldRequests bool
+
+	// Reconciler, if set, runs a Reconcile pass when the service is back,
+	// before the switches are resumed, so that orders that were filled or
+	// cancelled during the outage are accounted for. If the pass fails,
+	// the switches stay paused and the pass is retried on the next check.
+	Reconciler *Reconciler
+
+	// OnChange is called on every transition, after the switches were
+	// killed or resumed.
+	OnChange func(HealthState)
+}
+
+// HealthMonitor detects Wallex maintenance windows and outages, from
+// failing probes and from sustained server errors in the client's own
+// traffic, and pauses trading while they last:
+//
+//	health := wallex.NewHealthMonitor(client, wallex.HealthOptions{
+//	    Pause:      []wallex.TradingSwitch{checker},
+//	    Reconciler: reconciler,
+//	})
+//	go health.Run(ctx, log.Println)
+//
+// The service is assumed healthy until Failures consecutive checks failed,
+// or until a single check sees a MaintenanceError.
+//
+// HealthMonitor implements Closer and is safe for concurrent use.
+type HealthMonitor struct {
+	c    *Client
+	opts HealthOptions
+
+	mu          sync.Mutex
+	state       HealthState
+	failed      int
+	passed      int
+	since       time.Time
+	requests    uint64
+	errors      uint64
+	maintenance uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewHealthMonitor returns a HealthMonitor of the client. Call Run to start
+// checking.
+func NewHealthMonitor(c *Client, opts HealthOptions) *HealthMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultHealthInterval
+	}
+	if opts.ErrorRatio <= 0 {
+		opts.ErrorRatio = DefaultHealthErrorRatio
+	}
+	if opts.MinRequests <= 0 {
+		opts.MinRequests = DefaultHealthMinRequests
+	}
+	if opts.Failures <= 0 {
+		opts.Failures = DefaultHealthFailures
+	}
+	if opts.Recoveries <= 0 {
+		opts.Recoveries = DefaultHealthRecoveries
+	}
+	if opts.Probe == nil {
+		opts.Probe = func(ctx context.Context) error {
+			_, err := c.getMarketsInfo(ctx)
+			return err
+		}
+	}
+	h := &HealthMonitor{
+		c:     c,
+		opts:  opts,
+		state: HealthState{Healthy: true, Since: time.Now()},
+		stop:  make(chan struct{}),
+	}
+	h.since, h.requests, h.errors, h.maintenance = h.traffic()
+	return h
+}
+
+// IsHealthy reports whether the service is considered up.
+func (h *HealthMonitor) IsHealthy() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state.Healthy
+}
+
+// State returns the current state.
+func (h *HealthMonitor) State() HealthState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state
+}
+
+// Check runs one check and applies any resulting transition. It returns the
+// error of the Reconcile pass, if one ran and failed. Run calls it every
+// Interval.
+func (h *HealthMonitor) Check(ctx context.Context) error {
+	// The monitor's own requests must pass the hold gate.
+	ctx = context.WithValue(ctx, priorityKey{}, PriorityHigh)
+
+	reason, maintenance := h.probe(ctx)
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	switch {
+	case maintenance:
+		h.failed = h.opts.Failures
+		h.passed = 0
+	case reason != "":
+		h.failed++
+		h.passed = 0
+	default:
+		h.passed++
+		h.failed = 0
+	}
+	down := h.state.Healthy && h.failed >= h.opts.Failures
+	up := !h.state.Healthy && h.passed >= h.opts.Recoveries
+	if down {
+		h.state = HealthState{Healthy: false, Since: time.Now(), Reason: reason, Maintenance: maintenance}
+	}
+	h.mu.Unlock()
+
+	if down {
+		h.c.metrics().Add("wallex_health_outages_total", 1)
+		h.c.metrics().Gauge("wallex_healthy", 0)
+		if h.opts.HoldRequests {
+			h.c.hold.hold()
+		}
+		for _, sw := range h.opts.Pause {
+			sw.Kill("wallex unavailable: " + reason)
+		}
+		if h.opts.OnChange != nil {
+			h.opts.OnChange(h.State())
+		}
+		return nil
+	}
+	if !up {
+		return nil
+	}
+
+	if h.opts.Reconciler != nil {
+		if _, err := h.opts.Reconciler.Reconcile(ctx); err != nil {
+			return &GoWallexError{Message: "reconciliation after outage failed", Err: err}
+		}
+	}
+
+	h.mu.Lock()
+	h.state = HealthState{Healthy: true, Since: time.Now()}
+	h.mu.Unlock()
+
+	h.c.metrics().Gauge("wallex_healthy", 1)
+	if h.opts.HoldRequests {
+		h.c.hold.release()
+	}
+	for _, sw := range h.opts.Pause {
+		sw.Resume()
+	}
+	if h.opts.OnChange != nil {
+		h.opts.OnChange(h.State())
+	}
+	return nil
+}
+
+// probe returns why the check failed, or "" if it passed, and whether
+// Wallex announced maintenance.
+func (h *HealthMonitor) probe(ctx context.Context) (string, bool) {
+	pctx, cancel := context.WithTimeout(ctx, DefaultHealthProbeTimeout)
+	err := h.opts.Probe(pctx)
+	cancel()
+
+	// The traffic is sampled after the probe, so that its failure counts
+	// once, in this check.
+	since, requests, failed, maintenance := h.traffic()
+	h.mu.Lock()
+	var calls, failures, announced uint64
+	if since.Equal(h.since) {
+		calls, failures, announced = requests-h.requests, failed-h.errors, maintenance-h.maintenance
+	}
+	h.since, h.requests, h.errors, h.maintenance = since, requests, failed, maintenance
+	h.mu.Unlock()
+
+	if err != nil {
+		var mErr *MaintenanceError
+		return "probe failed: " + err.Error(), errors.As(err, &mErr)
+	}
+	if announced > 0 {
+		return fmt.Sprintf("%d requests failed with maintenance errors", announced), true
+	}
+	if calls >= uint64(h.opts.MinRequests) && float64(failures) >= h.opts.ErrorRatio*float64(calls) {
+		return fmt.Sprintf("%d of %d requests failed", failures, calls), false
+	}
+	return "", false
+}
+
+// traffic returns the client's request count, outage-like failures and
+// maintenance errors.
+func (h *HealthMonitor) traffic() (since time.Time, requests, failures, maintenance uint64) {
+	st := h.c.Stats()
+	for _, n := range st.Requests {
+		requests += n
+	}
+	maintenance = st.Errors[ErrorClassMaintenance]
+	failures = st.Errors[ErrorClassServer] + st.Errors[ErrorClassNetwork] + st.Errors[ErrorClassTimeout] + maintenance
+	return st.Since, requests, failures, maintenance
+}
+
+// Run checks every Interval until ctx is done, the monitor is closed or
+// the client is shut down. Errors are passed to onError when it is
+// non-nil.
+func (h *HealthMonitor) Run(ctx context.Context, onError func(error)) {
+	h.loops.Add(1)
+	defer h.loops.Done()
+
+	ticker := time.NewTicker(h.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.Check(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-h.stop:
+			return
+		case <-h.c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close implements Closer. It stops Run loops and waits for them to return.
+// Requests held by the monitor are released.
+func (h *HealthMonitor) Close(ctx context.Context) error {
+	h.stopOnce.Do(func() {
+		close(h.stop)
+		if h.opts.HoldRequests {
+			h.c.hold.release()
+		}
+	})
+	return waitGroupDone(ctx, &h.loops)
+}
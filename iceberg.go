@@ -0,0 +1,468 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// DefaultIcebergInterval is how often an Iceberg polls its working slice
+// when IcebergOptions.Interval is zero.
+const DefaultIcebergInterval = time.Second
+
+// IcebergOptions configures an Iceberg.
+type IcebergOptions struct {
+	// Symbol is the market, e.g. "BTCTMN".
+	Symbol string
+
+	// Side is t.SideBuy or t.SideSell.
+	Side string
+
+	// Quantity is the total base quantity to execute.
+	Quantity float64
+
+	// Price is the limit price of the slices. With a Peg it is the worst
+	// price a slice may rest at instead, the highest for a buy and the
+	// lowest for a sell; zero leaves a pegged order unbounded.
+	Price float64
+
+	// SliceSize is the visible quantity of each slice.
+	SliceSize float64
+
+	// SliceJitter randomizes each slice by up to ±SliceJitter×SliceSize,
+	// e.g. 0.3, so the replenishments are harder to spot.
+	SliceJitter float64
+
+	// Peg reprices the working slice as the book moves, offset
+	// PegOffsetTicks ticks away from the opposite side. A slice whose
+	// peg price changed is canceled and replaced at the new price, at
+	// most once per MinReprice.
+	Peg            PegMode
+	PegOffsetTicks int
+	MinReprice     time.Duration
+
+	// Interval is how often the working slice is polled and, with a Peg,
+	// repriced. Defaults to DefaultIcebergInterval.
+	Interval time.Duration
+
+	// Tracker receives every slice snapshot, so transitions can be
+	// observed through its callbacks. Defaults to a private tracker.
+	// Snapshots are fed to it outside the iceberg's lock, so its callbacks
+	// may call Progress; they must not call Step, Run or Close.
+	Tracker *OrderTracker
+
+	// ClientOrderIdPrefix prefixes the client order ids of slices.
+	// Defaults to "ice-<symbol>".
+	ClientOrderIdPrefix string
+}
+
+// IcebergProgress is the state of an Iceberg.
+type IcebergProgress struct {
+	// Filled is the executed quantity and AvgPrice its average price.
+	// Remaining is what is left to execute, including the working slice.
+	Filled    float64
+	Remaining float64
+	AvgPrice  float64
+
+	// Slices is the number of slices placed so far.
+	Slices int
+
+	// Working is the last snapshot of the resting slice, nil between
+	// slices.
+	Working *t.BaseOrder
+
+	// Done is set once the quantity is executed, or what remains is below
+	// the market minimums and cannot be placed.
+	Done bool
+}
+
+// Iceberg works a large order by showing only a small LIMIT slice of it at
+// a time. When the slice fills, a new one of randomized size replaces it,
+// until the whole quantity is executed.
+//
+// Slices are followed through an OrderTracker: each Step polls the working
+// slice, feeds the snapshot to the tracker and accounts its fills once it
+// is closed. With a Peg the slice is also repriced as the book moves.
+// Slices are placed through EnsureOrder; a slice whose creation cannot be
+// confirmed is looked up by its client order id before the next one is
+// placed, so the quantity is never exceeded.
+//
+// Iceberg implements Closer and is safe for concurrent use.
+type Iceberg struct {
+	c       *Client
+	opts    IcebergOptions
+	tracker *OrderTracker
+
+	priceDecimals int
+	qtyDecimals   int
+	minQty        float64
+	minNotional   float64
+
+	mu       sync.Mutex
+	working  *t.BaseOrder
+	observed []t.BaseOrder
+	pending  string
+	price    float64
+	placedAt time.Time
+	filled   float64
+	notional float64
+	slices   int
+	done     bool
+	seq      atomic.Uint64
+
+	// deliverMu keeps the snapshots of consecutive steps in order while
+	// they are fed to the tracker.
+	deliverMu sync.Mutex
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewIceberg returns an Iceberg for opts. Tick and step sizes and the
+// market minimums are loaded from GET /v1/markets. Call Run, or Step
+// repeatedly, to work the order.
+func NewIceberg(ctx context.Context, c *Client, opts IcebergOptions) (*Iceberg, error) {
+	if opts.Side != t.SideBuy && opts.Side != t.SideSell {
+		return nil, invalidParam("side", opts.Side, "must be "+t.SideBuy+" or "+t.SideSell)
+	}
+	if opts.Quantity <= 0 || opts.SliceSize <= 0 || opts.Price < 0 || opts.Peg == PegNone && opts.Price == 0 {
+		return nil, &GoWallexError{
+			Message: "iceberg quantity and slice size must be positive, and a price is required without a peg",
+			Err:     nil,
+		}
+	}
+
+	symbol, err := c.resolveSymbol(ctx, opts.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	opts.Symbol = symbol
+
+	markets, err := c.getMarketsInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := markets.Get(symbol)
+	if !ok {
+		return nil, &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "unknown symbol " + symbol, Err: nil},
+			Symbol:        symbol,
+		}
+	}
+
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultIcebergInterval
+	}
+	if opts.ClientOrderIdPrefix == "" {
+		opts.ClientOrderIdPrefix = "ice-" + symbol
+	}
+	tracker := opts.Tracker
+	if tracker == nil {
+		tracker = NewOrderTracker()
+	}
+
+	return &Iceberg{
+		c:             c,
+		opts:          opts,
+		tracker:       tracker,
+		priceDecimals: int(info.TickSize),
+		qtyDecimals:   int(info.StepSize),
+		minQty:        info.MinQty.Float64(),
+		minNotional:   float64(info.MinNotional),
+		stop:          make(chan struct{}),
+	}, nil
+}
+
+// Progress returns the current state of the iceberg.
+func (ib *Iceberg) Progress() IcebergProgress {
+	ib.mu.Lock()
+	defer ib.mu.Unlock()
+
+	p := IcebergProgress{
+		Filled:    ib.filled,
+		Remaining: math.Max(0, ib.opts.Quantity-ib.filled),
+		Slices:    ib.slices,
+		Done:      ib.done,
+	}
+	if ib.filled > 0 {
+		p.AvgPrice = ib.notional / ib.filled
+	}
+	if ib.working != nil {
+		w := *ib.working
+		p.Working = &w
+	}
+	return p
+}
+
+// Step advances the iceberg once: it polls the working slice, reprices it
+// when pegged, and places the next slice when none is working. It reports
+// whether the iceberg is done.
+func (ib *Iceberg) Step(ctx context.Context) (bool, error) {
+	ib.mu.Lock()
+	defer ib.unlock()
+
+	if ib.done {
+		return true, nil
+	}
+
+	if ib.pending != "" {
+		if err := ib.resolve(ctx); err != nil || ib.pending != "" {
+			return false, err
+		}
+	}
+
+	if ib.working != nil {
+		resp, err := ib.c.getOrderStatus(ctx, ib.working.ClientOrderId)
+		if err != nil {
+			return false, err
+		}
+		ib.observe(resp.Result)
+	}
+
+	if ib.working != nil && (ib.opts.Peg == PegNone || time.Since(ib.placedAt) < ib.opts.MinReprice) {
+		return false, nil
+	}
+
+	price, err := ib.target(ctx)
+	if err != nil {
+		return false, err
+	}
+	if ib.working != nil {
+		if price == ib.price {
+			return false, nil
+		}
+		if err := ib.pull(ctx); err != nil || ib.working != nil {
+			return false, err
+		}
+	}
+
+	remaining := u.RoundDown(ib.opts.Quantity-ib.filled, ib.qtyDecimals)
+	if remaining <= 0 || remaining < ib.minQty || remaining*price < ib.minNotional {
+		ib.done = true
+		return true, nil
+	}
+	return false, ib.place(ctx, price, ib.sliceSize(remaining, price))
+}
+
+// target returns the price of the next slice.
+func (ib *Iceberg) target(ctx context.Context) (float64, error) {
+	if ib.opts.Peg == PegNone {
+		return ib.opts.Price, nil
+	}
+
+	depth, err := ib.c.getOrderBook(ctx, ib.opts.Symbol)
+	if err != nil {
+		return 0, err
+	}
+	price, ok := pegPrice(depth.Result, ib.opts.Side, ib.opts.Peg, ib.opts.PegOffsetTicks, ib.priceDecimals)
+	if !ok {
+		return 0, &GoWallexError{
+			Message: "cannot peg " + ib.opts.Symbol + ": book side empty",
+			Err:     nil,
+		}
+	}
+	if limit := ib.opts.Price; limit > 0 {
+		if ib.opts.Side == t.SideBuy {
+			price = math.Min(price, limit)
+		} else {
+			price = math.Max(price, limit)
+		}
+	}
+	return price, nil
+}
+
+// sliceSize returns the randomized size of the next slice. A slice that
+// would leave less than the market minimum behind takes the rest.
+func (ib *Iceberg) sliceSize(remaining, price float64) float64 {
+	size := ib.opts.SliceSize
+	if j := ib.opts.SliceJitter; j > 0 {
+		size *= 1 + (rand.Float64()*2-1)*math.Min(j, 0.99)
+	}
+	size = math.Max(u.RoundDown(size, ib.qtyDecimals), ib.minQty)
+	if left := remaining - size; left < ib.minQty || left*price < ib.minNotional {
+		size = remaining
+	}
+	return math.Min(size, remaining)
+}
+
+// place submits a slice through EnsureOrder. When its creation cannot be
+// confirmed, its client order id is kept so that the slice is looked up
+// before another one is placed. ib.mu must be held.
+func (ib *Iceberg) place(ctx context.Context, price, size float64) error {
+	ib.price = price
+	id := fmt.Sprintf("%s-%d-%d", ib.opts.ClientOrderIdPrefix, time.Now().UnixMilli(), ib.seq.Add(1))
+	resp, err := ib.c.EnsureOrder(ctx, t.CreateOrderParams{
+		Symbol:        ib.opts.Symbol,
+		Type:          t.OrderTypeLimit,
+		Side:          ib.opts.Side,
+		Price:         strconv.FormatFloat(price, 'f', ib.priceDecimals, 64),
+		Quantity:      strconv.FormatFloat(size, 'f', ib.qtyDecimals, 64),
+		ClientOrderId: id,
+	})
+	if err != nil {
+		var ambiguous *AmbiguousOrderError
+		if errors.As(err, &ambiguous) {
+			ib.pending = id
+		}
+		return err
+	}
+	ib.slices++
+	ib.placedAt = time.Now()
+	ib.observe(resp.Result)
+	return nil
+}
+
+// resolve looks up the slice whose creation could not be confirmed. It is
+// followed as the working slice when it exists and forgotten when Wallex
+// does not know it. ib.mu must be held.
+func (ib *Iceberg) resolve(ctx context.Context) error {
+	resp, err := ib.c.getOrderStatus(ctx, ib.pending)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			ib.pending = ""
+			return nil
+		}
+		return err
+	}
+	ib.pending = ""
+	ib.slices++
+	ib.placedAt = time.Now()
+	ib.observe(resp.Result)
+	return nil
+}
+
+// observe records a snapshot of the working slice, accounting its fills
+// once it is closed, and queues it for the tracker. ib.mu must be held.
+func (ib *Iceberg) observe(order t.BaseOrder) {
+	ib.observed = append(ib.observed, order)
+	if !IsTerminalStatus(order.Status) {
+		ib.working = &order
+		return
+	}
+	st := ComputeFillStats(order)
+	ib.filled += st.Quantity
+	ib.notional += st.Notional
+	ib.working = nil
+}
+
+// pull cancels the working slice, or the slice whose creation could not be
+// confirmed, and records its final state. A slice that is already closed on
+// the server is looked up instead. ib.mu must be held.
+func (ib *Iceberg) pull(ctx context.Context) error {
+	id := ib.pending
+	if ib.working != nil {
+		id = ib.working.ClientOrderId
+	}
+	if id == "" {
+		return nil
+	}
+
+	if _, err := ib.c.cancelOrder(ctx, id); err != nil {
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) || apiErr.StatusCode >= 500 || apiErr.StatusCode == 429 {
+			return err
+		}
+	}
+	if ib.pending != "" {
+		return ib.resolve(ctx)
+	}
+	resp, err := ib.c.getOrderStatus(ctx, id)
+	if err != nil {
+		return err
+	}
+	ib.observe(resp.Result)
+	return nil
+}
+
+// Run works the order, stepping every Interval, until it is done, ctx is
+// done, the iceberg is closed or the client is shut down. Step errors are
+// passed to onError when it is non-nil; a slice rejected by Wallex (a 4xx
+// response other than 429) stops Run with that error.
+//
+// Run returns nil once the order is done. Otherwise the working slice is
+// canceled and an error is returned.
+func (ib *Iceberg) Run(ctx context.Context, onError func(error)) error {
+	ib.loops.Add(1)
+	defer ib.loops.Done()
+
+	ticker := time.NewTicker(ib.opts.Interval)
+	defer ticker.Stop()
+
+	var stopErr error
+	for {
+		done, err := ib.Step(ctx)
+		if done {
+			return nil
+		}
+		if err != nil && ctx.Err() == nil {
+			var apiErr *APIError
+			if errors.As(err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != 429 {
+				stopErr = err
+				break
+			}
+			if onError != nil {
+				onError(err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			stopErr = ctx.Err()
+		case <-ib.stop:
+		case <-ib.c.Done():
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+
+	cancelCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancel()
+	ib.mu.Lock()
+	pullErr := ib.pull(cancelCtx)
+	ib.unlock()
+
+	return &GoWallexError{
+		Message: "iceberg stopped before completion",
+		Err:     errors.Join(stopErr, pullErr),
+	}
+}
+
+// Close implements Closer. It stops Run loops, waits for them to return and
+// cancels the working slice.
+func (ib *Iceberg) Close(ctx context.Context) error {
+	ib.stopOnce.Do(func() { close(ib.stop) })
+	if err := waitGroupDone(ctx, &ib.loops); err != nil {
+		return err
+	}
+	ib.mu.Lock()
+	defer ib.unlock()
+	return ib.pull(ctx)
+}
+
+// unlock releases ib.mu and feeds the snapshots observed while it was held
+// to the tracker. The tracker runs its callbacks synchronously, and they
+// may call back into the iceberg, so they must not run under ib.mu.
+func (ib *Iceberg) unlock() {
+	observed := ib.observed
+	ib.observed = nil
+	ib.deliverMu.Lock()
+	defer ib.deliverMu.Unlock()
+	ib.mu.Unlock()
+
+	for _, o := range observed {
+		ib.tracker.Update(o)
+	}
+}
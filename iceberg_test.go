@@ -0,0 +1,80 @@
+package wallex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-wallex/fixtures"
+	"github.com/darhelm/go-wallex/types"
+)
+
+// TestIcebergTrackerCallbackCallsProgress checks that a tracker callback
+// may call back into the iceberg that fed it the snapshot.
+func TestIcebergTrackerCallbackCallsProgress(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := ""
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/markets":
+			name = fixtures.Markets
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/account/orders":
+			name = fixtures.OrderCreate
+		default:
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(fixtures.MustLoad(name))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientOptions{BaseUrl: srv.URL, ApiKey: "key", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tracker := NewOrderTracker()
+	ib, err := NewIceberg(ctx, client, IcebergOptions{
+		Symbol:    "BTCUSDT",
+		Side:      types.SideBuy,
+		Quantity:  0.01,
+		Price:     60000,
+		SliceSize: 0.001,
+		Tracker:   tracker,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	progress := make(chan IcebergProgress, 1)
+	tracker.OnTransition(func(OrderTransition) {
+		progress <- ib.Progress()
+	})
+
+	stepped := make(chan error, 1)
+	go func() {
+		_, err := ib.Step(ctx)
+		stepped <- err
+	}()
+
+	select {
+	case err := <-stepped:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-ctx.Done():
+		t.Fatal("Step deadlocked on a callback calling Progress")
+	}
+	select {
+	case p := <-progress:
+		if p.Slices != 1 || p.Working == nil {
+			t.Errorf("progress seen by the callback = %+v, want one working slice", p)
+		}
+	default:
+		t.Fatal("the tracker callback was not called")
+	}
+}
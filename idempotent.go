@@ -0,0 +1,157 @@
+package wallex
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// DefaultEnsureOrderAttempts is the number of times EnsureOrder submits an
+// order that was confirmed not to exist.
+const DefaultEnsureOrderAttempts = 3
+
+// AmbiguousOrderError is returned by EnsureOrder when it cannot determine
+// whether the order was created, e.g. because ctx expired while Wallex was
+// unreachable. The order may exist; reconcile it by ClientOrderId before
+// placing it again.
+type AmbiguousOrderError struct {
+	GoWallexError
+	ClientOrderId string
+}
+
+// NewClientOrderId returns a random client order id suitable for
+// CreateOrderParams.ClientOrderId.
+func NewClientOrderId() string {
+	var b [12]byte
+	for i := 0; i < len(b); i += 8 {
+		v := rand.Uint64()
+		for j := 0; j < 8 && i+j < len(b); j++ {
+			b[i+j] = byte(v >> (8 * j))
+		}
+	}
+	return "gw-" + hex.EncodeToString(b[:])
+}
+
+// EnsureOrder places an order at most once, even when responses are lost.
+//
+// A CreateOrder whose outcome is unknown (a transport error, a timeout or a
+// 502/503/504 after the request was sent) is never blindly retried.
+// Instead EnsureOrder looks the order up by its clientOrderId:
+//   - if it exists, it is returned as if CreateOrder had succeeded;
+//   - if Wallex reports it as not found (HTTP 404), the order is submitted
+//     again, up to DefaultEnsureOrderAttempts times;
+//   - if the lookup fails too, it is repeated with the client Backoff until
+//     ctx is done, and an *AmbiguousOrderError is returned. A lookup
+//     rejected with a 4xx other than 404 and 429 is not repeated.
+//
+// If params.ClientOrderId is empty, one is generated with NewClientOrderId;
+// the returned order carries it. Rejections such as insufficient balance or
+// a failed PreTrade check are returned immediately.
+//
+// Authentication: REQUIRED.
+func (c *Client) EnsureOrder(ctx context.Context, params t.CreateOrderParams) (*t.BaseOrderResponse, error) {
+	if params.ClientOrderId == "" {
+		params.ClientOrderId = NewClientOrderId()
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < DefaultEnsureOrderAttempts; attempt++ {
+		created, err := c.createOrder(ctx, params)
+		if err == nil {
+			return created, nil
+		}
+		lastErr = err
+
+		// On resubmissions a rejection may be Wallex refusing the duplicate
+		// clientOrderId of an order an earlier attempt did create, so it is
+		// resolved by a lookup too.
+		ambiguous := isAmbiguous(err)
+		var apiErr *APIError
+		duplicate := attempt > 0 && !ambiguous && errors.As(err, &apiErr)
+		if !ambiguous && !duplicate {
+			return nil, err
+		}
+
+		existing, found, err := c.lookupOrder(ctx, params.ClientOrderId)
+		if err != nil {
+			return nil, &AmbiguousOrderError{
+				GoWallexError: GoWallexError{
+					Message: "order outcome unknown: " + lastErr.Error(),
+					Err:     err,
+				},
+				ClientOrderId: params.ClientOrderId,
+			}
+		}
+		if found {
+			return existing, nil
+		}
+		if duplicate {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+// lookupOrder queries the order until its existence is known or ctx is
+// done. found is false when Wallex answers 404. Client errors other than
+// 404 and 429 are returned at once.
+func (c *Client) lookupOrder(ctx context.Context, clientOrderId string) (*t.BaseOrderResponse, bool, error) {
+	backoff := c.Backoff
+	if backoff == nil {
+		backoff = DefaultBackoff()
+	}
+
+	var delay time.Duration
+	for attempt := 0; ; attempt++ {
+		status, err := c.getOrderStatus(ctx, clientOrderId)
+		if err == nil {
+			return status, true, nil
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			if apiErr.StatusCode == http.StatusNotFound {
+				return nil, false, nil
+			}
+			// Other client errors, e.g. a revoked key, will not go away.
+			if apiErr.StatusCode < 500 && apiErr.StatusCode != http.StatusTooManyRequests {
+				return nil, false, err
+			}
+		}
+
+		delay = backoff.Next(attempt, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, false, err
+		case <-timer.C:
+		}
+	}
+}
+
+// isAmbiguous reports whether a failed POST may nevertheless have been
+// executed by Wallex.
+func isAmbiguous(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.Operation {
+		case "sending request", "reading response", "parsing response":
+			return true
+		}
+	}
+	return false
+}
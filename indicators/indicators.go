@@ -0,0 +1,181 @@
+// Package indicators computes common technical indicators on candle series
+// returned by GET /v1/udf/history:
+//
+//	history, _ := client.GetCandles(types.CandleParams{Symbol: "BTCUSDT", Resolution: types.Resolution1h, From: from, To: to})
+//	candles := history.Candles()
+//	fast := indicators.EMA(candles.Closes(), 12)
+//	slow := indicators.EMA(candles.Closes(), 26)
+//	rsi := indicators.RSI(candles.Closes(), 14)
+//
+// Every function returns a slice aligned with its input: element i is the
+// indicator value as of input i. Positions before the indicator has enough
+// data (the warm-up period) are NaN; use math.IsNaN or Last to skip them.
+package indicators
+
+import (
+	"math"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// SMA returns the simple moving average of values over period.
+func SMA(values []float64, period int) []float64 {
+	out := nanSlice(len(values))
+	if period <= 0 || len(values) < period {
+		return out
+	}
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= period {
+			sum -= values[i-period]
+		}
+		if i >= period-1 {
+			out[i] = sum / float64(period)
+		}
+	}
+	return out
+}
+
+// EMA returns the exponential moving average of values over period, with
+// smoothing 2/(period+1), seeded with the SMA of the first period values.
+func EMA(values []float64, period int) []float64 {
+	return ema(values, period, 2/float64(period+1))
+}
+
+// ema is an exponential moving average with smoothing factor alpha, seeded
+// with the simple average of the first period values.
+func ema(values []float64, period int, alpha float64) []float64 {
+	out := nanSlice(len(values))
+	if period <= 0 || len(values) < period {
+		return out
+	}
+	var seed float64
+	for _, v := range values[:period] {
+		seed += v
+	}
+	prev := seed / float64(period)
+	out[period-1] = prev
+	for i := period; i < len(values); i++ {
+		prev += alpha * (values[i] - prev)
+		out[i] = prev
+	}
+	return out
+}
+
+// RSI returns Wilder's relative strength index of values over period, in
+// [0, 100]. The first value is available at index period.
+func RSI(values []float64, period int) []float64 {
+	out := nanSlice(len(values))
+	if period <= 0 || len(values) <= period {
+		return out
+	}
+
+	var gain, loss float64
+	for i := 1; i <= period; i++ {
+		d := values[i] - values[i-1]
+		if d > 0 {
+			gain += d
+		} else {
+			loss -= d
+		}
+	}
+	gain /= float64(period)
+	loss /= float64(period)
+	out[period] = rsi(gain, loss)
+
+	for i := period + 1; i < len(values); i++ {
+		d := values[i] - values[i-1]
+		g, l := 0.0, 0.0
+		if d > 0 {
+			g = d
+		} else {
+			l = -d
+		}
+		gain = (gain*float64(period-1) + g) / float64(period)
+		loss = (loss*float64(period-1) + l) / float64(period)
+		out[i] = rsi(gain, loss)
+	}
+	return out
+}
+
+func rsi(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// TrueRange returns the true range of every candle: the largest of
+// high-low and the distances of high and low from the previous close. The
+// first candle has no previous close and uses high-low.
+func TrueRange(candles t.Candles) []float64 {
+	out := make([]float64, len(candles))
+	for i, c := range candles {
+		tr := c.High - c.Low
+		if i > 0 {
+			prev := candles[i-1].Close
+			tr = math.Max(tr, math.Max(math.Abs(c.High-prev), math.Abs(c.Low-prev)))
+		}
+		out[i] = tr
+	}
+	return out
+}
+
+// ATR returns Wilder's average true range over period.
+func ATR(candles t.Candles, period int) []float64 {
+	return ema(TrueRange(candles), period, 1/float64(period))
+}
+
+// Bands holds Bollinger bands aligned with the input series.
+type Bands struct {
+	Upper  []float64
+	Middle []float64
+	Lower  []float64
+}
+
+// Bollinger returns Bollinger bands of values: the SMA over period plus and
+// minus k population standard deviations. k is usually 2.
+func Bollinger(values []float64, period int, k float64) Bands {
+	b := Bands{
+		Upper:  nanSlice(len(values)),
+		Middle: SMA(values, period),
+		Lower:  nanSlice(len(values)),
+	}
+	for i := range values {
+		mean := b.Middle[i]
+		if math.IsNaN(mean) {
+			continue
+		}
+		var sq float64
+		for _, v := range values[i-period+1 : i+1] {
+			sq += (v - mean) * (v - mean)
+		}
+		sd := math.Sqrt(sq / float64(period))
+		b.Upper[i] = mean + k*sd
+		b.Lower[i] = mean - k*sd
+	}
+	return b
+}
+
+// Last returns the most recent non-NaN value of series, and false if there
+// is none.
+func Last(series []float64) (float64, bool) {
+	for i := len(series) - 1; i >= 0; i-- {
+		if !math.IsNaN(series[i]) {
+			return series[i], true
+		}
+	}
+	return 0, false
+}
+
+func nanSlice(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
@@ -0,0 +1,130 @@
+package wallex
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// BookIssueKind classifies an order book integrity problem.
+type BookIssueKind string
+
+const (
+	// IssueCrossed: the best bid is at or above the best ask.
+	IssueCrossed BookIssueKind = "crossed"
+
+	// IssueNonPositiveSize: a level has a zero or negative quantity.
+	IssueNonPositiveSize BookIssueKind = "non_positive_size"
+
+	// IssueNonPositivePrice: a level has a zero or negative price.
+	IssueNonPositivePrice BookIssueKind = "non_positive_price"
+
+	// IssueUnsorted: bids are not descending or asks not ascending.
+	IssueUnsorted BookIssueKind = "unsorted"
+
+	// IssueDuplicateLevel: the same price appears twice on one side.
+	IssueDuplicateLevel BookIssueKind = "duplicate_level"
+
+	// IssueStale: no valid book was received within the staleness window.
+	IssueStale BookIssueKind = "stale"
+)
+
+// BookIssue is a single integrity problem found in an order book.
+type BookIssue struct {
+	Kind BookIssueKind
+
+	// Side is "bid" or "ask"; empty for book-wide issues.
+	Side string
+
+	// Level is the index of the offending level on Side, or -1.
+	Level int
+
+	Detail string
+}
+
+func (i BookIssue) String() string {
+	if i.Side == "" {
+		return fmt.Sprintf("%s: %s", i.Kind, i.Detail)
+	}
+	return fmt.Sprintf("%s at %s[%d]: %s", i.Kind, i.Side, i.Level, i.Detail)
+}
+
+// IntegrityError reports that an order book failed verification.
+type IntegrityError struct {
+	GoWallexError
+	Symbol string
+	Issues []BookIssue
+}
+
+func newIntegrityError(symbol string, issues []BookIssue) *IntegrityError {
+	parts := make([]string, len(issues))
+	for i, issue := range issues {
+		parts[i] = issue.String()
+	}
+	return &IntegrityError{
+		GoWallexError: GoWallexError{
+			Message: "order book " + symbol + " failed verification: " + strings.Join(parts, "; "),
+			Err:     nil,
+		},
+		Symbol: symbol,
+		Issues: issues,
+	}
+}
+
+// IntegrityEvent is a diagnostic emitted by book watchers when
+// verification fails. The watcher discards the offending book and
+// resyncs from a fresh snapshot at MinInterval.
+type IntegrityEvent struct {
+	Symbol     string
+	Issues     []BookIssue
+	Book       t.OrderBook
+	DetectedAt time.Time
+
+	// LastValid is when the last book that passed verification arrived.
+	LastValid time.Time
+}
+
+// VerifyOrderBook checks book for structural problems: crossed top of
+// book, non-positive prices or sizes, wrongly ordered and duplicate levels.
+// It returns nil for a consistent book. An empty side is not an issue.
+//
+// Wallex's REST depth carries neither checksums nor sequence numbers, so
+// verification is limited to what can be inferred from the book itself.
+func VerifyOrderBook(book t.OrderBook) []BookIssue {
+	var issues []BookIssue
+	issues = verifySide(issues, "bid", book.Bid, func(prev, cur float64) bool { return cur < prev })
+	issues = verifySide(issues, "ask", book.Ask, func(prev, cur float64) bool { return cur > prev })
+
+	if len(book.Bid) > 0 && len(book.Ask) > 0 && book.Bid[0].Price >= book.Ask[0].Price {
+		issues = append(issues, BookIssue{
+			Kind:   IssueCrossed,
+			Level:  -1,
+			Detail: fmt.Sprintf("best bid %g >= best ask %g", book.Bid[0].Price, book.Ask[0].Price),
+		})
+	}
+	return issues
+}
+
+func verifySide(issues []BookIssue, side string, levels []t.Order, ordered func(prev, cur float64) bool) []BookIssue {
+	for i, lv := range levels {
+		if lv.Price <= 0 {
+			issues = append(issues, BookIssue{Kind: IssueNonPositivePrice, Side: side, Level: i, Detail: fmt.Sprintf("price %g", lv.Price)})
+		}
+		if q := lv.Quantity.Float64(); q <= 0 {
+			issues = append(issues, BookIssue{Kind: IssueNonPositiveSize, Side: side, Level: i, Detail: fmt.Sprintf("quantity %g", q)})
+		}
+		if i == 0 {
+			continue
+		}
+		prev := levels[i-1].Price
+		switch {
+		case lv.Price == prev:
+			issues = append(issues, BookIssue{Kind: IssueDuplicateLevel, Side: side, Level: i, Detail: fmt.Sprintf("price %g repeated", lv.Price)})
+		case !ordered(prev, lv.Price):
+			issues = append(issues, BookIssue{Kind: IssueUnsorted, Side: side, Level: i, Detail: fmt.Sprintf("price %g after %g", lv.Price, prev)})
+		}
+	}
+	return issues
+}
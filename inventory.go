@@ -0,0 +1,287 @@
+package wallex
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/darhelm/go-wallex/risk"
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// MarketInventory is the inventory of one market's base and quote assets.
+//
+// Base and Quote are total holdings; the Locked amounts are what Wallex
+// reports as reserved by open orders and withdrawals. Pending amounts are
+// reserved locally for orders that were submitted but are not yet reflected
+// in the balances.
+type MarketInventory struct {
+	Symbol     string
+	BaseAsset  string
+	QuoteAsset string
+
+	Base        float64
+	BaseLocked  float64
+	PendingBase float64
+
+	Quote        float64
+	QuoteLocked  float64
+	PendingQuote float64
+
+	// OpenBuyNotional and OpenSellQty summarize the resting orders of the
+	// market as of the last refresh.
+	OpenBuyNotional float64
+	OpenSellQty     float64
+	OpenOrders      int
+
+	UpdatedAt time.Time
+}
+
+// AvailableBase returns the base quantity that can be sold right now.
+func (m MarketInventory) AvailableBase() float64 {
+	return nonNegative(m.Base - m.BaseLocked - m.PendingBase)
+}
+
+// AvailableQuote returns the quote amount that can be spent right now.
+func (m MarketInventory) AvailableQuote() float64 {
+	return nonNegative(m.Quote - m.QuoteLocked - m.PendingQuote)
+}
+
+func nonNegative(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	return v
+}
+
+// reservation is funds held for an order between submission and the
+// balance refresh that reflects it.
+type reservation struct {
+	asset      string
+	amount     float64
+	releasedAt time.Time
+}
+
+// InventoryTracker tracks per-market inventory from balances and open
+// orders, and accounts for orders that are still in flight.
+//
+// Call Reserve before submitting an order and Release once Wallex has
+// answered. Released reservations are kept until the next Refresh that
+// started after the release, so the funds are never counted as available
+// twice between the order being accepted and the balances showing it as
+// locked.
+//
+// InventoryTracker implements risk.Exposure, so a risk.Checker can be
+// backed by its cached state instead of querying Wallex on every order.
+// It is safe for concurrent use.
+type InventoryTracker struct {
+	c *Client
+
+	mu       sync.Mutex
+	markets  map[string]t.SymbolInfo
+	balances map[string]t.Balance
+	open     map[string][]t.BaseOrder
+	reserved map[uint64]reservation
+	nextID   uint64
+	updated  time.Time
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+var _ risk.Exposure = (*InventoryTracker)(nil)
+
+// NewInventoryTracker returns an empty tracker. Call Refresh or Run to
+// populate it.
+func NewInventoryTracker(c *Client) *InventoryTracker {
+	return &InventoryTracker{
+		c:        c,
+		reserved: make(map[uint64]reservation),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Refresh reloads balances and open orders. Market metadata is loaded on
+// the first call.
+func (it *InventoryTracker) Refresh(ctx context.Context) error {
+	started := time.Now()
+
+	it.mu.Lock()
+	needMarkets := it.markets == nil
+	it.mu.Unlock()
+
+	var markets map[string]t.SymbolInfo
+	if needMarkets {
+		info, err := it.c.getMarketsInfo(ctx)
+		if err != nil {
+			return err
+		}
+		markets = info.Result.Symbols
+	}
+
+	wallets, err := it.c.getWallets(ctx)
+	if err != nil {
+		return err
+	}
+	open, err := it.c.getOpenOrders(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	if markets != nil {
+		it.markets = markets
+	}
+	it.balances = wallets.Result.Balances
+	it.open = open.BySymbol()
+	it.updated = started
+	for id, r := range it.reserved {
+		if !r.releasedAt.IsZero() && r.releasedAt.Before(started) {
+			delete(it.reserved, id)
+		}
+	}
+	return nil
+}
+
+// Run calls Refresh every interval until ctx is done, the tracker is closed
+// or the client is shut down. Errors are passed to onError when it is
+// non-nil.
+func (it *InventoryTracker) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	it.loops.Add(1)
+	defer it.loops.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := it.Refresh(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-it.stop:
+			return
+		case <-it.c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close implements Closer. It stops Run loops and waits for them to return.
+func (it *InventoryTracker) Close(ctx context.Context) error {
+	it.stopOnce.Do(func() { close(it.stop) })
+	return waitGroupDone(ctx, &it.loops)
+}
+
+// Reserve holds the funds params would lock: the quote notional for a BUY,
+// the base quantity for a SELL. MARKET buys without a price reserve
+// nothing. The returned id must be passed to Release.
+func (it *InventoryTracker) Reserve(params t.CreateOrderParams) uint64 {
+	qty, _ := strconv.ParseFloat(params.Quantity, 64)
+	price, _ := strconv.ParseFloat(params.Price, 64)
+
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	it.nextID++
+	id := it.nextID
+
+	info, ok := it.markets[params.Symbol]
+	if !ok {
+		return id
+	}
+	r := reservation{asset: info.BaseAsset, amount: qty}
+	if params.Side == t.SideBuy {
+		r = reservation{asset: info.QuoteAsset, amount: qty * price}
+	}
+	it.reserved[id] = r
+	return id
+}
+
+// Release marks a reservation as answered by Wallex. Rejected orders
+// should pass accepted=false so the funds are freed immediately.
+func (it *InventoryTracker) Release(id uint64, accepted bool) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	r, ok := it.reserved[id]
+	if !ok {
+		return
+	}
+	if !accepted {
+		delete(it.reserved, id)
+		return
+	}
+	r.releasedAt = time.Now()
+	it.reserved[id] = r
+}
+
+// Inventory returns the inventory of symbol. It reports false until market
+// metadata has been loaded or if the symbol is unknown.
+func (it *InventoryTracker) Inventory(symbol string) (MarketInventory, bool) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+
+	info, ok := it.markets[symbol]
+	if !ok {
+		return MarketInventory{}, false
+	}
+
+	base := it.balances[info.BaseAsset]
+	quote := it.balances[info.QuoteAsset]
+	inv := MarketInventory{
+		Symbol:      symbol,
+		BaseAsset:   info.BaseAsset,
+		QuoteAsset:  info.QuoteAsset,
+		Base:        base.Total(),
+		BaseLocked:  base.LockedAmount(),
+		Quote:       quote.Total(),
+		QuoteLocked: quote.LockedAmount(),
+		OpenOrders:  len(it.open[symbol]),
+		UpdatedAt:   it.updated,
+	}
+	for _, r := range it.reserved {
+		switch r.asset {
+		case info.BaseAsset:
+			inv.PendingBase += r.amount
+		case info.QuoteAsset:
+			inv.PendingQuote += r.amount
+		}
+	}
+	for _, o := range it.open[symbol] {
+		if o.Side == t.SideBuy {
+			inv.OpenBuyNotional += o.OpenNotional()
+		} else {
+			inv.OpenSellQty += o.RemainingQty()
+		}
+	}
+	return inv, true
+}
+
+// OpenOrderCount implements risk.Exposure from the last refresh.
+func (it *InventoryTracker) OpenOrderCount(_ context.Context, symbol string) (int, error) {
+	it.mu.Lock()
+	defer it.mu.Unlock()
+	return len(it.open[symbol]), nil
+}
+
+// Position implements risk.Exposure: the base-asset holdings of symbol as
+// of the last refresh.
+func (it *InventoryTracker) Position(_ context.Context, symbol string) (float64, error) {
+	inv, ok := it.Inventory(symbol)
+	if !ok {
+		return 0, &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "inventory of " + symbol + " is not loaded", Err: nil},
+			Symbol:        symbol,
+		}
+	}
+	return inv.Base, nil
+}
+
+// MarketPrice implements risk.Exposure by querying the order book.
+func (it *InventoryTracker) MarketPrice(ctx context.Context, symbol, side string) (float64, error) {
+	return clientExposure{c: it.c}.MarketPrice(ctx, symbol, side)
+}
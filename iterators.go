@@ -0,0 +1,176 @@
+package wallex
+
+import (
+	"context"
+	"iter"
+	"reflect"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// DefaultPageSize is the page size requested by the iterators when the
+// params leave PerPage unset.
+const DefaultPageSize = 50
+
+// pageFunc fetches one page of a paginated collection.
+type pageFunc[T any] func(ctx context.Context, page, perPage int) ([]T, t.PageInfo, error)
+
+// paginate turns a pageFunc into an iterator starting at page start.
+//
+// Iteration stops when the server reports no further pages. Responses
+// without pagination info end after the first short page. A page equal to
+// the one before it also ends the iteration, without being yielded again,
+// so a server ignoring the paging parameters cannot loop forever. A fetch
+// error is yielded once with a zero value and ends the iteration; so does
+// ctx being done.
+func paginate[T any](ctx context.Context, start, perPage int, fetch pageFunc[T]) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for items, err := range pages(ctx, start, perPage, fetch) {
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// pages is paginate yielding whole pages instead of single items.
+func pages[T any](ctx context.Context, start, perPage int, fetch pageFunc[T]) iter.Seq2[[]T, error] {
+	if start < 1 {
+		start = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultPageSize
+	}
+
+	return func(yield func([]T, error) bool) {
+		var prev []T
+		for page := start; ; page++ {
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+
+			items, info, err := fetch(ctx, page, perPage)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if page > start && len(items) > 0 && reflect.DeepEqual(items, prev) {
+				return
+			}
+			prev = items
+			if !yield(items, nil) {
+				return
+			}
+
+			if info.TotalCount > 0 || info.PerPage > 0 {
+				if !info.HasNext() {
+					return
+				}
+			} else if len(items) < perPage {
+				return
+			}
+		}
+	}
+}
+
+// UserTrades returns an iterator over the account's trade history matching
+// params, fetching pages of GET /v1/account/trades on demand:
+//
+//	for trade, err := range client.UserTrades(ctx, types.UserTradesParams{Symbol: "BTCUSDT"}) {
+//	    if err != nil {
+//	        return err
+//	    }
+//	    ...
+//	}
+//
+// params.Page sets the first page (default 1) and params.PerPage the page
+// size (default DefaultPageSize). Breaking out of the loop stops fetching.
+func (c *Client) UserTrades(ctx context.Context, params t.UserTradesParams) iter.Seq2[t.UserTrade, error] {
+	return paginate(ctx, params.Page, params.PerPage, c.userTradesPage(params))
+}
+
+// userTradesPage returns the pageFunc behind UserTrades.
+func (c *Client) userTradesPage(params t.UserTradesParams) pageFunc[t.UserTrade] {
+	return func(ctx context.Context, page, perPage int) ([]t.UserTrade, t.PageInfo, error) {
+		p := params
+		p.Page, p.PerPage = page, perPage
+		resp, err := c.getUserTrades(ctx, p)
+		if err != nil {
+			return nil, t.PageInfo{}, err
+		}
+		return resp.Result.AccountLatestTrades, resp.ResultInfo, nil
+	}
+}
+
+// OrderHistory returns an iterator over the account's orders matching
+// params, fetching pages of GET /v1/account/orders/history on demand.
+// Paging behaves as in UserTrades.
+func (c *Client) OrderHistory(ctx context.Context, params t.OrderHistoryParams) iter.Seq2[t.BaseOrder, error] {
+	return paginate(ctx, params.Page, params.PerPage, func(ctx context.Context, page, perPage int) ([]t.BaseOrder, t.PageInfo, error) {
+		p := params
+		p.Page, p.PerPage = page, perPage
+		resp, err := c.getOrderHistory(ctx, p)
+		if err != nil {
+			return nil, t.PageInfo{}, err
+		}
+		return resp.Result.Orders, resp.ResultInfo, nil
+	})
+}
+
+// FiatDeposits returns an iterator over the account's Toman deposits,
+// fetching pages of GET /v1/account/money-deposit on demand. Paging behaves
+// as in UserTrades.
+func (c *Client) FiatDeposits(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error] {
+	return c.fiatHistory(ctx, "/account/money-deposit", params)
+}
+
+// FiatWithdrawals returns an iterator over the account's Toman withdrawals,
+// fetching pages of GET /v1/account/money-withdrawal on demand. Paging
+// behaves as in UserTrades.
+func (c *Client) FiatWithdrawals(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error] {
+	return c.fiatHistory(ctx, "/account/money-withdrawal", params)
+}
+
+// CryptoDeposits returns an iterator over the account's on-chain deposits
+// matching params, fetching pages of GET /v1/account/crypto-deposit on
+// demand. Paging behaves as in UserTrades.
+func (c *Client) CryptoDeposits(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error] {
+	return c.cryptoHistory(ctx, "/account/crypto-deposit", params)
+}
+
+// CryptoWithdrawals returns an iterator over the account's on-chain
+// withdrawals matching params, fetching pages of GET
+// /v1/account/crypto-withdrawal on demand. Paging behaves as in UserTrades.
+func (c *Client) CryptoWithdrawals(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error] {
+	return c.cryptoHistory(ctx, "/account/crypto-withdrawal", params)
+}
+
+func (c *Client) cryptoHistory(ctx context.Context, endpoint string, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error] {
+	return paginate(ctx, params.Page, params.PerPage, func(ctx context.Context, page, perPage int) ([]t.CryptoTransfer, t.PageInfo, error) {
+		p := params
+		p.Page, p.PerPage = page, perPage
+		resp, err := c.getCryptoHistory(ctx, endpoint, p)
+		if err != nil {
+			return nil, t.PageInfo{}, err
+		}
+		return resp.Result, resp.ResultInfo, nil
+	})
+}
+
+func (c *Client) fiatHistory(ctx context.Context, endpoint string, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error] {
+	return paginate(ctx, params.Page, params.PerPage, func(ctx context.Context, page, perPage int) ([]t.FiatTransfer, t.PageInfo, error) {
+		resp, err := c.getFiatHistory(ctx, endpoint, t.HistoryParams{Page: page, PerPage: perPage})
+		if err != nil {
+			return nil, t.PageInfo{}, err
+		}
+		return resp.Result, resp.ResultInfo, nil
+	})
+}
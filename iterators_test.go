@@ -0,0 +1,64 @@
+package wallex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-wallex/fixtures"
+	"github.com/darhelm/go-wallex/types"
+)
+
+// TestPaginateServerIgnoringPaging checks that iteration ends when the
+// server answers every page with the same full page and no result_info.
+func TestPaginateServerIgnoringPaging(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/account/trades" {
+			http.NotFound(w, r)
+			return
+		}
+		requests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(fixtures.MustLoad(fixtures.UserTrades))
+	}))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(ClientOptions{BaseUrl: srv.URL, ApiKey: "key", MaxRetries: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// The fixture holds two trades, so every page looks full.
+	params := types.UserTradesParams{PerPage: 2}
+	var got int
+	for _, err := range client.UserTrades(ctx, params) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got++
+	}
+	if got != 2 {
+		t.Errorf("iterated %d trades, want 2", got)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("fetched %d pages, want 2", n)
+	}
+
+	syncer := NewTradeSyncer(client, NewMemoryCursorStore(), params)
+	var delivered int
+	if err := syncer.Sync(ctx, func(trades []types.UserTrade) error {
+		delivered += len(trades)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if delivered != 2 {
+		t.Errorf("Sync delivered %d trades, want 2", delivered)
+	}
+}
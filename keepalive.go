@@ -0,0 +1,166 @@
+package wallex
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Keep-alive defaults used by ClientOptions.KeepAlive and NewConnectionWarmer.
+// The interval stays well below DefaultIdleConnTimeout and the typical
+// server-side idle timeout, so warm connections are never reaped.
+const (
+	DefaultKeepAliveInterval = 30 * time.Second
+	DefaultKeepAliveConns    = 2
+)
+
+// ConnectionWarmer keeps TLS connections to the API open during quiet
+// periods, so the first order after a pause does not pay for a TCP and TLS
+// handshake.
+//
+// Every interval it checks whether the client sent any request since the
+// last check. If not, it issues Conns concurrent HEAD requests to the base
+// URL: they are unauthenticated, carry no payload and leave Conns
+// connections in the idle pool. The pings go through the client
+// RateLimiter, so they share the rate budget with regular requests.
+//
+// Connections can only be kept if the transport pools them; with a custom
+// HttpClient make sure its MaxIdleConnsPerHost is at least Conns.
+//
+// ConnectionWarmer implements Closer and is safe for concurrent use.
+type ConnectionWarmer struct {
+	client   *Client
+	interval time.Duration
+	conns    int
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewConnectionWarmer creates a ConnectionWarmer for c. Non-positive
+// arguments select DefaultKeepAliveInterval and DefaultKeepAliveConns.
+// Start it with Run.
+func NewConnectionWarmer(c *Client, interval time.Duration, conns int) *ConnectionWarmer {
+	if interval <= 0 {
+		interval = DefaultKeepAliveInterval
+	}
+	if conns <= 0 {
+		conns = DefaultKeepAliveConns
+	}
+	return &ConnectionWarmer{
+		client:   c,
+		interval: interval,
+		conns:    conns,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Warm opens, or refreshes, the warmer's connections immediately. Call it
+// once at startup to have the connections ready before the first order.
+// The first failed ping is returned; warming is best effort and failures
+// do not affect regular requests.
+func (w *ConnectionWarmer) Warm(ctx context.Context) error {
+	errs := make(chan error, w.conns)
+	for i := 0; i < w.conns; i++ {
+		go func() { errs <- w.client.ping(ctx) }()
+	}
+
+	var firstErr error
+	for i := 0; i < w.conns; i++ {
+		if err := <-errs; err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Run warms the connections, then keeps them warm until ctx is done, the
+// warmer is closed or the client is shut down. Ping errors are ignored.
+func (w *ConnectionWarmer) Run(ctx context.Context) {
+	w.loops.Add(1)
+	defer w.loops.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	_ = w.Warm(ctx)
+	last := w.client.lastRequest.Load()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			return
+		case <-w.client.Done():
+			return
+		case <-ticker.C:
+		}
+
+		// Regular traffic keeps the pool warm on its own.
+		if seen := w.client.lastRequest.Load(); seen != last {
+			last = seen
+			continue
+		}
+		_ = w.Warm(ctx)
+		last = w.client.lastRequest.Load()
+	}
+}
+
+// Close implements Closer. It stops Run and waits for pings in progress.
+func (w *ConnectionWarmer) Close(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+	return waitGroupDone(ctx, &w.loops)
+}
+
+// ping sends a HEAD request to the base URL and drains the response so the
+// connection returns to the idle pool.
+func (c *Client) ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, c.baseUrl+"/", http.NoBody)
+	if err != nil {
+		return &RequestError{
+			GoWallexError: GoWallexError{
+				Message: "failed to create keep-alive request",
+				Err:     err,
+			},
+			Operation: "creating request",
+		}
+	}
+
+	if c.RateLimiter != nil {
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return &RequestError{
+				GoWallexError: GoWallexError{
+					Message: "rate limiter wait aborted",
+					Err:     err,
+				},
+				Operation: "waiting for rate limiter",
+			}
+		}
+	}
+
+	c.touch()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return &RequestError{
+			GoWallexError: GoWallexError{
+				Message: "failed to send keep-alive request",
+				Err:     err,
+			},
+			Operation: "sending request",
+		}
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+// touch records that a request is being sent.
+func (c *Client) touch() {
+	c.lastRequest.Store(time.Now().UnixNano())
+}
@@ -0,0 +1,97 @@
+package wallex
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// KeyProvider supplies the API key of a client. It is asked once per
+// request, before the first attempt, so every retry of a request uses the
+// same key. Implementations must be safe for concurrent use and should
+// not block; fetch keys from a secret store in the background.
+type KeyProvider interface {
+	ApiKey(ctx context.Context) (string, error)
+}
+
+// StaticKey is a KeyProvider returning a fixed key.
+type StaticKey string
+
+// ApiKey implements KeyProvider.
+func (k StaticKey) ApiKey(context.Context) (string, error) {
+	return string(k), nil
+}
+
+// RotatingKey is a KeyProvider whose key can be replaced atomically while
+// requests are in flight: requests already started keep the old key, new
+// requests use the new one. It is the provider of clients created without
+// ClientOptions.KeyProvider; rotate their key with Client.SetApiKey.
+type RotatingKey struct {
+	key atomic.Pointer[string]
+}
+
+// NewRotatingKey returns a RotatingKey holding key.
+func NewRotatingKey(key string) *RotatingKey {
+	r := new(RotatingKey)
+	r.Set(key)
+	return r
+}
+
+// ApiKey implements KeyProvider.
+func (r *RotatingKey) ApiKey(context.Context) (string, error) {
+	return r.Current(), nil
+}
+
+// Current returns the current key.
+func (r *RotatingKey) Current() string {
+	if k := r.key.Load(); k != nil {
+		return *k
+	}
+	return ""
+}
+
+// Set replaces the key.
+func (r *RotatingKey) Set(key string) {
+	r.key.Store(&key)
+}
+
+// SetApiKey validates key with GET /v1/account/balances and, if Wallex
+// accepts it, makes it the key of c and of the clients sharing its key
+// (see With). Requests in flight finish with the old key. On failure the
+// old key stays in use and the probe's error is returned.
+//
+// SetApiKey requires the client's key to be managed by a RotatingKey,
+// which is the case unless ClientOptions.KeyProvider was set; custom
+// providers rotate their keys themselves.
+//
+// Authentication: REQUIRED.
+func (c *Client) SetApiKey(ctx context.Context, key string) error {
+	rk, ok := c.keys.(*RotatingKey)
+	if !ok {
+		return &GoWallexError{
+			Message: "API key is managed by a custom KeyProvider",
+			Err:     nil,
+		}
+	}
+	if err := assertAuth(key); err != nil {
+		return err
+	}
+
+	probe := c.With(WithApiKey(key))
+	if _, err := probe.getWallets(ctx); err != nil {
+		return &GoWallexError{
+			Message: "new API key failed validation",
+			Err:     err,
+		}
+	}
+	rk.Set(key)
+	c.metrics().Add("wallex_key_rotations_total", 1)
+	return nil
+}
+
+// apiKey resolves the key for a request.
+func (c *Client) apiKey(ctx context.Context) (string, error) {
+	if c.keys == nil {
+		return "", nil
+	}
+	return c.keys.ApiKey(ctx)
+}
@@ -0,0 +1,167 @@
+package wallex
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultLatencyWindow is the number of most recent attempts per endpoint
+// that latency percentiles are computed from.
+const DefaultLatencyWindow = 1024
+
+// LatencyStats summarizes the attempt latencies of one endpoint.
+//
+// Count and Errors cover the lifetime of the client (or since
+// ResetLatency); the remaining fields are computed over the last Window
+// attempts, so they follow recent degradations of Wallex or the network.
+type LatencyStats struct {
+	// Endpoint is the endpoint key, e.g. EndpointDepth.
+	Endpoint string
+
+	// Count is the number of attempts recorded.
+	Count uint64
+
+	// Errors is the number of attempts that failed.
+	Errors uint64
+
+	// Window is the number of samples the fields below are computed from.
+	Window int
+
+	Min  time.Duration
+	Max  time.Duration
+	Mean time.Duration
+	P50  time.Duration
+	P95  time.Duration
+	P99  time.Duration
+}
+
+// latencyRecorder keeps a ring of recent attempt latencies per endpoint.
+// Its zero value is ready to use.
+type latencyRecorder struct {
+	mu        sync.Mutex
+	endpoints map[string]*latencyRing
+}
+
+type latencyRing struct {
+	samples []time.Duration
+	next    int
+	count   uint64
+	errors  uint64
+}
+
+func (r *latencyRecorder) record(endpoint string, took time.Duration, failed bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.endpoints == nil {
+		r.endpoints = make(map[string]*latencyRing)
+	}
+	ring, ok := r.endpoints[endpoint]
+	if !ok {
+		ring = &latencyRing{samples: make([]time.Duration, 0, 64)}
+		r.endpoints[endpoint] = ring
+	}
+
+	ring.count++
+	if failed {
+		ring.errors++
+	}
+	if len(ring.samples) < DefaultLatencyWindow {
+		ring.samples = append(ring.samples, took)
+		return
+	}
+	ring.samples[ring.next] = took
+	ring.next = (ring.next + 1) % DefaultLatencyWindow
+}
+
+func (r *latencyRecorder) stats(endpoint string) (LatencyStats, bool) {
+	r.mu.Lock()
+	ring, ok := r.endpoints[endpoint]
+	if !ok {
+		r.mu.Unlock()
+		return LatencyStats{}, false
+	}
+	samples := append([]time.Duration(nil), ring.samples...)
+	st := LatencyStats{Endpoint: endpoint, Count: ring.count, Errors: ring.errors, Window: len(samples)}
+	r.mu.Unlock()
+
+	if len(samples) == 0 {
+		return st, true
+	}
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+
+	var sum time.Duration
+	for _, s := range samples {
+		sum += s
+	}
+	st.Min = samples[0]
+	st.Max = samples[len(samples)-1]
+	st.Mean = sum / time.Duration(len(samples))
+	st.P50 = durationPercentile(samples, 0.50)
+	st.P95 = durationPercentile(samples, 0.95)
+	st.P99 = durationPercentile(samples, 0.99)
+	return st, true
+}
+
+// durationPercentile returns the p-th percentile of sorted durations using
+// the nearest-rank method, like percentile.
+func durationPercentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
+
+// recordLatency records one request attempt and publishes it as the
+// wallex_request_duration_seconds distribution, labelled with the endpoint
+// and the outcome ("ok" or "error").
+func (c *Client) recordLatency(endpoint string, took time.Duration, err error) {
+	c.latency.record(endpoint, took, err != nil)
+
+	outcome := "ok"
+	if err != nil {
+		outcome = "error"
+	}
+	c.metrics().Observe("wallex_request_duration_seconds", took.Seconds(),
+		Label{Name: "endpoint", Value: endpoint},
+		Label{Name: "outcome", Value: outcome},
+	)
+}
+
+// Latency returns a snapshot of the latency distribution of every endpoint
+// the client has called, sorted by endpoint key. Every HTTP attempt that
+// was sent is a sample, including retried and failed ones. Samples cover
+// the round-trip only, from sending the request to reading the response;
+// the time spent waiting for the RateLimiter is not included.
+func (c *Client) Latency() []LatencyStats {
+	c.latency.mu.Lock()
+	keys := make([]string, 0, len(c.latency.endpoints))
+	for k := range c.latency.endpoints {
+		keys = append(keys, k)
+	}
+	c.latency.mu.Unlock()
+	sort.Strings(keys)
+
+	out := make([]LatencyStats, 0, len(keys))
+	for _, k := range keys {
+		if st, ok := c.latency.stats(k); ok {
+			out = append(out, st)
+		}
+	}
+	return out
+}
+
+// EndpointLatency returns the latency snapshot of a single endpoint, e.g.
+// EndpointCreateOrder. It reports false if the endpoint was never called.
+func (c *Client) EndpointLatency(endpoint string) (LatencyStats, bool) {
+	return c.latency.stats(endpoint)
+}
+
+// ResetLatency discards all recorded latencies.
+func (c *Client) ResetLatency() {
+	c.latency.mu.Lock()
+	c.latency.endpoints = nil
+	c.latency.mu.Unlock()
+}
@@ -0,0 +1,198 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Closer is implemented by every long-running component of the SDK
+// (Poller, OrderTracker, TradeSyncer, ...).
+//
+// Close stops the component's goroutines, waits for callbacks that are
+// already executing to return, and releases its resources. If ctx expires
+// first, Close returns ctx.Err() and leaves the remaining work to finish in
+// the background. Close is idempotent.
+type Closer interface {
+	Close(ctx context.Context) error
+}
+
+// ErrClientClosed is returned by order operations started after
+// Client.Shutdown.
+var ErrClientClosed = errors.New("wallex: client is shut down")
+
+// ShutdownError is returned by Client.Shutdown when it could not complete
+// cleanly before its context expired.
+type ShutdownError struct {
+	GoWallexError
+
+	// InFlight describes the order operations (e.g. "CreateOrder BTCUSDT")
+	// that were still running when the deadline passed.
+	InFlight []string
+
+	// Errors holds the errors returned by individual components.
+	Errors []error
+}
+
+// lifecycle holds the client-wide shutdown state.
+type lifecycle struct {
+	mu       sync.Mutex
+	closers  []Closer
+	closed   bool
+	done     chan struct{}
+	inflight map[uint64]inflightOp
+	opSeq    uint64
+	ops      sync.WaitGroup
+}
+
+func (l *lifecycle) doneCh() chan struct{} {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.done == nil {
+		l.done = make(chan struct{})
+	}
+	return l.done
+}
+
+// Done returns a channel that is closed when Shutdown is called. Background
+// helpers started from the client stop when it closes.
+func (c *Client) Done() <-chan struct{} {
+	return c.life.doneCh()
+}
+
+// Register adds a component to be closed by Shutdown. Components created by
+// client helpers register themselves; user components may be added too.
+// Registering on a shut down client closes the component immediately.
+func (c *Client) Register(component Closer) {
+	c.life.mu.Lock()
+	if c.life.closed {
+		c.life.mu.Unlock()
+		_ = component.Close(context.Background())
+		return
+	}
+	c.life.closers = append(c.life.closers, component)
+	c.life.mu.Unlock()
+}
+
+// inflightOp describes a running order operation. Its description is only
+// formatted when Shutdown reports it, keeping the order path free of string
+// building.
+type inflightOp struct {
+	op     string
+	target string
+	side   string
+	qty    string
+	price  string
+}
+
+func (o inflightOp) String() string {
+	if o.side == "" {
+		return o.op + " " + o.target
+	}
+	return o.op + " " + o.target + " " + o.side + " " + o.qty + "@" + o.price
+}
+
+// beginOp records an in-flight order operation. endOp must be called with
+// the returned id when the operation completes.
+func (c *Client) beginOp(op inflightOp) (uint64, error) {
+	c.life.mu.Lock()
+	defer c.life.mu.Unlock()
+	if c.life.closed {
+		return 0, ErrClientClosed
+	}
+	if c.life.inflight == nil {
+		c.life.inflight = make(map[uint64]inflightOp)
+	}
+	c.life.opSeq++
+	id := c.life.opSeq
+	c.life.inflight[id] = op
+	c.life.ops.Add(1)
+	return id, nil
+}
+
+// endOp marks the operation started by beginOp as completed.
+func (c *Client) endOp(id uint64) {
+	c.life.mu.Lock()
+	delete(c.life.inflight, id)
+	c.life.mu.Unlock()
+	c.life.ops.Done()
+}
+
+// Shutdown gracefully stops the client:
+//
+//  1. New order operations are rejected with ErrClientClosed and Done is
+//     closed, stopping client-driven watchers.
+//  2. Registered components are closed in reverse registration order.
+//  3. In-flight CreateOrder/CancelOrder calls are awaited.
+//
+// If ctx expires before everything finished, a *ShutdownError lists the
+// order operations still in flight, so callers know which orders may need
+// reconciliation. Read-only requests remain usable after Shutdown.
+func (c *Client) Shutdown(ctx context.Context) error {
+	done := c.life.doneCh()
+
+	c.life.mu.Lock()
+	if c.life.closed {
+		c.life.mu.Unlock()
+		return nil
+	}
+	c.life.closed = true
+	closers := c.life.closers
+	c.life.closers = nil
+	close(done)
+	c.life.mu.Unlock()
+
+	var errs []error
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	opsDone := make(chan struct{})
+	go func() {
+		c.life.ops.Wait()
+		close(opsDone)
+	}()
+
+	select {
+	case <-opsDone:
+		if len(errs) == 0 {
+			return nil
+		}
+		return &ShutdownError{
+			GoWallexError: GoWallexError{Message: "shutdown completed with component errors", Err: errors.Join(errs...)},
+			Errors:        errs,
+		}
+	case <-ctx.Done():
+		c.life.mu.Lock()
+		inflight := make([]string, 0, len(c.life.inflight))
+		for _, op := range c.life.inflight {
+			inflight = append(inflight, op.String())
+		}
+		c.life.mu.Unlock()
+		sort.Strings(inflight)
+
+		return &ShutdownError{
+			GoWallexError: GoWallexError{Message: "shutdown deadline exceeded", Err: ctx.Err()},
+			InFlight:      inflight,
+			Errors:        errs,
+		}
+	}
+}
+
+// waitGroupDone waits for wg or ctx, whichever comes first.
+func waitGroupDone(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,290 @@
+package wallex
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// SpreadSample is one top-of-book observation.
+type SpreadSample struct {
+	Symbol  string
+	Time    time.Time
+	BestBid float64
+	BestAsk float64
+	BidSize float64
+	AskSize float64
+
+	// Spread is BestAsk - BestBid; SpreadBps the same relative to Mid.
+	Spread    float64
+	SpreadBps float64
+	Mid       float64
+}
+
+// TopNotional returns the quote value resting at the best bid and ask.
+func (s SpreadSample) TopNotional() float64 {
+	return s.BestBid*s.BidSize + s.BestAsk*s.AskSize
+}
+
+// SpreadStats summarizes the samples of a symbol within a window.
+type SpreadStats struct {
+	Symbol string
+	Count  int
+	From   time.Time
+	To     time.Time
+
+	MeanSpreadBps float64
+	MinSpreadBps  float64
+	MaxSpreadBps  float64
+	P50SpreadBps  float64
+	P95SpreadBps  float64
+
+	MeanBidSize     float64
+	MeanAskSize     float64
+	MeanTopNotional float64
+}
+
+// SpreadMonitorOptions configures a SpreadMonitor.
+type SpreadMonitorOptions struct {
+	// Symbols to monitor. Required.
+	Symbols []string
+
+	// Interval between samples. Defaults to 5 seconds.
+	Interval time.Duration
+
+	// Retention is how long samples are kept in memory. Defaults to one
+	// hour.
+	Retention time.Duration
+
+	// Metrics receives a gauge per sample. Defaults to the client's
+	// Metrics hook.
+	Metrics Metrics
+}
+
+// SpreadMonitor samples the best bid/ask and top-of-book sizes of selected
+// symbols and keeps them as in-memory time series.
+//
+// Every sample is published through the Metrics hook as the gauges
+// wallex_spread_bps, wallex_mid_price, wallex_top_bid_size and
+// wallex_top_ask_size, labelled by symbol. Rolling statistics over any
+// window within the retention are available from Stats.
+//
+// A single GET /v2/depth/all request is made per round when more than one
+// symbol is monitored; only the monitored books are decoded.
+//
+// SpreadMonitor implements Closer and is safe for concurrent use.
+type SpreadMonitor struct {
+	c    *Client
+	opts SpreadMonitorOptions
+
+	mu     sync.RWMutex
+	series map[string][]SpreadSample
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewSpreadMonitor returns a monitor for opts.Symbols. Call Run to start
+// sampling.
+func NewSpreadMonitor(c *Client, opts SpreadMonitorOptions) *SpreadMonitor {
+	if opts.Interval <= 0 {
+		opts.Interval = 5 * time.Second
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = time.Hour
+	}
+	if opts.Metrics == nil {
+		opts.Metrics = c.metrics()
+	}
+	return &SpreadMonitor{
+		c:      c,
+		opts:   opts,
+		series: make(map[string][]SpreadSample),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Sample takes one observation of every monitored symbol. Symbols with an
+// empty side are skipped.
+func (m *SpreadMonitor) Sample(ctx context.Context) error {
+	books, err := m.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	cutoff := now.Add(-m.opts.Retention)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for symbol, book := range books {
+		s, ok := sampleBook(symbol, now, book)
+		if !ok {
+			continue
+		}
+		series := append(m.series[symbol], s)
+		i := sort.Search(len(series), func(i int) bool { return !series[i].Time.Before(cutoff) })
+		m.series[symbol] = series[i:]
+
+		label := Label{Name: "symbol", Value: symbol}
+		m.opts.Metrics.Gauge("wallex_spread_bps", s.SpreadBps, label)
+		m.opts.Metrics.Gauge("wallex_mid_price", s.Mid, label)
+		m.opts.Metrics.Gauge("wallex_top_bid_size", s.BidSize, label)
+		m.opts.Metrics.Gauge("wallex_top_ask_size", s.AskSize, label)
+	}
+	return nil
+}
+
+func (m *SpreadMonitor) fetch(ctx context.Context) (map[string]t.OrderBook, error) {
+	books := make(map[string]t.OrderBook, len(m.opts.Symbols))
+	if len(m.opts.Symbols) == 1 {
+		depth, err := m.c.getOrderBook(ctx, m.opts.Symbols[0])
+		if err != nil {
+			return nil, err
+		}
+		books[m.opts.Symbols[0]] = depth.Result
+		return books, nil
+	}
+
+	all, err := m.c.getAllOrderBooks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, symbol := range m.opts.Symbols {
+		if book, ok := all.Result.Get(symbol); ok {
+			books[symbol] = book
+		}
+	}
+	return books, nil
+}
+
+func sampleBook(symbol string, at time.Time, book t.OrderBook) (SpreadSample, bool) {
+	if len(book.Bid) == 0 || len(book.Ask) == 0 {
+		return SpreadSample{}, false
+	}
+	bid, ask := book.Bid[0], book.Ask[0]
+	s := SpreadSample{
+		Symbol:  symbol,
+		Time:    at,
+		BestBid: bid.Price,
+		BestAsk: ask.Price,
+		BidSize: bid.Quantity.Float64(),
+		AskSize: ask.Quantity.Float64(),
+		Spread:  ask.Price - bid.Price,
+		Mid:     (ask.Price + bid.Price) / 2,
+	}
+	if s.Mid > 0 {
+		s.SpreadBps = s.Spread / s.Mid * 1e4
+	}
+	return s, true
+}
+
+// Run samples every Interval until ctx is done, the monitor is closed or
+// the client is shut down. Errors are passed to onError when it is non-nil.
+func (m *SpreadMonitor) Run(ctx context.Context, onError func(error)) {
+	m.loops.Add(1)
+	defer m.loops.Done()
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.Sample(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stop:
+			return
+		case <-m.c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close implements Closer. It stops Run loops and waits for them to return.
+func (m *SpreadMonitor) Close(ctx context.Context) error {
+	m.stopOnce.Do(func() { close(m.stop) })
+	return waitGroupDone(ctx, &m.loops)
+}
+
+// Series returns the samples of symbol taken at or after since, oldest
+// first.
+func (m *SpreadMonitor) Series(symbol string, since time.Time) []SpreadSample {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	series := m.series[symbol]
+	i := sort.Search(len(series), func(i int) bool { return !series[i].Time.Before(since) })
+	out := make([]SpreadSample, len(series)-i)
+	copy(out, series[i:])
+	return out
+}
+
+// Latest returns the most recent sample of symbol.
+func (m *SpreadMonitor) Latest(symbol string) (SpreadSample, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	series := m.series[symbol]
+	if len(series) == 0 {
+		return SpreadSample{}, false
+	}
+	return series[len(series)-1], true
+}
+
+// Stats summarizes the samples of symbol taken within window of now. It
+// reports false when there are none.
+func (m *SpreadMonitor) Stats(symbol string, window time.Duration) (SpreadStats, bool) {
+	samples := m.Series(symbol, time.Now().Add(-window))
+	if len(samples) == 0 {
+		return SpreadStats{}, false
+	}
+
+	st := SpreadStats{
+		Symbol:       symbol,
+		Count:        len(samples),
+		From:         samples[0].Time,
+		To:           samples[len(samples)-1].Time,
+		MinSpreadBps: math.Inf(1),
+		MaxSpreadBps: math.Inf(-1),
+	}
+	bps := make([]float64, len(samples))
+	for i, s := range samples {
+		bps[i] = s.SpreadBps
+		st.MeanSpreadBps += s.SpreadBps
+		st.MinSpreadBps = math.Min(st.MinSpreadBps, s.SpreadBps)
+		st.MaxSpreadBps = math.Max(st.MaxSpreadBps, s.SpreadBps)
+		st.MeanBidSize += s.BidSize
+		st.MeanAskSize += s.AskSize
+		st.MeanTopNotional += s.TopNotional()
+	}
+	n := float64(len(samples))
+	st.MeanSpreadBps /= n
+	st.MeanBidSize /= n
+	st.MeanAskSize /= n
+	st.MeanTopNotional /= n
+
+	sort.Float64s(bps)
+	st.P50SpreadBps = percentile(bps, 0.50)
+	st.P95SpreadBps = percentile(bps, 0.95)
+	return st, true
+}
+
+// percentile returns the p-th percentile of sorted values using the
+// nearest-rank method.
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,119 @@
+package wallex
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaintenanceError is returned instead of an APIError when Wallex reports
+// that it is in maintenance mode. It unwraps to the APIError, so
+// errors.As(err, &apiErr) keeps working for callers that do not care.
+//
+// Maintenance errors are not retried, classify as ErrorClassMaintenance
+// and take a HealthMonitor down at its next check without waiting for
+// further failures.
+type MaintenanceError struct {
+	APIError
+
+	// RetryAfter is the delay announced in the Retry-After header, or zero.
+	RetryAfter time.Duration
+}
+
+func (e *MaintenanceError) Unwrap() error { return &e.APIError }
+
+// maintenanceMarkers are substrings of the message or detail of Wallex's
+// maintenance responses, in English and Persian ("تعمیر": repair,
+// "بروزرسانی": update), matched case-insensitively.
+var maintenanceMarkers = []string{"maintenance", "تعمیر", "بروزرسانی", "به‌روزرسانی", "به روزرسانی"}
+
+// isMaintenance reports whether an error response is Wallex's maintenance
+// shape: a 5xx response whose message mentions maintenance, or one that
+// carries a truthy "maintenance" field.
+func isMaintenance(e *APIError) bool {
+	if e.StatusCode < 500 {
+		return false
+	}
+	if v, ok := e.Fields["maintenance"]; ok && len(v) > 0 && v[0] != "false" && v[0] != "0" {
+		return true
+	}
+	texts := append([]string{e.Message}, e.Fields["detail"]...)
+	for _, text := range texts {
+		text = strings.ToLower(text)
+		for _, m := range maintenanceMarkers {
+			if strings.Contains(text, m) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date.
+func retryAfter(h http.Header) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		if d := time.Until(at); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// holdGate holds requests of a client and its clones during an outage. It
+// is opened and closed by a HealthMonitor with HoldRequests.
+type holdGate struct {
+	mu sync.Mutex
+
+	// released is closed when held requests may proceed; nil while
+	// requests are not held.
+	released chan struct{}
+}
+
+// hold starts holding requests.
+func (g *holdGate) hold() {
+	g.mu.Lock()
+	if g.released == nil {
+		g.released = make(chan struct{})
+	}
+	g.mu.Unlock()
+}
+
+// release lets held requests proceed.
+func (g *holdGate) release() {
+	g.mu.Lock()
+	if g.released != nil {
+		close(g.released)
+		g.released = nil
+	}
+	g.mu.Unlock()
+}
+
+// wait blocks a request below PriorityHigh while requests are held. It
+// returns ctx.Err() if ctx is done first.
+func (g *holdGate) wait(ctx context.Context) error {
+	if g == nil || PriorityFromContext(ctx) >= PriorityHigh {
+		return nil
+	}
+	g.mu.Lock()
+	released := g.released
+	g.mu.Unlock()
+	if released == nil {
+		return nil
+	}
+	select {
+	case <-released:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,502 @@
+package wallex
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Default refresh intervals of a MarketDataCache.
+const (
+	DefaultMarketDataInterval = time.Second
+	DefaultTickerInterval     = 10 * time.Second
+)
+
+// MarketSnapshot is the latest market data of one symbol held by a
+// MarketDataCache. Snapshots are shared between readers and must not be
+// modified; every refresh publishes a new one.
+type MarketSnapshot struct {
+	Symbol string
+
+	// Ticker holds the 24h statistics and best prices from GET /v1/markets.
+	Ticker   t.Stats
+	TickerAt time.Time
+
+	// Book is the order book from GET /v1/depth.
+	Book   t.OrderBook
+	BookAt time.Time
+
+	// Trades are the recent trades from GET /v1/trades, newest first.
+	Trades   []t.Trade
+	TradesAt time.Time
+}
+
+// QuoteTick is the top of book and last trade price of a symbol, in the
+// form latency-sensitive code reads on every tick. Times are unix
+// nanoseconds; zero means unknown.
+type QuoteTick struct {
+	Bid     float64
+	BidSize float64
+	Ask     float64
+	AskSize float64
+
+	// BookNanos is when the book holding Bid and Ask was received.
+	BookNanos int64
+
+	// Last is the price of the newest trade, executed at LastNanos per
+	// Wallex. Without trades it is the ticker's last price, and LastNanos
+	// is when the ticker was received.
+	Last      float64
+	LastNanos int64
+}
+
+// Spread returns Ask - Bid, or 0 when either side is missing.
+func (q QuoteTick) Spread() float64 {
+	if q.Bid <= 0 || q.Ask <= 0 {
+		return 0
+	}
+	return q.Ask - q.Bid
+}
+
+// Mid returns the bid/ask mid-point, or 0 when either side is missing.
+func (q QuoteTick) Mid() float64 {
+	if q.Bid <= 0 || q.Ask <= 0 {
+		return 0
+	}
+	return (q.Bid + q.Ask) / 2
+}
+
+// MarketDataOptions configures a MarketDataCache.
+type MarketDataOptions struct {
+	// Interval between refreshes of the books and trades of subscribed
+	// symbols. Defaults to DefaultMarketDataInterval.
+	Interval time.Duration
+
+	// TickerInterval between refreshes of the tickers, which are fetched
+	// for all symbols with one GET /v1/markets request. Defaults to
+	// DefaultTickerInterval.
+	TickerInterval time.Duration
+
+	// Depth, if positive, keeps only the best Depth levels of each side
+	// of the books, to cut memory and comparison cost when many symbols
+	// are cached. Changes confined to deeper levels are not delivered.
+	Depth int
+}
+
+// MarketDataCache centralizes the latest ticker, order book and trades of
+// the symbols an application follows, so that many goroutines interested
+// in the same symbol share one stream of upstream requests:
+//   - Subscribe registers interest in a symbol and fans every changed
+//     snapshot out to the subscriber; Run refreshes subscribed symbols.
+//   - Refresh and Fetch load a symbol on demand. Concurrent refreshes of
+//     the same symbol are merged into one set of requests.
+//   - Get reads the latest snapshot, and LastQuote its top of book, without
+//     locking or I/O, so they can be called on every tick.
+//
+// Requests go through the client RateLimiter: following n symbols at
+// Interval costs 2n/Interval requests per second, plus one GET
+// /v1/markets per TickerInterval.
+//
+// Subscribers that do not keep up miss snapshots, unless they subscribed
+// with the Block policy. MarketDataCache implements Closer and is safe for
+// concurrent use.
+type MarketDataCache struct {
+	c    *Client
+	opts MarketDataOptions
+
+	// symbols is replaced, never modified, under mu so that Get can read
+	// it without locking.
+	mu      sync.Mutex
+	symbols atomic.Pointer[map[string]*marketEntry]
+
+	// markets holds the last GET /v1/markets response; tickers merges
+	// its concurrent refreshes.
+	markets   atomic.Pointer[t.MarketInformation]
+	marketsAt atomic.Int64
+	tickers   flight
+
+	// ctx is cancelled by Close to release publishers blocked on
+	// subscribers with the Block policy.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+type marketSub struct {
+	ch       chan *MarketSnapshot
+	overflow OverflowPolicy
+	ctx      context.Context
+	cancel   context.CancelFunc
+}
+
+type marketEntry struct {
+	symbol string
+	snap   atomic.Pointer[MarketSnapshot]
+	quote  atomic.Pointer[QuoteTick]
+
+	// refresh merges concurrent refreshes; update serializes publishing.
+	refresh flight
+	update  sync.Mutex
+
+	subMu   sync.Mutex
+	subs    map[int]*marketSub
+	nextSub int
+}
+
+// NewMarketDataCache returns an empty cache. Call Run to keep subscribed
+// symbols fresh.
+func NewMarketDataCache(c *Client, opts MarketDataOptions) *MarketDataCache {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultMarketDataInterval
+	}
+	if opts.TickerInterval <= 0 {
+		opts.TickerInterval = DefaultTickerInterval
+	}
+	m := &MarketDataCache{
+		c:    c,
+		opts: opts,
+		stop: make(chan struct{}),
+	}
+	m.ctx, m.cancel = context.WithCancel(context.Background())
+	m.symbols.Store(&map[string]*marketEntry{})
+	return m
+}
+
+// Get returns the latest snapshot of symbol, or false if it was never
+// loaded. It neither locks nor sends requests.
+func (m *MarketDataCache) Get(symbol string) (*MarketSnapshot, bool) {
+	e, ok := (*m.symbols.Load())[NormalizeSymbol(symbol)]
+	if !ok {
+		return nil, false
+	}
+	snap := e.snap.Load()
+	return snap, snap != nil
+}
+
+// LastQuote returns the best bid and ask and the last price of symbol, or
+// false if it was never loaded. Like Get it neither locks nor sends
+// requests, and it does not allocate, so it suits tight loops.
+func (m *MarketDataCache) LastQuote(symbol string) (QuoteTick, bool) {
+	e, ok := (*m.symbols.Load())[symbol]
+	if !ok {
+		if e, ok = (*m.symbols.Load())[NormalizeSymbol(symbol)]; !ok {
+			return QuoteTick{}, false
+		}
+	}
+	q := e.quote.Load()
+	if q == nil {
+		return QuoteTick{}, false
+	}
+	return *q, true
+}
+
+// Fetch returns the snapshot of symbol if its book and trades are younger
+// than maxAge, and refreshes it first otherwise.
+func (m *MarketDataCache) Fetch(ctx context.Context, symbol string, maxAge time.Duration) (*MarketSnapshot, error) {
+	if snap, ok := m.Get(symbol); ok && time.Since(snap.BookAt) < maxAge && time.Since(snap.TradesAt) < maxAge {
+		return snap, nil
+	}
+	if err := m.Refresh(ctx, symbol); err != nil {
+		return nil, err
+	}
+	snap, _ := m.Get(symbol)
+	return snap, nil
+}
+
+// Refresh loads the order book and recent trades of symbol, and the
+// tickers of all symbols if they are older than TickerInterval. A call
+// made while a refresh of the same symbol is in progress waits for that
+// refresh and returns its error instead of sending requests of its own.
+func (m *MarketDataCache) Refresh(ctx context.Context, symbol string) error {
+	e := m.entry(NormalizeSymbol(symbol))
+	return e.refresh.do(ctx, func() error {
+		return m.load(ctx, e)
+	})
+}
+
+// Subscribe is SubscribeWithOptions with the given buffer and the
+// default DropNewest policy.
+func (m *MarketDataCache) Subscribe(symbol string, buffer int) (<-chan *MarketSnapshot, func()) {
+	return m.SubscribeWithOptions(symbol, SubscribeOptions{Buffer: buffer})
+}
+
+// SubscribeWithOptions registers interest in symbol and returns a channel
+// receiving its snapshot whenever it changes, and a function that cancels
+// the subscription. The latest snapshot, if any, is delivered immediately.
+// The channel is closed when the subscription is cancelled or the cache
+// is closed.
+//
+// Overflow defaults to DropNewest: a subscriber that falls behind keeps the
+// snapshots it has buffered and misses the newer ones. A subscriber with
+// the Block policy holds up the delivery of the
+// symbol's snapshots to every subscriber, and its refreshes, until it
+// makes room or unsubscribes.
+func (m *MarketDataCache) SubscribeWithOptions(symbol string, opts SubscribeOptions) (<-chan *MarketSnapshot, func()) {
+	if opts.Buffer < 1 {
+		opts.Buffer = 1
+	}
+	e := m.entry(NormalizeSymbol(symbol))
+	s := &marketSub{ch: make(chan *MarketSnapshot, opts.Buffer), overflow: opts.Overflow.or(DropNewest)}
+	s.ctx, s.cancel = context.WithCancel(m.ctx)
+
+	e.subMu.Lock()
+	id := e.nextSub
+	e.nextSub++
+	e.subs[id] = s
+	if snap := e.snap.Load(); snap != nil {
+		s.ch <- snap
+	}
+	e.subMu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			s.cancel()
+			e.subMu.Lock()
+			defer e.subMu.Unlock()
+			if _, ok := e.subs[id]; ok {
+				delete(e.subs, id)
+				close(s.ch)
+			}
+		})
+	}
+	return s.ch, unsubscribe
+}
+
+// Symbols returns the symbols with at least one subscriber.
+func (m *MarketDataCache) Symbols() []string {
+	var out []string
+	for sym, e := range *m.symbols.Load() {
+		e.subMu.Lock()
+		n := len(e.subs)
+		e.subMu.Unlock()
+		if n > 0 {
+			out = append(out, sym)
+		}
+	}
+	return out
+}
+
+// Run refreshes every subscribed symbol each Interval until ctx is done,
+// the cache is closed or the client is shut down. Refresh errors are
+// passed to onError, if set, and the previous snapshot is kept.
+func (m *MarketDataCache) Run(ctx context.Context, onError func(error)) {
+	m.loops.Add(1)
+	defer m.loops.Done()
+
+	ticker := time.NewTicker(m.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		var wg sync.WaitGroup
+		for _, sym := range m.Symbols() {
+			wg.Add(1)
+			go func(sym string) {
+				defer wg.Done()
+				if err := m.Refresh(ctx, sym); err != nil && onError != nil && ctx.Err() == nil {
+					onError(err)
+				}
+			}(sym)
+		}
+		wg.Wait()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stop:
+			return
+		case <-m.c.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close implements Closer. It stops Run, waits for it and closes all
+// subscriber channels.
+func (m *MarketDataCache) Close(ctx context.Context) error {
+	m.stopOnce.Do(func() { close(m.stop) })
+	m.cancel()
+	if err := waitGroupDone(ctx, &m.loops); err != nil {
+		return err
+	}
+	for _, e := range *m.symbols.Load() {
+		e.subMu.Lock()
+		for id, s := range e.subs {
+			delete(e.subs, id)
+			close(s.ch)
+		}
+		e.subMu.Unlock()
+	}
+	return nil
+}
+
+// entry returns the entry of symbol, creating it if needed.
+func (m *MarketDataCache) entry(symbol string) *marketEntry {
+	if e, ok := (*m.symbols.Load())[symbol]; ok {
+		return e
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old := *m.symbols.Load()
+	if e, ok := old[symbol]; ok {
+		return e
+	}
+	e := &marketEntry{symbol: symbol, subs: make(map[int]*marketSub)}
+	next := make(map[string]*marketEntry, len(old)+1)
+	for k, v := range old {
+		next[k] = v
+	}
+	next[symbol] = e
+	m.symbols.Store(&next)
+	return e
+}
+
+// load fetches the data of e and publishes the resulting snapshot.
+func (m *MarketDataCache) load(ctx context.Context, e *marketEntry) error {
+	depth, err := m.c.getOrderBook(ctx, e.symbol)
+	if err != nil {
+		return err
+	}
+	bookAt := time.Now()
+	trades, err := m.c.getRecentTrades(ctx, e.symbol)
+	if err != nil {
+		return err
+	}
+	tradesAt := time.Now()
+
+	if time.Since(time.Unix(0, m.marketsAt.Load())) >= m.opts.TickerInterval {
+		if err := m.tickers.do(ctx, func() error { return m.loadTickers(ctx) }); err != nil {
+			return err
+		}
+	}
+	info, haveTicker := m.markets.Load().Get(e.symbol)
+	tickerAt := time.Unix(0, m.marketsAt.Load())
+
+	m.publish(e, func(snap *MarketSnapshot) {
+		snap.Book, snap.BookAt = depth.Result.Top(m.opts.Depth), bookAt
+		snap.Trades, snap.TradesAt = trades.Result.LatestTrades, tradesAt
+		if haveTicker {
+			snap.Ticker, snap.TickerAt = info.Stats, tickerAt
+		}
+	})
+	return nil
+}
+
+// loadTickers fetches the tickers of all symbols.
+func (m *MarketDataCache) loadTickers(ctx context.Context) error {
+	markets, err := m.c.getMarketsInfo(ctx)
+	if err != nil {
+		return err
+	}
+	m.markets.Store(markets)
+	m.marketsAt.Store(time.Now().UnixNano())
+	return nil
+}
+
+// publish stores a copy of the snapshot of e modified by apply and, if the
+// data changed, delivers it to the subscribers.
+func (m *MarketDataCache) publish(e *marketEntry, apply func(snap *MarketSnapshot)) {
+	e.update.Lock()
+	defer e.update.Unlock()
+
+	next := MarketSnapshot{Symbol: e.symbol}
+	prev := e.snap.Load()
+	if prev != nil {
+		next = *prev
+	}
+	apply(&next)
+	quote := quoteTick(&next)
+	e.snap.Store(&next)
+	e.quote.Store(&quote)
+
+	if prev != nil && reflect.DeepEqual(prev.Book, next.Book) &&
+		reflect.DeepEqual(prev.Trades, next.Trades) && reflect.DeepEqual(prev.Ticker, next.Ticker) {
+		return
+	}
+
+	e.subMu.Lock()
+	defer e.subMu.Unlock()
+	for _, s := range e.subs {
+		offer(s.ctx, s.ch, &next, s.overflow, nil)
+	}
+}
+
+// quoteTick derives the QuoteTick of snap.
+func quoteTick(snap *MarketSnapshot) QuoteTick {
+	var q QuoteTick
+	if len(snap.Book.Bid) > 0 {
+		q.Bid, q.BidSize = snap.Book.Bid[0].Price, snap.Book.Bid[0].Quantity.Float64()
+	}
+	if len(snap.Book.Ask) > 0 {
+		q.Ask, q.AskSize = snap.Book.Ask[0].Price, snap.Book.Ask[0].Quantity.Float64()
+	}
+	if !snap.BookAt.IsZero() {
+		q.BookNanos = snap.BookAt.UnixNano()
+	}
+
+	var newest time.Time
+	for _, tr := range snap.Trades {
+		if !tr.Timestamp.After(newest) {
+			continue
+		}
+		if price, err := strconv.ParseFloat(tr.Price.String(), 64); err == nil && price > 0 {
+			q.Last, newest = price, tr.Timestamp.Time
+		}
+	}
+	switch {
+	case !newest.IsZero():
+		q.LastNanos = newest.UnixNano()
+	case !snap.TickerAt.IsZero():
+		q.Last, q.LastNanos = snap.Ticker.LastPriceFloat(), snap.TickerAt.UnixNano()
+	}
+	return q
+}
+
+// flight merges concurrent executions of a function: callers arriving
+// while a call is in progress wait for it and share its error. Its zero
+// value is ready to use.
+type flight struct {
+	mu   sync.Mutex
+	call *flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	err  error
+}
+
+// do runs fn unless a call is in progress, in which case it waits for that
+// call or for ctx.
+func (f *flight) do(ctx context.Context, fn func() error) error {
+	f.mu.Lock()
+	if call := f.call; call != nil {
+		f.mu.Unlock()
+		select {
+		case <-call.done:
+			return call.err
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	call := &flightCall{done: make(chan struct{})}
+	f.call = call
+	f.mu.Unlock()
+
+	call.err = fn()
+
+	f.mu.Lock()
+	f.call = nil
+	f.mu.Unlock()
+	close(call.done)
+	return call.err
+}
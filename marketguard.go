@@ -0,0 +1,127 @@
+package wallex
+
+import (
+	"context"
+	"strconv"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// SlippageError is returned by ExecuteMarketWithLimit when the book cannot
+// fill the quantity within the slippage bound. No order is placed.
+type SlippageError struct {
+	GoWallexError
+	Symbol string
+	Side   string
+
+	// Quantity is the quantity requested, and Available the quantity
+	// resting at or better than LimitPrice.
+	Quantity   float64
+	Available  float64
+	LimitPrice float64
+}
+
+// ExecuteMarketWithLimit executes qty of symbol at market, without risking
+// fills more than maxSlippageBps away from the best price.
+//
+// The live book is fetched and the protective price computed from the best
+// opposite price: best ask × (1 + maxSlippageBps/10000) for a buy, best bid
+// × (1 - maxSlippageBps/10000) for a sell, rounded inwards to the tick
+// size. When the levels within that price hold less than qty, the book is
+// too thin and a *SlippageError is returned without placing an order.
+// Otherwise an aggressive LIMIT order at the protective price is placed;
+// it fills against the book like a market order, and any part the book no
+// longer holds when it arrives rests at the protective price rather than
+// filling deeper.
+//
+// The quantity is rounded down to the step size of the market.
+//
+// Authentication: REQUIRED.
+func (c *Client) ExecuteMarketWithLimit(ctx context.Context, symbol, side string, qty, maxSlippageBps float64) (*t.BaseOrderResponse, error) {
+	if side != t.SideBuy && side != t.SideSell {
+		return nil, invalidParam("side", side, "must be "+t.SideBuy+" or "+t.SideSell)
+	}
+	if qty <= 0 || maxSlippageBps < 0 {
+		return nil, &GoWallexError{
+			Message: "quantity must be positive and max slippage not negative",
+			Err:     nil,
+		}
+	}
+
+	symbol, err := c.resolveSymbol(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	markets, err := c.getMarketsInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := markets.Get(symbol)
+	if !ok {
+		return nil, &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "unknown symbol " + symbol, Err: nil},
+			Symbol:        symbol,
+		}
+	}
+	priceDecimals, qtyDecimals := int(info.TickSize), int(info.StepSize)
+	if qty = u.RoundDown(qty, qtyDecimals); qty <= 0 {
+		return nil, &GoWallexError{
+			Message: "quantity is below the step size of " + symbol,
+			Err:     CodeInvalidQuantity,
+		}
+	}
+
+	depth, err := c.getOrderBook(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	levels := depth.Result.Ask
+	if side == t.SideSell {
+		levels = depth.Result.Bid
+	}
+
+	limit := protectivePrice(levels, side, maxSlippageBps, priceDecimals)
+	var available float64
+	for _, l := range levels {
+		if side == t.SideBuy && l.Price > limit || side == t.SideSell && l.Price < limit {
+			break
+		}
+		available += l.Quantity.Float64()
+	}
+	if len(levels) == 0 || available < qty {
+		return nil, &SlippageError{
+			GoWallexError: GoWallexError{
+				Message: "book too thin for " + side + " " + strconv.FormatFloat(qty, 'f', -1, 64) + " " + symbol +
+					" within " + strconv.FormatFloat(maxSlippageBps, 'f', -1, 64) + " bps",
+				Err: nil,
+			},
+			Symbol:     symbol,
+			Side:       side,
+			Quantity:   qty,
+			Available:  available,
+			LimitPrice: limit,
+		}
+	}
+
+	return c.createOrder(ctx, t.CreateOrderParams{
+		Symbol:   symbol,
+		Type:     t.OrderTypeLimit,
+		Side:     side,
+		Price:    strconv.FormatFloat(limit, 'f', priceDecimals, 64),
+		Quantity: strconv.FormatFloat(qty, 'f', qtyDecimals, 64),
+	})
+}
+
+// protectivePrice returns the worst price an order of side may fill at,
+// maxSlippageBps away from the best of levels, the opposite side of the
+// book. It is zero when levels is empty.
+func protectivePrice(levels []t.Order, side string, maxSlippageBps float64, decimals int) float64 {
+	if len(levels) == 0 {
+		return 0
+	}
+	if side == t.SideBuy {
+		return u.RoundDown(levels[0].Price*(1+maxSlippageBps/1e4), decimals)
+	}
+	return u.RoundUp(levels[0].Price*(1-maxSlippageBps/1e4), decimals)
+}
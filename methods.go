@@ -0,0 +1,50 @@
+package wallex
+
+import (
+	"net/http"
+	"net/url"
+
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// HTTP methods accepted by Request, ApiRequest and Raw.
+const (
+	MethodGet    = http.MethodGet
+	MethodPost   = http.MethodPost
+	MethodPut    = http.MethodPut
+	MethodPatch  = http.MethodPatch
+	MethodDelete = http.MethodDelete
+)
+
+// methodHasBody reports whether a request body passed to Request is sent
+// as JSON (POST, PUT, PATCH, DELETE) rather than encoded into the query
+// string (GET, HEAD).
+func methodHasBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return false
+	}
+	return true
+}
+
+// idempotentMethod reports whether repeating a request cannot change its
+// effect, so that it may be retried after an ambiguous failure. Wallex
+// treats a repeated DELETE of an order as a no-op and PUT replaces state,
+// whereas POST and PATCH may apply twice.
+func idempotentMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPatch:
+		return false
+	}
+	return true
+}
+
+// isQueryParams reports whether body explicitly carries query parameters,
+// which are encoded into the URL for every method.
+func isQueryParams(body interface{}) bool {
+	switch body.(type) {
+	case *u.Params, url.Values:
+		return true
+	}
+	return false
+}
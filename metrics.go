@@ -0,0 +1,39 @@
+package wallex
+
+// Label is a metric dimension, e.g. {Name: "symbol", Value: "BTCUSDT"}.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Metrics is the hook through which SDK components publish measurements.
+// Adapters for Prometheus, OpenTelemetry, expvar and the like implement it;
+// implementations must be safe for concurrent use and should not block.
+//
+// Metric names are prefixed with "wallex_".
+type Metrics interface {
+	// Gauge records the current value of name.
+	Gauge(name string, value float64, labels ...Label)
+
+	// Observe records one sample of a distribution such as a latency.
+	Observe(name string, value float64, labels ...Label)
+
+	// Add increments the counter name by delta.
+	Add(name string, delta float64, labels ...Label)
+}
+
+// NopMetrics discards all measurements. It is used when no Metrics hook is
+// configured.
+type NopMetrics struct{}
+
+func (NopMetrics) Gauge(string, float64, ...Label)   {}
+func (NopMetrics) Observe(string, float64, ...Label) {}
+func (NopMetrics) Add(string, float64, ...Label)     {}
+
+// metrics returns the configured hook or NopMetrics.
+func (c *Client) metrics() Metrics {
+	if c.Metrics == nil {
+		return NopMetrics{}
+	}
+	return c.Metrics
+}
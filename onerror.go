@@ -0,0 +1,63 @@
+package wallex
+
+import (
+	"context"
+	"time"
+)
+
+// ErrorEvent describes an error observed by the client. It is passed to
+// the OnError hook.
+type ErrorEvent struct {
+	// Err is the error, typically a *RequestError, *APIError or one of the
+	// local rejection errors (ErrClientClosed, *risk.RiskError, ...).
+	Err error
+
+	// Method and Endpoint identify the request, e.g. "POST" and
+	// "POST /v1/account/orders".
+	Method   string
+	Endpoint string
+
+	// RequestID is the correlation ID of the request; empty for errors
+	// raised before a request was built.
+	RequestID string
+
+	// Attempt is the zero-based attempt number.
+	Attempt int
+
+	// Final is set when Err is returned to the caller. Attempts that are
+	// retried are reported with Final unset; if ctx ends while waiting to
+	// retry, the same error is reported again with Final set.
+	Final bool
+
+	// Local is set for errors raised by the SDK without contacting Wallex,
+	// such as pre-trade rejections or requests after Shutdown.
+	Local bool
+
+	// Took is the duration of the failed attempt.
+	Took time.Duration
+}
+
+// reportError counts final errors in Stats and passes ev to the OnError
+// hook, if any.
+func (c *Client) reportError(ctx context.Context, ev ErrorEvent) {
+	if ev.Err == nil {
+		return
+	}
+	if ev.Final {
+		class := ClassifyError(ev.Err)
+		if ev.Local && class == ErrorClassOther {
+			class = ErrorClassLocal
+		}
+		c.stats.failure(class)
+	}
+	if c.OnError == nil {
+		return
+	}
+	c.OnError(ctx, ev)
+}
+
+// reportLocal reports an error raised before a request was sent.
+func (c *Client) reportLocal(ctx context.Context, method, endpoint string, err error) error {
+	c.reportError(ctx, ErrorEvent{Err: err, Method: method, Endpoint: endpoint, Final: true, Local: true})
+	return err
+}
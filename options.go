@@ -0,0 +1,102 @@
+package wallex
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// RequestOption tunes a single call, e.g.
+//
+//	depth, err := client.GetOrderBook("BTCUSDT", wallex.WithTimeout(time.Second), wallex.WithPriority(wallex.PriorityHigh))
+//
+// Options are carried by the request context, so methods that take a
+// context accept them through WithOptions.
+type RequestOption func(ctx context.Context) context.Context
+
+// WithOptions returns a copy of ctx carrying opts.
+func WithOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	for _, opt := range opts {
+		if opt != nil {
+			ctx = opt(ctx)
+		}
+	}
+	return ctx
+}
+
+// callContext returns the context of a public method called with opts.
+func callContext(opts []RequestOption) context.Context {
+	return WithOptions(context.Background(), opts...)
+}
+
+// WithTimeout bounds each HTTP attempt of the call by d, overriding
+// ClientOptions.EndpointTimeouts; see WithRequestTimeout.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(ctx context.Context) context.Context {
+		return WithRequestTimeout(ctx, d)
+	}
+}
+
+type headersKey struct{}
+
+// WithHeader adds a header to the requests of the call. Headers the client
+// sets itself, such as X-API-Key and Content-Type, cannot be replaced.
+func WithHeader(key, value string) RequestOption {
+	return func(ctx context.Context) context.Context {
+		h := http.Header{}
+		if prev, ok := ctx.Value(headersKey{}).(http.Header); ok {
+			h = prev.Clone()
+		}
+		h.Add(key, value)
+		return context.WithValue(ctx, headersKey{}, h)
+	}
+}
+
+type retryDisabledKey struct{}
+
+// WithRetryDisabled makes the first failure of the call final regardless
+// of ClientOptions.MaxRetries, e.g. for latency-critical requests that
+// are better abandoned than retried.
+func WithRetryDisabled() RequestOption {
+	return func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, retryDisabledKey{}, true)
+	}
+}
+
+func retryDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(retryDisabledKey{}).(bool)
+	return disabled
+}
+
+// Priority ranks calls competing for the rate limit.
+type Priority int
+
+const (
+	// PriorityLow calls only take a TokenBucket token while a fifth of its
+	// burst remains, so background work cannot starve other calls.
+	PriorityLow Priority = iota - 1
+
+	// PriorityNormal is the default.
+	PriorityNormal
+
+	// PriorityHigh calls are exempt from ClientOptions.RetryBudget, e.g.
+	// cancels that must go through while the budget is exhausted.
+	PriorityHigh
+)
+
+type priorityKey struct{}
+
+// WithPriority sets the priority of the call. Custom RateLimiter
+// implementations can read it with PriorityFromContext.
+func WithPriority(p Priority) RequestOption {
+	return func(ctx context.Context) context.Context {
+		return context.WithValue(ctx, priorityKey{}, p)
+	}
+}
+
+// PriorityFromContext returns the priority set with WithPriority, or
+// PriorityNormal.
+func PriorityFromContext(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
@@ -0,0 +1,270 @@
+package wallex
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// ReplaceOrderResult holds the outcome of both legs of ReplaceOrder.
+//
+// Canceled is the order as reported by the cancel leg (or by the status
+// lookup when the cancel itself failed). Created is nil whenever the
+// replacement was not placed.
+type ReplaceOrderResult struct {
+	Canceled *t.CancelOrder
+	Created  *t.BaseOrder
+}
+
+// ReplaceOrder amends a resting order by cancelling it and placing a new order
+// with the same symbol, side and type at newPrice/newQty.
+//
+// Steps:
+//  1. Cancel clientOrderId.
+//  2. If the cancel fails, look the order up. When it is already FILLED (the
+//     classic cancel/fill race) an *OrderStateError is returned and no
+//     replacement is placed; otherwise the cancel error is returned.
+//  3. Verify the cancel leg reports status CANCELED.
+//  4. Place the replacement order.
+//
+// The result always carries whichever legs completed, so callers can tell a
+// failed cancel apart from a failed replacement. The replacement receives a
+// server-assigned clientOrderId.
+//
+// Authentication: REQUIRED.
+func (c *Client) ReplaceOrder(ctx context.Context, clientOrderId string, newPrice string, newQty string) (*ReplaceOrderResult, error) {
+	if clientOrderId == "" {
+		return nil, &GoWallexError{
+			Message: "client order id is required for replacing an order",
+			Err:     nil,
+		}
+	}
+
+	result := &ReplaceOrderResult{}
+
+	canceled, err := c.cancelOrder(ctx, clientOrderId)
+	if err != nil {
+		status, statusErr := c.getOrderStatus(ctx, clientOrderId)
+		if statusErr != nil {
+			return result, err
+		}
+
+		result.Canceled = &t.CancelOrder{
+			Symbol:          status.Result.Symbol,
+			Type:            status.Result.Type,
+			Side:            status.Result.Side,
+			ClientOrderID:   status.Result.ClientOrderId,
+			Price:           status.Result.Price,
+			OrigQty:         status.Result.OrigQty,
+			ExecutedQty:     status.Result.ExecutedQty,
+			ExecutedPercent: status.Result.ExecutedPercent,
+			Status:          status.Result.Status,
+			Active:          status.Result.Active,
+		}
+
+		if status.Result.Status == t.OrderStatusFilled {
+			return result, &OrderStateError{
+				GoWallexError: GoWallexError{
+					Message: "order already filled, replacement not placed",
+					Err:     err,
+				},
+				ClientOrderId: clientOrderId,
+				Status:        status.Result.Status,
+			}
+		}
+		return result, err
+	}
+
+	result.Canceled = &canceled.Result
+	if canceled.Result.Status != t.OrderStatusCanceled {
+		return result, &OrderStateError{
+			GoWallexError: GoWallexError{
+				Message: "cancel did not complete, replacement not placed",
+				Err:     nil,
+			},
+			ClientOrderId: clientOrderId,
+			Status:        canceled.Result.Status,
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return result, &GoWallexError{
+			Message: "context done before placing replacement order",
+			Err:     err,
+		}
+	}
+
+	created, err := c.createOrder(ctx, t.CreateOrderParams{
+		Symbol:   canceled.Result.Symbol,
+		Type:     canceled.Result.Type,
+		Side:     canceled.Result.Side,
+		Price:    newPrice,
+		Quantity: newQty,
+	})
+	if err != nil {
+		return result, err
+	}
+
+	result.Created = &created.Result
+	return result, nil
+}
+
+// CreateOrderResult is the per-order outcome of CreateOrders.
+//
+// Exactly one of Order and Err is set.
+type CreateOrderResult struct {
+	Params t.CreateOrderParams
+	Order  *t.BaseOrder
+	Err    error
+}
+
+// CreateOrders submits multiple orders concurrently.
+//
+// At most Client.BatchConcurrency requests are in flight at once, and each
+// request still goes through the client RateLimiter. The returned slice has
+// the same length and ordering as params, so results[i] always belongs to
+// params[i]. A failure of one order does not stop the others.
+//
+// If ctx is cancelled, orders that were not yet submitted report ctx.Err().
+//
+// Authentication: REQUIRED.
+func (c *Client) CreateOrders(ctx context.Context, params []t.CreateOrderParams) []CreateOrderResult {
+	results := make([]CreateOrderResult, len(params))
+	errs := forEachConcurrent(ctx, len(params), c.BatchConcurrency, func(i int) error {
+		resp, err := c.createOrder(ctx, params[i])
+		if err != nil {
+			return err
+		}
+		results[i].Order = &resp.Result
+		return nil
+	})
+	for i, p := range params {
+		results[i].Params = p
+		results[i].Err = errs[i]
+	}
+	return results
+}
+
+// CancelOrderResult is the per-order outcome of bulk cancel helpers.
+//
+// Exactly one of Order and Err is set.
+type CancelOrderResult struct {
+	ClientOrderId string
+	Order         *t.CancelOrder
+	Err           error
+}
+
+// CancelOrdersOlderThan cancels every open order whose CreatedAt is more
+// than age in the past.
+//
+// If symbol is empty, open orders across all markets are inspected. Orders
+// with a zero CreatedAt are skipped, since their age cannot be determined.
+// Only the stale orders are returned, one result per cancel attempt.
+//
+// This is mainly useful for sweeping abandoned LIMIT orders left behind by a
+// crashed strategy.
+//
+// Authentication: REQUIRED.
+func (c *Client) CancelOrdersOlderThan(ctx context.Context, symbol string, age time.Duration) ([]CancelOrderResult, error) {
+	open, err := c.getOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	cutoff := time.Now().Add(-age)
+	var stale []string
+	for _, o := range open.Result.Orders {
+		if o.CreatedAt.IsZero() || !o.CreatedAt.Before(cutoff) {
+			continue
+		}
+		stale = append(stale, o.ClientOrderId)
+	}
+
+	return c.cancelOrders(ctx, stale), nil
+}
+
+// cancelOrders cancels the given orders concurrently, bounded by
+// BatchConcurrency, preserving input ordering in the results.
+func (c *Client) cancelOrders(ctx context.Context, clientOrderIds []string) []CancelOrderResult {
+	results := make([]CancelOrderResult, len(clientOrderIds))
+	errs := forEachConcurrent(ctx, len(clientOrderIds), c.BatchConcurrency, func(i int) error {
+		resp, err := c.cancelOrder(ctx, clientOrderIds[i])
+		if err != nil {
+			return err
+		}
+		results[i].Order = &resp.Result
+		return nil
+	})
+	for i, id := range clientOrderIds {
+		results[i].ClientOrderId = id
+		results[i].Err = errs[i]
+	}
+	return results
+}
+
+// ListOpenOrders returns the open orders of the given symbols, or of all
+// markets when no symbol is given.
+//
+// Symbols are fetched concurrently, bounded by BatchConcurrency, and merged
+// into a single response in argument order; use BySymbol to partition it
+// and TotalOpenNotional to measure resting exposure before adding more. If
+// any symbol fails, the first error is returned.
+//
+// Authentication: REQUIRED.
+func (c *Client) ListOpenOrders(ctx context.Context, symbols ...string) (*t.OpenOrdersResponse, error) {
+	if len(symbols) == 0 {
+		return c.getOpenOrders(ctx, "")
+	}
+
+	responses := make([]*t.OpenOrdersResponse, len(symbols))
+	errs := forEachConcurrent(ctx, len(symbols), c.BatchConcurrency, func(i int) error {
+		var err error
+		responses[i], err = c.getOpenOrders(ctx, symbols[i])
+		return err
+	})
+
+	merged := &t.OpenOrdersResponse{}
+	merged.Success = true
+	for i, resp := range responses {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if resp != nil {
+			merged.Result.Orders = append(merged.Result.Orders, resp.Result.Orders...)
+		}
+	}
+	return merged, nil
+}
+
+// forEachConcurrent calls fn(i) for every i in [0, n), running at most limit
+// calls at once; a limit of zero or less means DefaultBatchConcurrency. It
+// returns the error of each call, or ctx.Err() for calls not started
+// because ctx was done.
+func forEachConcurrent(ctx context.Context, n, limit int, fn func(i int) error) []error {
+	if limit <= 0 {
+		limit = DefaultBatchConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	errs := make([]error, n)
+
+	var wg sync.WaitGroup
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+	return errs
+}
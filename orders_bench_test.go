@@ -0,0 +1,88 @@
+package wallex
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/darhelm/go-wallex/fixtures"
+	"github.com/darhelm/go-wallex/types"
+)
+
+// fixtureTransport answers every request with the same payload, so the
+// order benchmarks measure the client alone.
+type fixtureTransport struct {
+	body []byte
+}
+
+func (f fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(f.body)),
+		Request:    req,
+	}, nil
+}
+
+// benchClient returns a client whose requests are answered with fixture.
+func benchClient(b *testing.B, fixture string) *Client {
+	c, err := NewClient(ClientOptions{
+		ApiKey:     "bench",
+		HttpClient: &http.Client{Transport: fixtureTransport{body: fixtures.MustLoad(fixture)}},
+	})
+	if err != nil {
+		b.Fatal(err)
+	}
+	return c
+}
+
+var benchOrder = types.CreateOrderParams{
+	Symbol:        "BTCUSDT",
+	Type:          types.OrderTypeLimit,
+	Side:          types.SideBuy,
+	Price:         "64250.5",
+	Quantity:      "0.0015",
+	ClientOrderId: "mm-bid-000001",
+}
+
+// BenchmarkCreateOrder measures the full client-side CreateOrder path: body
+// encoding, request construction, response read and decoding.
+func BenchmarkCreateOrder(b *testing.B) {
+	c := benchClient(b, fixtures.OrderCreate)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.CreateOrder(benchOrder); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCancelOrder measures the client-side CancelOrder path.
+func BenchmarkCancelOrder(b *testing.B) {
+	c := benchClient(b, fixtures.OrderCancel)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.CancelOrder(benchOrder.ClientOrderId); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkCreateOrderParamsMarshal measures encoding of the order payload
+// alone.
+func BenchmarkCreateOrderParamsMarshal(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := json.Marshal(benchOrder); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
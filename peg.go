@@ -0,0 +1,169 @@
+package wallex
+
+import (
+	"context"
+	"math"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// PegMode selects how a resting order is priced relative to the book.
+type PegMode int
+
+const (
+	// PegNone rests at a fixed price.
+	PegNone PegMode = iota
+
+	// PegBest joins the best quote of the order's own side: the best bid
+	// for a buy, the best ask for a sell.
+	PegBest
+
+	// PegMid rests at the bid/ask mid-point, rounded away from the
+	// opposite side so the order never crosses.
+	PegMid
+)
+
+// pegPrice returns the price an order of side pegged with mode rests at in
+// book, offset ticks further from the opposite side, or false when the book
+// lacks the sides needed. The price never crosses the opposite best quote.
+func pegPrice(book t.OrderBook, side string, mode PegMode, offset, decimals int) (float64, bool) {
+	if len(book.Bid) == 0 || len(book.Ask) == 0 {
+		return 0, false
+	}
+	bid, ask := book.Bid[0].Price, book.Ask[0].Price
+	tick := math.Pow10(-decimals)
+
+	var price float64
+	switch {
+	case mode == PegMid && side == t.SideBuy:
+		price = u.RoundDown((bid+ask)/2, decimals)
+	case mode == PegMid:
+		price = u.RoundUp((bid+ask)/2, decimals)
+	case side == t.SideBuy:
+		price = bid
+	default:
+		price = ask
+	}
+
+	if side == t.SideBuy {
+		price = math.Min(price-float64(offset)*tick, ask-tick)
+	} else {
+		price = math.Max(price+float64(offset)*tick, bid+tick)
+	}
+	return u.RoundDown(price+tick/2, decimals), price > 0
+}
+
+// DefaultPegMinReprice is the least time between two reprices of a
+// PeggedOrder when PegOptions.MinReprice is zero. A reprice costs four
+// requests: the book, the status, the cancel and the new order.
+const DefaultPegMinReprice = 2 * time.Second
+
+// PegOptions configures a PeggedOrder.
+type PegOptions struct {
+	// Symbol is the market, e.g. "BTCTMN".
+	Symbol string
+
+	// Side is t.SideBuy or t.SideSell.
+	Side string
+
+	// Quantity is the base quantity of the order.
+	Quantity float64
+
+	// Mode is the peg. Defaults to PegBest.
+	Mode PegMode
+
+	// OffsetTicks moves the order that many ticks away from the opposite
+	// side, behind the best quote.
+	OffsetTicks int
+
+	// Limit is the worst price the order may rest at, the highest for a
+	// buy and the lowest for a sell. Zero leaves it unbounded.
+	Limit float64
+
+	// Interval is how often the order is polled and the book checked.
+	// Defaults to DefaultIcebergInterval.
+	Interval time.Duration
+
+	// MinReprice is the least time between two reprices, so a fast moving
+	// book does not exhaust the rate limit. Defaults to
+	// DefaultPegMinReprice.
+	MinReprice time.Duration
+
+	// Tracker receives every order snapshot. Defaults to a private
+	// tracker.
+	Tracker *OrderTracker
+
+	// ClientOrderIdPrefix prefixes the client order ids of the order and
+	// its replacements. Defaults to "peg-<symbol>".
+	ClientOrderIdPrefix string
+}
+
+// PeggedOrder keeps a LIMIT order resting at the best quote of its side,
+// or at an offset behind it, until it is filled. When the peg price moves,
+// the order is canceled and replaced at the new price for its remaining
+// quantity, at most once per MinReprice.
+//
+// A PeggedOrder is an Iceberg showing its whole quantity; Step, Run and
+// Progress behave the same.
+//
+// PeggedOrder implements Closer and is safe for concurrent use.
+type PeggedOrder struct {
+	ib *Iceberg
+}
+
+// NewPeggedOrder returns a PeggedOrder for opts. Call Run, or Step
+// repeatedly, to place and maintain it.
+func NewPeggedOrder(ctx context.Context, c *Client, opts PegOptions) (*PeggedOrder, error) {
+	if opts.Mode == PegNone {
+		opts.Mode = PegBest
+	}
+	if opts.MinReprice <= 0 {
+		opts.MinReprice = DefaultPegMinReprice
+	}
+	if opts.ClientOrderIdPrefix == "" {
+		opts.ClientOrderIdPrefix = "peg-" + NormalizeSymbol(opts.Symbol)
+	}
+
+	ib, err := NewIceberg(ctx, c, IcebergOptions{
+		Symbol:              opts.Symbol,
+		Side:                opts.Side,
+		Quantity:            opts.Quantity,
+		Price:               opts.Limit,
+		SliceSize:           opts.Quantity,
+		Peg:                 opts.Mode,
+		PegOffsetTicks:      opts.OffsetTicks,
+		MinReprice:          opts.MinReprice,
+		Interval:            opts.Interval,
+		Tracker:             opts.Tracker,
+		ClientOrderIdPrefix: opts.ClientOrderIdPrefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PeggedOrder{ib: ib}, nil
+}
+
+// Progress returns the current state of the order; Working is the resting
+// order.
+func (p *PeggedOrder) Progress() IcebergProgress {
+	return p.ib.Progress()
+}
+
+// Step polls the order once, repricing or placing it as needed, and
+// reports whether it is done.
+func (p *PeggedOrder) Step(ctx context.Context) (bool, error) {
+	return p.ib.Step(ctx)
+}
+
+// Run maintains the order until it is filled, as Iceberg.Run does.
+func (p *PeggedOrder) Run(ctx context.Context, onError func(error)) error {
+	return p.ib.Run(ctx, onError)
+}
+
+// Close implements Closer. It stops Run loops and cancels the resting
+// order.
+func (p *PeggedOrder) Close(ctx context.Context) error {
+	return p.ib.Close(ctx)
+}
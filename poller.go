@@ -0,0 +1,333 @@
+package wallex
+
+import (
+	"context"
+	"math/rand/v2"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// PollFunc fetches one value for a Poller job.
+type PollFunc func(ctx context.Context) (interface{}, error)
+
+// PollResult is delivered to Poller subscribers.
+type PollResult struct {
+	// Name is the job that produced the result.
+	Name string
+
+	// Value is the fetched value, or the last good value when Err is set.
+	Value interface{}
+
+	// Err is set when the fetch failed.
+	Err error
+
+	// At is when the fetch completed.
+	At time.Time
+}
+
+// PollJob configures one periodically polled endpoint.
+type PollJob struct {
+	// Name identifies the job for subscribers. Must be unique per Poller.
+	Name string
+
+	// Interval is the nominal time between polls.
+	Interval time.Duration
+
+	// Jitter randomizes each interval by up to ±Jitter×Interval, so many
+	// jobs with the same interval do not fire in lockstep. Range [0, 1).
+	Jitter float64
+
+	// Fetch retrieves the value.
+	Fetch PollFunc
+}
+
+// Poller runs periodic fetches and fans results out to subscribers.
+//
+// Features:
+//   - jittered intervals per job
+//   - coalescing: a tick that fires while the previous fetch of the same
+//     job is still running is skipped instead of piling up requests
+//   - de-duplication: a result equal to the previous one is not delivered
+//   - shared results: any number of subscribers receive the same fetch
+//
+// Requests made by Fetch functions built from a Client go through the
+// client RateLimiter, so all jobs share the global rate budget.
+//
+// Subscribers that do not keep up miss results rather than blocking the
+// poller. Poller is safe for concurrent use.
+type Poller struct {
+	mu     sync.Mutex
+	jobs   map[string]*pollJob
+	cancel context.CancelFunc
+	ctx    context.Context
+	wg     sync.WaitGroup
+}
+
+type pollJob struct {
+	PollJob
+
+	mu      sync.Mutex
+	subs    map[int]chan PollResult
+	nextSub int
+	last    *PollResult
+	running bool
+}
+
+// NewPoller creates an idle Poller. Add jobs and call Start.
+func NewPoller() *Poller {
+	return &Poller{jobs: make(map[string]*pollJob)}
+}
+
+// Add registers a job. Jobs added after Start begin polling immediately.
+func (p *Poller) Add(job PollJob) error {
+	if job.Name == "" || job.Fetch == nil || job.Interval <= 0 {
+		return &GoWallexError{
+			Message: "poll job requires a name, a fetch function and a positive interval",
+			Err:     nil,
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, exists := p.jobs[job.Name]; exists {
+		return &GoWallexError{
+			Message: "poll job " + job.Name + " already exists",
+			Err:     nil,
+		}
+	}
+
+	j := &pollJob{PollJob: job, subs: make(map[int]chan PollResult)}
+	p.jobs[job.Name] = j
+	if p.ctx != nil {
+		p.wg.Add(1)
+		go p.run(p.ctx, j)
+	}
+	return nil
+}
+
+// Subscribe returns a channel receiving results of the named job, and a
+// function that cancels the subscription. The latest known result, if any,
+// is delivered immediately. The channel is closed when the subscription is
+// cancelled or the Poller stops.
+func (p *Poller) Subscribe(name string, buffer int) (<-chan PollResult, func(), error) {
+	p.mu.Lock()
+	j, ok := p.jobs[name]
+	p.mu.Unlock()
+	if !ok {
+		return nil, nil, &GoWallexError{
+			Message: "unknown poll job " + name,
+			Err:     nil,
+		}
+	}
+	if buffer < 1 {
+		buffer = 1
+	}
+
+	ch := make(chan PollResult, buffer)
+
+	j.mu.Lock()
+	id := j.nextSub
+	j.nextSub++
+	j.subs[id] = ch
+	if j.last != nil {
+		ch <- *j.last
+	}
+	j.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			j.mu.Lock()
+			defer j.mu.Unlock()
+			if c, ok := j.subs[id]; ok {
+				delete(j.subs, id)
+				close(c)
+			}
+		})
+	}
+	return ch, unsubscribe, nil
+}
+
+// Latest returns the most recent result of the named job.
+func (p *Poller) Latest(name string) (PollResult, bool) {
+	p.mu.Lock()
+	j, ok := p.jobs[name]
+	p.mu.Unlock()
+	if !ok {
+		return PollResult{}, false
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.last == nil {
+		return PollResult{}, false
+	}
+	return *j.last, true
+}
+
+// Start begins polling all jobs until ctx is done or Stop is called.
+// Calling Start on a running Poller has no effect.
+func (p *Poller) Start(ctx context.Context) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.ctx != nil {
+		return
+	}
+
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	for _, j := range p.jobs {
+		p.wg.Add(1)
+		go p.run(p.ctx, j)
+	}
+}
+
+// Stop stops all jobs, waits for in-flight fetches to return and closes all
+// subscriber channels. A stopped Poller cannot be restarted.
+func (p *Poller) Stop() {
+	_ = p.Close(context.Background())
+}
+
+// Close implements Closer. It behaves like Stop but gives up waiting for
+// in-flight fetches when ctx expires; subscriber channels are then closed
+// once the fetches return.
+func (p *Poller) Close(ctx context.Context) error {
+	p.mu.Lock()
+	cancel := p.cancel
+	p.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+
+	if err := waitGroupDone(ctx, &p.wg); err != nil {
+		go func() {
+			p.wg.Wait()
+			p.closeSubscribers()
+		}()
+		return err
+	}
+	p.closeSubscribers()
+	return nil
+}
+
+func (p *Poller) closeSubscribers() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, j := range p.jobs {
+		j.mu.Lock()
+		for id, ch := range j.subs {
+			delete(j.subs, id)
+			close(ch)
+		}
+		j.mu.Unlock()
+	}
+}
+
+func (p *Poller) run(ctx context.Context, j *pollJob) {
+	defer p.wg.Done()
+
+	var fetches sync.WaitGroup
+	defer fetches.Wait()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		j.mu.Lock()
+		busy := j.running
+		j.running = true
+		j.mu.Unlock()
+
+		if !busy {
+			fetches.Add(1)
+			go func() {
+				defer fetches.Done()
+				j.fetch(ctx)
+			}()
+		}
+
+		timer.Reset(jittered(j.Interval, j.Jitter))
+	}
+}
+
+func (j *pollJob) fetch(ctx context.Context) {
+	value, err := j.Fetch(ctx)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.running = false
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	res := PollResult{Name: j.Name, Value: value, Err: err, At: time.Now()}
+	if err != nil {
+		if j.last != nil {
+			res.Value = j.last.Value
+		}
+	} else if j.last != nil && j.last.Err == nil && reflect.DeepEqual(j.last.Value, value) {
+		return
+	}
+	j.last = &res
+
+	for _, ch := range j.subs {
+		select {
+		case ch <- res:
+		default:
+		}
+	}
+}
+
+// jittered returns d randomized by up to ±frac×d.
+func jittered(d time.Duration, frac float64) time.Duration {
+	if frac <= 0 {
+		return d
+	}
+	if frac >= 1 {
+		frac = 0.99
+	}
+	delta := (rand.Float64()*2 - 1) * frac * float64(d)
+	return d + time.Duration(delta)
+}
+
+// PollMarkets returns a PollFunc fetching GetMarketsInfo.
+func (c *Client) PollMarkets() PollFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return c.getMarketsInfo(ctx)
+	}
+}
+
+// PollOrderBook returns a PollFunc fetching GetOrderBook(symbol).
+func (c *Client) PollOrderBook(symbol string) PollFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return c.getOrderBook(ctx, symbol)
+	}
+}
+
+// PollAllOrderBooks returns a PollFunc fetching GetAllOrderBooks.
+func (c *Client) PollAllOrderBooks() PollFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return c.getAllOrderBooks(ctx)
+	}
+}
+
+// PollRecentTrades returns a PollFunc fetching GetRecentTrades(symbol).
+func (c *Client) PollRecentTrades(symbol string) PollFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return c.getRecentTrades(ctx, symbol)
+	}
+}
+
+// PollWallets returns a PollFunc fetching GetWallets.
+func (c *Client) PollWallets() PollFunc {
+	return func(ctx context.Context) (interface{}, error) {
+		return c.getWallets(ctx)
+	}
+}
@@ -0,0 +1,30 @@
+package wallex
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer bounds the capacity of buffers returned to bufferPool so
+// that one large /v2/depth/all response does not pin memory forever.
+const maxPooledBuffer = 1 << 20
+
+// bufferPool recycles request and response buffers across calls. Request
+// bodies are small, so the order path normally reuses buffers without
+// allocating.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	bufferPool.Put(buf)
+}
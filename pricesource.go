@@ -0,0 +1,140 @@
+package wallex
+
+import (
+	"context"
+)
+
+// PriceSource supplies the reference price of a symbol, the price a
+// valuation, a risk check or a trigger compares against. Which price fits
+// depends on the use: the last trade for reporting, the mid-price for fair
+// value, a book mark where last prints are sparse or easily pushed.
+//
+// Client.RiskExposureWithPrices values orders for risk checks with one, and
+// report.SourceRates values assets with one.
+type PriceSource interface {
+	Price(ctx context.Context, symbol string) (float64, error)
+}
+
+// PriceSourceFunc adapts a function to a PriceSource.
+type PriceSourceFunc func(ctx context.Context, symbol string) (float64, error)
+
+// Price implements PriceSource.
+func (f PriceSourceFunc) Price(ctx context.Context, symbol string) (float64, error) {
+	return f(ctx, symbol)
+}
+
+// PriceKind selects the price a built-in PriceSource reports.
+type PriceKind string
+
+const (
+	// PriceLast is the price of the last trade.
+	PriceLast PriceKind = "last"
+
+	// PriceMid is the mid-point of the best bid and ask.
+	PriceMid PriceKind = "mid"
+
+	// PriceMark is the mid-point of the best bid and ask weighted by the
+	// quantity resting at the opposite side, (bid×askSize + ask×bidSize) /
+	// (bidSize + askSize). It leans towards the side about to be taken
+	// out, and is harder to move than the last trade in thin markets.
+	PriceMark PriceKind = "mark"
+)
+
+// Mark returns the quantity-weighted mid-point of the best bid and ask, see
+// PriceMark. It falls back to Mid when the sizes are unknown, and is 0 when
+// either side is missing.
+func (q QuoteTick) Mark() float64 {
+	if q.Bid <= 0 || q.Ask <= 0 {
+		return 0
+	}
+	if q.BidSize <= 0 || q.AskSize <= 0 {
+		return q.Mid()
+	}
+	return (q.Bid*q.AskSize + q.Ask*q.BidSize) / (q.BidSize + q.AskSize)
+}
+
+// Price returns the price of kind.
+func (q QuoteTick) Price(kind PriceKind) float64 {
+	switch kind {
+	case PriceLast:
+		return q.Last
+	case PriceMid:
+		return q.Mid()
+	case PriceMark:
+		return q.Mark()
+	}
+	return 0
+}
+
+// PriceSource returns a PriceSource of kind that queries Wallex on every
+// call: the ticker of GET /v1/markets for PriceLast, the book of GET
+// /v1/depth for PriceMid and PriceMark. MarketDataCache.PriceSource serves
+// the same prices without requests.
+//
+// Authentication: NOT required.
+func (c *Client) PriceSource(kind PriceKind) PriceSource {
+	return PriceSourceFunc(func(ctx context.Context, symbol string) (float64, error) {
+		if err := validPriceKind(kind); err != nil {
+			return 0, err
+		}
+		symbol, err := c.resolveSymbol(ctx, symbol)
+		if err != nil {
+			return 0, err
+		}
+
+		var q QuoteTick
+		if kind == PriceLast {
+			markets, err := c.getMarketsInfo(ctx)
+			if err != nil {
+				return 0, err
+			}
+			info, ok := markets.Get(symbol)
+			if !ok {
+				return 0, &UnknownSymbolError{
+					GoWallexError: GoWallexError{Message: "unknown symbol " + symbol, Err: nil},
+					Symbol:        symbol,
+				}
+			}
+			q.Last = info.Stats.LastPriceFloat()
+		} else {
+			depth, err := c.getOrderBook(ctx, symbol)
+			if err != nil {
+				return 0, err
+			}
+			q = quoteTick(&MarketSnapshot{Symbol: symbol, Book: depth.Result})
+		}
+		return reportPrice(symbol, kind, q)
+	})
+}
+
+// PriceSource returns a PriceSource of kind reading the cached quotes, see
+// LastQuote. It never sends requests: symbols not loaded yet are an error.
+func (m *MarketDataCache) PriceSource(kind PriceKind) PriceSource {
+	return PriceSourceFunc(func(_ context.Context, symbol string) (float64, error) {
+		if err := validPriceKind(kind); err != nil {
+			return 0, err
+		}
+		q, ok := m.LastQuote(symbol)
+		if !ok {
+			return 0, &GoWallexError{Message: "no market data cached for " + symbol, Err: nil}
+		}
+		return reportPrice(symbol, kind, q)
+	})
+}
+
+func validPriceKind(kind PriceKind) error {
+	switch kind {
+	case PriceLast, PriceMid, PriceMark:
+		return nil
+	}
+	return invalidParam("kind", string(kind), "must be "+string(PriceLast)+", "+string(PriceMid)+" or "+string(PriceMark))
+}
+
+// reportPrice returns the price of kind in q, or an error when it is not
+// available.
+func reportPrice(symbol string, kind PriceKind, q QuoteTick) (float64, error) {
+	if p := q.Price(kind); p > 0 {
+		return p, nil
+	}
+	return 0, &GoWallexError{Message: "no " + string(kind) + " price for " + symbol, Err: nil}
+}
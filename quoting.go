@@ -0,0 +1,318 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// QuoteConfig configures a Quoter.
+type QuoteConfig struct {
+	// Symbol is the market to quote, e.g. "BTCUSDT".
+	Symbol string
+
+	// Spread is the full relative distance between bid and ask, e.g. 0.002
+	// quotes 10 bps on each side of the (skewed) reference price.
+	Spread float64
+
+	// Size is the base quantity of each quote.
+	Size float64
+
+	// TargetInventory is the base-asset holding the quoter steers towards.
+	TargetInventory float64
+
+	// MaxInventory is the deviation from TargetInventory at which skew is
+	// at its maximum. Zero disables skew.
+	MaxInventory float64
+
+	// MaxSkew is the relative shift of both quotes at full deviation. When
+	// long, quotes move down to sell more eagerly; when short, they move up.
+	MaxSkew float64
+
+	// Tolerance is the relative move of either target price that triggers
+	// a requote, e.g. 0.0005 for 5 bps. Zero requotes on any change.
+	Tolerance float64
+
+	// ClientOrderIdPrefix prefixes the client order ids of quotes.
+	// Defaults to "q-<symbol>".
+	ClientOrderIdPrefix string
+}
+
+// Quote is a pair of resting orders placed by a Quoter.
+type Quote struct {
+	Bid      float64
+	Ask      float64
+	BidOrder *t.BaseOrder
+	AskOrder *t.BaseOrder
+	PlacedAt time.Time
+}
+
+// Quoter maintains a bid and an ask around a reference price.
+//
+// Each Update computes target prices from the reference price, the
+// configured spread and the inventory skew. When the targets moved beyond
+// Tolerance from the resting quotes, or a side is missing, the old quotes
+// are canceled and new ones placed with the batch cancel and CreateOrders
+// APIs. Prices and quantities are rounded to the market's tick and step
+// sizes; the bid is rounded down and the ask up so rounding never narrows
+// the spread.
+//
+// Fills are not observed by the Quoter itself. Feed fills into the
+// inventory passed to Update and call Invalidate (for example from an
+// OrderTracker callback) once a quote fills so it is replaced.
+//
+// Quoter implements Closer; Close pulls the resting quotes. It is safe for
+// concurrent use; concurrent Update calls are serialized.
+type Quoter struct {
+	c   *Client
+	cfg QuoteConfig
+
+	priceDecimals int
+	qtyDecimals   int
+
+	mu      sync.Mutex
+	current Quote
+	stale   bool
+	seq     atomic.Uint64
+}
+
+// NewQuoter returns a Quoter for cfg.Symbol. Tick and step sizes are loaded
+// from GET /v1/markets.
+func NewQuoter(ctx context.Context, c *Client, cfg QuoteConfig) (*Quoter, error) {
+	if cfg.Spread <= 0 || cfg.Size <= 0 {
+		return nil, &GoWallexError{
+			Message: "quote spread and size must be positive",
+			Err:     nil,
+		}
+	}
+
+	symbol, err := c.resolveSymbol(ctx, cfg.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	cfg.Symbol = symbol
+
+	markets, err := c.getMarketsInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := markets.Get(symbol)
+	if !ok {
+		return nil, &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "unknown symbol " + symbol, Err: nil},
+			Symbol:        symbol,
+		}
+	}
+
+	if cfg.ClientOrderIdPrefix == "" {
+		cfg.ClientOrderIdPrefix = "q-" + symbol
+	}
+
+	return &Quoter{
+		c:             c,
+		cfg:           cfg,
+		priceDecimals: int(info.TickSize),
+		qtyDecimals:   int(info.StepSize),
+	}, nil
+}
+
+// Targets returns the bid and ask prices the Quoter would place for the
+// reference price and inventory, after rounding.
+func (q *Quoter) Targets(ref, inventory float64) (bid, ask float64) {
+	mid := ref
+	if q.cfg.MaxInventory > 0 && q.cfg.MaxSkew > 0 {
+		dev := (inventory - q.cfg.TargetInventory) / q.cfg.MaxInventory
+		dev = math.Max(-1, math.Min(1, dev))
+		mid = ref * (1 - dev*q.cfg.MaxSkew)
+	}
+	half := q.cfg.Spread / 2
+	bid = u.RoundDown(mid*(1-half), q.priceDecimals)
+	ask = u.RoundUp(mid*(1+half), q.priceDecimals)
+	return bid, ask
+}
+
+// Current returns the resting quote. Zero prices mean no quote is placed.
+func (q *Quoter) Current() Quote {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.current
+}
+
+// Invalidate forces the next Update to replace the quotes, e.g. after one
+// of them filled.
+func (q *Quoter) Invalidate() {
+	q.mu.Lock()
+	q.stale = true
+	q.mu.Unlock()
+}
+
+// Update requotes around ref if needed and reports whether it did.
+//
+// On a partial failure (one side placed, the other rejected) the placed
+// side rests and the error is returned; the next Update pulls it and
+// requotes both sides.
+func (q *Quoter) Update(ctx context.Context, ref, inventory float64) (bool, error) {
+	if ref <= 0 {
+		return false, &GoWallexError{
+			Message: "reference price must be positive",
+			Err:     nil,
+		}
+	}
+
+	bid, ask := q.Targets(ref, inventory)
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	cur := q.current
+	if !q.stale && cur.BidOrder != nil && cur.AskOrder != nil &&
+		!movedBeyond(cur.Bid, bid, q.cfg.Tolerance) && !movedBeyond(cur.Ask, ask, q.cfg.Tolerance) {
+		return false, nil
+	}
+
+	if err := q.pull(ctx); err != nil {
+		return false, err
+	}
+
+	qty := strconv.FormatFloat(u.RoundDown(q.cfg.Size, q.qtyDecimals), 'f', q.qtyDecimals, 64)
+	params := []t.CreateOrderParams{
+		{
+			Symbol:        q.cfg.Symbol,
+			Type:          t.OrderTypeLimit,
+			Side:          t.SideBuy,
+			Price:         strconv.FormatFloat(bid, 'f', q.priceDecimals, 64),
+			Quantity:      qty,
+			ClientOrderId: q.nextID("b"),
+		},
+		{
+			Symbol:        q.cfg.Symbol,
+			Type:          t.OrderTypeLimit,
+			Side:          t.SideSell,
+			Price:         strconv.FormatFloat(ask, 'f', q.priceDecimals, 64),
+			Quantity:      qty,
+			ClientOrderId: q.nextID("a"),
+		},
+	}
+
+	results := q.c.CreateOrders(ctx, params)
+	q.current = Quote{PlacedAt: time.Now()}
+	var errs []error
+	if r := results[0]; r.Err == nil {
+		q.current.Bid, q.current.BidOrder = bid, r.Order
+	} else {
+		errs = append(errs, r.Err)
+	}
+	if r := results[1]; r.Err == nil {
+		q.current.Ask, q.current.AskOrder = ask, r.Order
+	} else {
+		errs = append(errs, r.Err)
+	}
+	q.stale = len(errs) > 0
+
+	if len(errs) > 0 {
+		return true, &GoWallexError{
+			Message: "failed to place quotes",
+			Err:     errors.Join(errs...),
+		}
+	}
+	return true, nil
+}
+
+// Cancel pulls the resting quotes.
+func (q *Quoter) Cancel(ctx context.Context) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pull(ctx)
+}
+
+// Close implements Closer by pulling the resting quotes.
+func (q *Quoter) Close(ctx context.Context) error {
+	return q.Cancel(ctx)
+}
+
+// pull cancels both resting quotes. A quote whose cancel is refused is
+// forgotten only when Wallex reports it closed or unknown; otherwise it is
+// kept and the error returned. q.mu must be held.
+func (q *Quoter) pull(ctx context.Context) error {
+	var ids []string
+	if q.current.BidOrder != nil {
+		ids = append(ids, q.current.BidOrder.ClientOrderId)
+	}
+	if q.current.AskOrder != nil {
+		ids = append(ids, q.current.AskOrder.ClientOrderId)
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for _, r := range q.c.cancelOrders(ctx, ids) {
+		if r.Err == nil {
+			q.forget(r.ClientOrderId)
+			continue
+		}
+		var apiErr *APIError
+		if errors.As(r.Err, &apiErr) && apiErr.StatusCode < 500 && apiErr.StatusCode != 429 {
+			// The cancel may have been refused because the quote is
+			// already filled or canceled; only the order status tells
+			// that apart from a rejected request.
+			if closed, err := q.closed(ctx, r.ClientOrderId); err == nil && closed {
+				q.forget(r.ClientOrderId)
+				continue
+			}
+		}
+		errs = append(errs, r.Err)
+	}
+	if len(errs) > 0 {
+		return &GoWallexError{
+			Message: "failed to cancel quotes",
+			Err:     errors.Join(errs...),
+		}
+	}
+	return nil
+}
+
+// closed reports whether the order is closed on the server or unknown to
+// it, so that nothing of it rests on the book.
+func (q *Quoter) closed(ctx context.Context, clientOrderId string) (bool, error) {
+	resp, err := q.c.getOrderStatus(ctx, clientOrderId)
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound {
+			return true, nil
+		}
+		return false, err
+	}
+	return IsTerminalStatus(resp.Result.Status), nil
+}
+
+func (q *Quoter) forget(clientOrderId string) {
+	if o := q.current.BidOrder; o != nil && o.ClientOrderId == clientOrderId {
+		q.current.Bid, q.current.BidOrder = 0, nil
+	}
+	if o := q.current.AskOrder; o != nil && o.ClientOrderId == clientOrderId {
+		q.current.Ask, q.current.AskOrder = 0, nil
+	}
+}
+
+func (q *Quoter) nextID(side string) string {
+	return fmt.Sprintf("%s-%s-%d-%d", q.cfg.ClientOrderIdPrefix, side, time.Now().UnixMilli(), q.seq.Add(1))
+}
+
+// movedBeyond reports whether next differs from cur by more than tol,
+// relative to cur.
+func movedBeyond(cur, next, tol float64) bool {
+	if cur == 0 {
+		return true
+	}
+	return math.Abs(next-cur)/cur > tol
+}
@@ -0,0 +1,196 @@
+package wallex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles outgoing API requests.
+//
+// Wait blocks until a request may be sent or ctx is done. Client calls Wait
+// once per HTTP request when ClientOptions.RateLimiter is set.
+//
+// One limiter may be shared by any number of clients, e.g. clients using
+// different API keys of the same account. To share a budget between
+// processes use a SharedRateLimiter backed by an external RateStore.
+type RateLimiter interface {
+	Wait(ctx context.Context) error
+}
+
+// TokenBucket is a RateLimiter that refills at a fixed rate up to a maximum
+// burst size. It is safe for concurrent use.
+type TokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a TokenBucket allowing ratePerSecond requests per
+// second with bursts of up to burst requests.
+//
+// Wallex documents a global limit of 100 requests/sec, so
+// NewRateLimiter(100, 100) matches the server-side budget.
+func NewRateLimiter(ratePerSecond float64, burst int) *TokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &TokenBucket{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a token is available or ctx is done. PriorityLow
+// requests wait while less than a fifth of the burst remains.
+func (b *TokenBucket) Wait(ctx context.Context) error {
+	var keep float64
+	if PriorityFromContext(ctx) < PriorityNormal {
+		keep = min(b.burst/5, b.burst-1)
+	}
+	for {
+		delay := b.reserveKeeping(keep)
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// reserve takes a token if one is available and returns zero, otherwise it
+// returns how long to wait before the next token is due.
+func (b *TokenBucket) reserve() time.Duration {
+	return b.reserveKeeping(0)
+}
+
+// reserveKeeping is like reserve but leaves at least keep tokens in the
+// bucket.
+func (b *TokenBucket) reserveKeeping(keep float64) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens >= 1+keep {
+		b.tokens--
+		return 0
+	}
+	if b.rate <= 0 {
+		return time.Second
+	}
+	return time.Duration((1 + keep - b.tokens) / b.rate * float64(time.Second))
+}
+
+// RateStore holds rate limit state outside the process, so that clients in
+// several processes can share one budget, e.g. the account-wide limit of a
+// fleet of bots.
+//
+// Take atomically takes one token from the bucket identified by key,
+// creating it full if it does not exist. The bucket refills at rate tokens
+// per second up to burst. If no token is available, Take returns how long
+// to wait before retrying, without taking a token. A Redis implementation
+// typically runs the token bucket as a Lua script keyed by key.
+type RateStore interface {
+	Take(ctx context.Context, key string, rate float64, burst int) (wait time.Duration, err error)
+}
+
+// SharedRateLimiter is a RateLimiter whose token bucket lives in a
+// RateStore. Every SharedRateLimiter using the same store and key, in any
+// process, draws from the same budget.
+type SharedRateLimiter struct {
+	store RateStore
+	key   string
+	rate  float64
+	burst int
+}
+
+// NewSharedRateLimiter creates a RateLimiter allowing ratePerSecond requests
+// per second with bursts of up to burst requests across all limiters
+// sharing store and key.
+func NewSharedRateLimiter(store RateStore, key string, ratePerSecond float64, burst int) *SharedRateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &SharedRateLimiter{store: store, key: key, rate: ratePerSecond, burst: burst}
+}
+
+// Wait blocks until the shared bucket grants a token, ctx is done or the
+// store fails.
+func (l *SharedRateLimiter) Wait(ctx context.Context) error {
+	for {
+		delay, err := l.store.Take(ctx, l.key, l.rate, l.burst)
+		if err != nil {
+			return &GoWallexError{
+				Message: "rate store failed",
+				Err:     err,
+			}
+		}
+		if delay <= 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// MemoryRateStore is an in-process RateStore. It shares budgets between
+// clients of one process and serves as a reference implementation and test
+// double for external stores.
+type MemoryRateStore struct {
+	mu      sync.Mutex
+	buckets map[string]*TokenBucket
+}
+
+// NewMemoryRateStore creates an empty MemoryRateStore.
+func NewMemoryRateStore() *MemoryRateStore {
+	return &MemoryRateStore{buckets: make(map[string]*TokenBucket)}
+}
+
+// Take implements RateStore.
+func (s *MemoryRateStore) Take(_ context.Context, key string, rate float64, burst int) (time.Duration, error) {
+	s.mu.Lock()
+	b, ok := s.buckets[key]
+	if !ok {
+		b = NewRateLimiter(rate, burst)
+		s.buckets[key] = b
+	}
+	s.mu.Unlock()
+	return b.reserve(), nil
+}
+
+// MultiRateLimiter waits on several limiters in turn, e.g. a per-key
+// limiter and a fleet-wide SharedRateLimiter, so a request is only sent
+// once every limit allows it.
+type MultiRateLimiter []RateLimiter
+
+// Wait implements RateLimiter.
+func (m MultiRateLimiter) Wait(ctx context.Context) error {
+	for _, l := range m {
+		if err := l.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package wallex
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Raw calls an arbitrary Wallex endpoint, e.g. one that is undocumented or
+// newer than this package, through the same authentication, rate
+// limiting, retries, metrics and error parsing as the built-in methods.
+//
+// path is relative to the version, e.g. "/account/orders"; version
+// defaults to "v1". body is sent as query parameters for GET and as JSON
+// otherwise, like RequestContext does.
+//
+// Raw returns the standard envelope, whose Result holds the undecoded
+// "result" field, together with the complete response body for endpoints
+// that do not use the envelope. Non-2xx responses return an *APIError.
+//
+// Example:
+//
+//	resp, _, err := client.Raw(ctx, wallex.MethodGet, "/account/fee", "v1", true, nil)
+//	var fees map[string]json.RawMessage
+//	err = json.Unmarshal(resp.Result, &fees)
+func (c *Client) Raw(ctx context.Context, method, path, version string, auth bool, body interface{}) (*t.BaseResponse, []byte, error) {
+	if version == "" {
+		version = "v1"
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	var raw json.RawMessage
+	if err := c.ApiRequestContext(ctx, strings.ToUpper(method), path, version, auth, body, &raw); err != nil {
+		return nil, nil, err
+	}
+
+	// Bodies without the envelope, such as UDF responses, leave resp
+	// empty; the caller decodes raw instead.
+	var resp t.BaseResponse
+	_ = json.Unmarshal(raw, &resp)
+	return &resp, raw, nil
+}
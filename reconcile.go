@@ -0,0 +1,231 @@
+package wallex
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// DiscrepancyKind classifies a difference found by the Reconciler.
+type DiscrepancyKind string
+
+const (
+	// DiscrepancyUnknown is an order open on Wallex that the tracker does
+	// not know, e.g. one placed before a crash and never persisted.
+	DiscrepancyUnknown DiscrepancyKind = "unknown"
+
+	// DiscrepancyMissing is an order the tracker considers active but that
+	// is no longer open on Wallex; its final state was missed.
+	DiscrepancyMissing DiscrepancyKind = "missing"
+)
+
+// UnknownOrderPolicy selects what the Reconciler does with unknown orders.
+type UnknownOrderPolicy int
+
+const (
+	// ReportUnknown only reports unknown orders. This is the default.
+	ReportUnknown UnknownOrderPolicy = iota
+
+	// CancelUnknown cancels unknown orders.
+	CancelUnknown
+
+	// AdoptUnknown starts tracking unknown orders.
+	AdoptUnknown
+)
+
+// Discrepancy is one difference between the tracker and Wallex.
+type Discrepancy struct {
+	Kind          DiscrepancyKind
+	ClientOrderId string
+
+	// Order is the order as reported by Wallex. For a missing order it is
+	// the result of the status lookup, or the last tracked snapshot when
+	// the lookup failed.
+	Order t.BaseOrder
+
+	// Action is what the Reconciler did: "reported", "canceled",
+	// "adopted" or "updated".
+	Action string
+
+	// Err is set when the action failed.
+	Err error
+}
+
+// ReconcileReport is the outcome of one Reconcile pass.
+type ReconcileReport struct {
+	At            time.Time
+	Open          int
+	Tracked       int
+	Discrepancies []Discrepancy
+}
+
+// ReconcilerOptions tunes a Reconciler.
+type ReconcilerOptions struct {
+	// Symbols restricts reconciliation to these markets. Empty means all.
+	Symbols []string
+
+	// Policy selects how unknown orders are handled.
+	Policy UnknownOrderPolicy
+
+	// MinAge skips unknown orders created less than MinAge ago, so orders
+	// being placed concurrently are not cancelled before the caller could
+	// track them. Orders with a zero CreatedAt are never skipped.
+	MinAge time.Duration
+
+	// OnDiscrepancy is called for every discrepancy after its action ran.
+	OnDiscrepancy func(Discrepancy)
+}
+
+// Reconciler compares the orders of an OrderTracker with the open orders
+// on Wallex, which is essential after a process crash:
+//   - unknown orders (open on Wallex, not tracked) are reported, cancelled
+//     or adopted according to the Policy;
+//   - missing orders (tracked as active, not open on Wallex) are looked up
+//     and their final state is fed into the tracker, firing its callbacks.
+//
+// Reconciler implements Closer and is safe for concurrent use.
+type Reconciler struct {
+	client  *Client
+	tracker *OrderTracker
+	opts    ReconcilerOptions
+
+	mu sync.Mutex
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewReconciler returns a Reconciler for the orders of tracker. Call
+// Reconcile once at startup, and Run to keep reconciling.
+func NewReconciler(c *Client, tracker *OrderTracker, opts ReconcilerOptions) *Reconciler {
+	return &Reconciler{
+		client:  c,
+		tracker: tracker,
+		opts:    opts,
+		stop:    make(chan struct{}),
+	}
+}
+
+// Reconcile runs one pass. Concurrent calls are serialized. An error is
+// returned only when the open orders could not be listed; failures of
+// individual actions are recorded in the discrepancies.
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileReport, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Snapshot the tracker first: an order completing after the listing
+	// would otherwise look missing without being so.
+	active := r.tracker.Active()
+
+	open, err := r.client.ListOpenOrders(ctx, r.opts.Symbols...)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &ReconcileReport{At: now, Open: len(open.Result.Orders), Tracked: len(active)}
+
+	openIds := make(map[string]struct{}, len(open.Result.Orders))
+	for _, o := range open.Result.Orders {
+		openIds[o.ClientOrderId] = struct{}{}
+		if _, known := r.tracker.State(o.ClientOrderId); known {
+			continue
+		}
+		if r.opts.MinAge > 0 && !o.CreatedAt.IsZero() && now.Sub(o.CreatedAt.Time) < r.opts.MinAge {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, r.unknown(ctx, o))
+	}
+
+	inScope := make(map[string]bool, len(r.opts.Symbols))
+	for _, s := range r.opts.Symbols {
+		inScope[s] = true
+	}
+	for _, id := range active {
+		if _, ok := openIds[id]; ok {
+			continue
+		}
+		tracked, ok := r.tracker.State(id)
+		if !ok || (len(inScope) > 0 && !inScope[tracked.Symbol]) {
+			continue
+		}
+		report.Discrepancies = append(report.Discrepancies, r.missing(ctx, tracked))
+	}
+
+	for _, d := range report.Discrepancies {
+		if r.opts.OnDiscrepancy != nil {
+			r.opts.OnDiscrepancy(d)
+		}
+		r.client.metrics().Add("wallex_reconcile_discrepancies_total", 1,
+			Label{Name: "kind", Value: string(d.Kind)},
+			Label{Name: "action", Value: d.Action},
+		)
+	}
+	return report, nil
+}
+
+func (r *Reconciler) unknown(ctx context.Context, o t.BaseOrder) Discrepancy {
+	d := Discrepancy{Kind: DiscrepancyUnknown, ClientOrderId: o.ClientOrderId, Order: o, Action: "reported"}
+	switch r.opts.Policy {
+	case CancelUnknown:
+		d.Action = "canceled"
+		if _, err := r.client.cancelOrder(ctx, o.ClientOrderId); err != nil {
+			d.Err = err
+		}
+	case AdoptUnknown:
+		d.Action = "adopted"
+		r.tracker.Track(o)
+	}
+	return d
+}
+
+func (r *Reconciler) missing(ctx context.Context, tracked t.BaseOrder) Discrepancy {
+	d := Discrepancy{Kind: DiscrepancyMissing, ClientOrderId: tracked.ClientOrderId, Order: tracked, Action: "updated"}
+	status, err := r.client.getOrderStatus(ctx, tracked.ClientOrderId)
+	if err != nil {
+		d.Action = "reported"
+		d.Err = err
+		return d
+	}
+	d.Order = status.Result
+	r.tracker.Update(status.Result)
+	return d
+}
+
+// Run calls Reconcile every interval until ctx is done, the Reconciler is
+// closed or the client is shut down. Errors are passed to onError when it
+// is non-nil.
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	r.loops.Add(1)
+	defer r.loops.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stop:
+			return
+		case <-r.client.Done():
+			return
+		case <-ticker.C:
+			if _, err := r.Reconcile(ctx); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Close implements Closer. It stops all Run loops and waits for them.
+func (r *Reconciler) Close(ctx context.Context) error {
+	r.stopOnce.Do(func() { close(r.stop) })
+	return waitGroupDone(ctx, &r.loops)
+}
@@ -0,0 +1,49 @@
+package wallex
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+// MarketRecord is one market-data response captured by a MarketRecorder.
+type MarketRecord struct {
+	// Time is when the response was received.
+	Time time.Time `json:"time"`
+
+	// Endpoint is the endpoint key, e.g. EndpointDepth.
+	Endpoint string `json:"endpoint"`
+
+	// Symbol is the symbol query parameter of the request, if any.
+	Symbol string `json:"symbol,omitempty"`
+
+	// Body is the response body as received, after decompression.
+	Body json.RawMessage `json:"body"`
+}
+
+// MarketRecorder receives every successful response to an unauthenticated
+// request, i.e. all market data, including the polls behind
+// WatchOrderBook, MarketDataCache and TradeTape, for later replay and
+// research. Account data is never recorded.
+//
+// Record is called on the requesting goroutine and must not block for
+// long. The record, including Body, is not reused by the client.
+type MarketRecorder interface {
+	Record(rec MarketRecord)
+}
+
+// recordResponse passes a market-data response to the Recorder, if any.
+func (c *Client) recordResponse(method, rawURL string, auth bool, body []byte) {
+	if c.Recorder == nil || auth {
+		return
+	}
+	rec := MarketRecord{
+		Time:     time.Now(),
+		Endpoint: c.endpointKey(method, rawURL),
+		Body:     append(json.RawMessage(nil), body...),
+	}
+	if u, err := url.Parse(rawURL); err == nil {
+		rec.Symbol = u.Query().Get("symbol")
+	}
+	c.Recorder.Record(rec)
+}
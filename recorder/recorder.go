@@ -0,0 +1,290 @@
+// Package recorder writes wallex market data to disk for replay and
+// research.
+//
+// A Recorder implements wallex.MarketRecorder: set it as
+// ClientOptions.Recorder and every market-data response the client
+// receives is appended, as one JSON object per line, to a file in the
+// recorder's directory:
+//
+//	rec, err := recorder.New(recorder.Options{Dir: "data", Compress: true})
+//	client, err := wallex.NewClient(wallex.ClientOptions{Recorder: rec})
+//	defer rec.Close(context.Background())
+//
+// Files are named after the time they were opened, e.g.
+// wallex-20240512T104105.000000000Z.jsonl.gz, so that sorting the names
+// sorts them chronologically. A new file is started when the current one
+// exceeds MaxFileSize or MaxFileAge, and the oldest files are deleted
+// when the directory exceeds MaxTotalSize.
+//
+// Load reads recordings back, and Replay serves them to a client in place
+// of Wallex, at real-time or accelerated speed, so that order book and
+// strategy code can be exercised deterministically.
+package recorder
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wallex "github.com/darhelm/go-wallex"
+)
+
+// Defaults used by New.
+const (
+	DefaultPrefix      = "wallex"
+	DefaultMaxFileSize = 64 << 20
+	DefaultMaxFileAge  = time.Hour
+)
+
+const timeLayout = "20060102T150405.000000000Z"
+
+// Options configures a Recorder.
+type Options struct {
+	// Dir is the directory the files are written to. It is created if
+	// needed. Required.
+	Dir string
+
+	// Prefix starts every file name. Defaults to DefaultPrefix.
+	Prefix string
+
+	// Compress gzips the files (.jsonl.gz instead of .jsonl).
+	Compress bool
+
+	// MaxFileSize is the number of uncompressed bytes after which a new
+	// file is started. Defaults to DefaultMaxFileSize.
+	MaxFileSize int64
+
+	// MaxFileAge is the time after which a new file is started. Defaults
+	// to DefaultMaxFileAge.
+	MaxFileAge time.Duration
+
+	// MaxTotalSize, if positive, caps the size of the recorder's files on
+	// disk: after each rotation the oldest files are deleted until the
+	// total fits. The current file is never deleted.
+	MaxTotalSize int64
+
+	// OnError receives write errors, which Record cannot return. The
+	// failed record is lost; recording continues with the next one.
+	OnError func(error)
+}
+
+// Recorder appends market records to rotating files. It is safe for
+// concurrent use.
+type Recorder struct {
+	opts Options
+
+	mu     sync.Mutex
+	file   *os.File
+	gz     *gzip.Writer
+	w      *bufio.Writer
+	size   int64
+	opened time.Time
+	closed bool
+}
+
+var (
+	_ wallex.MarketRecorder = (*Recorder)(nil)
+	_ wallex.Closer         = (*Recorder)(nil)
+)
+
+// New creates opts.Dir if needed and returns a Recorder writing to it. The
+// first file is opened by the first record.
+func New(opts Options) (*Recorder, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("recorder: directory is required")
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = DefaultPrefix
+	}
+	if opts.MaxFileSize <= 0 {
+		opts.MaxFileSize = DefaultMaxFileSize
+	}
+	if opts.MaxFileAge <= 0 {
+		opts.MaxFileAge = DefaultMaxFileAge
+	}
+	if err := os.MkdirAll(opts.Dir, 0o700); err != nil {
+		return nil, fmt.Errorf("recorder: create directory: %w", err)
+	}
+	return &Recorder{opts: opts}, nil
+}
+
+// Record implements wallex.MarketRecorder.
+func (r *Recorder) Record(rec wallex.MarketRecord) {
+	if err := r.Write(rec); err != nil && r.opts.OnError != nil {
+		r.opts.OnError(err)
+	}
+}
+
+// Write appends rec to the current file, rotating it first if it is full
+// or too old. Records written after Close are rejected.
+func (r *Recorder) Write(rec wallex.MarketRecord) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("recorder: encode record: %w", err)
+	}
+	line = append(line, '\n')
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return fmt.Errorf("recorder: closed")
+	}
+	if r.w != nil && (r.size+int64(len(line)) > r.opts.MaxFileSize || time.Since(r.opened) >= r.opts.MaxFileAge) {
+		if err := r.closeFile(); err != nil {
+			return err
+		}
+		if err := r.prune(); err != nil {
+			return err
+		}
+	}
+	if r.w == nil {
+		if err := r.openFile(rec.Time); err != nil {
+			return err
+		}
+	}
+
+	n, err := r.w.Write(line)
+	r.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("recorder: write: %w", err)
+	}
+	return nil
+}
+
+// Flush writes buffered records to the current file. Records are
+// otherwise flushed when the buffer fills, on rotation and on Close.
+func (r *Recorder) Flush() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.w == nil {
+		return nil
+	}
+	if err := r.w.Flush(); err != nil {
+		return fmt.Errorf("recorder: flush: %w", err)
+	}
+	if r.gz != nil {
+		if err := r.gz.Flush(); err != nil {
+			return fmt.Errorf("recorder: flush: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close implements wallex.Closer. It flushes and closes the current file.
+func (r *Recorder) Close(context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+	r.closed = true
+	return r.closeFile()
+}
+
+// Files returns the paths of the recorder's files in chronological order,
+// including the one being written.
+func (r *Recorder) Files() ([]string, error) {
+	return Files(r.opts.Dir, r.opts.Prefix)
+}
+
+// Files returns the paths of the recording files in dir whose names start
+// with prefix (DefaultPrefix if empty), in chronological order.
+func Files(dir, prefix string) ([]string, error) {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: list files: %w", err)
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix+"-") {
+			continue
+		}
+		if strings.HasSuffix(name, ".jsonl") || strings.HasSuffix(name, ".jsonl.gz") {
+			out = append(out, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+func (r *Recorder) openFile(at time.Time) error {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	name := r.opts.Prefix + "-" + at.UTC().Format(timeLayout) + ".jsonl"
+	if r.opts.Compress {
+		name += ".gz"
+	}
+	f, err := os.OpenFile(filepath.Join(r.opts.Dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("recorder: open file: %w", err)
+	}
+
+	var dst io.Writer = f
+	if r.opts.Compress {
+		r.gz = gzip.NewWriter(f)
+		dst = r.gz
+	}
+	r.file, r.w = f, bufio.NewWriterSize(dst, 64<<10)
+	r.size, r.opened = 0, time.Now()
+	return nil
+}
+
+func (r *Recorder) closeFile() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.w.Flush()
+	if r.gz != nil {
+		if gzErr := r.gz.Close(); err == nil {
+			err = gzErr
+		}
+	}
+	if closeErr := r.file.Close(); err == nil {
+		err = closeErr
+	}
+	r.file, r.gz, r.w = nil, nil, nil
+	if err != nil {
+		return fmt.Errorf("recorder: close file: %w", err)
+	}
+	return nil
+}
+
+// prune deletes the oldest files while the total exceeds MaxTotalSize.
+func (r *Recorder) prune() error {
+	if r.opts.MaxTotalSize <= 0 {
+		return nil
+	}
+	files, err := r.Files()
+	if err != nil {
+		return err
+	}
+	sizes := make([]int64, len(files))
+	var total int64
+	for i, path := range files {
+		if info, err := os.Stat(path); err == nil {
+			sizes[i] = info.Size()
+			total += sizes[i]
+		}
+	}
+	for i := 0; i < len(files) && total > r.opts.MaxTotalSize; i++ {
+		if err := os.Remove(files[i]); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("recorder: delete old file: %w", err)
+		}
+		total -= sizes[i]
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package recorder
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	wallex "github.com/darhelm/go-wallex"
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// ReadFile returns the records of a recording file, in file order. Files
+// ending in .gz are decompressed.
+func ReadFile(path string) ([]wallex.MarketRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("recorder: open file: %w", err)
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("recorder: read %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var records []wallex.MarketRecord
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 64<<20)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var rec wallex.MarketRecord
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return records, fmt.Errorf("recorder: decode %s: %w", path, err)
+		}
+		records = append(records, rec)
+	}
+	if err := sc.Err(); err != nil {
+		return records, fmt.Errorf("recorder: read %s: %w", path, err)
+	}
+	return records, nil
+}
+
+// Load reads the given files, e.g. the result of Files, and returns their
+// records ordered by time.
+func Load(paths ...string) ([]wallex.MarketRecord, error) {
+	var records []wallex.MarketRecord
+	for _, path := range paths {
+		recs, err := ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, recs...)
+	}
+	sort.SliceStable(records, func(i, j int) bool { return records[i].Time.Before(records[j].Time) })
+	return records, nil
+}
+
+// ReplayOptions configures a Replay.
+type ReplayOptions struct {
+	// Speed is the replay rate relative to the recording: 1 is real time,
+	// 10 is ten times faster. Zero or negative replays without pausing.
+	Speed float64
+}
+
+// Replay plays a recording back through the client's own streaming code.
+//
+// A Replay is an http.RoundTripper that answers market-data requests with
+// the latest recorded response at or before its clock, so a client built
+// with HTTPClient runs WatchOrderBook, MarketDataCache, TradeTape and the
+// market-data endpoints unchanged against recorded data:
+//
+//	records, _ := recorder.Load(files...)
+//	replay := recorder.NewReplay(records, recorder.ReplayOptions{Speed: 10})
+//	client, _ := wallex.NewClient(wallex.ClientOptions{HttpClient: replay.HTTPClient()})
+//	updates, _ := client.WatchOrderBook(ctx, "BTCUSDT", wallex.BookWatchOptions{})
+//	go replay.Run(ctx, nil)
+//
+// The clock starts at the time of the first record, with nothing played,
+// and is moved by Run or Step. With Step, or Run at Speed zero, the order
+// in which records become visible is deterministic. Requests that were not
+// recorded, or not yet reached, get HTTP 404; account endpoints are never
+// served.
+//
+// Replay is safe for concurrent use.
+type Replay struct {
+	records []wallex.MarketRecord
+	speed   float64
+
+	mu     sync.RWMutex
+	pos    int
+	now    time.Time
+	latest map[replayKey]int
+}
+
+type replayKey struct {
+	endpoint string
+	symbol   string
+}
+
+var _ http.RoundTripper = (*Replay)(nil)
+
+// NewReplay returns a Replay of records, which must be ordered by time as
+// returned by Load.
+func NewReplay(records []wallex.MarketRecord, opts ReplayOptions) *Replay {
+	r := &Replay{
+		records: records,
+		speed:   opts.Speed,
+		latest:  make(map[replayKey]int),
+	}
+	if len(records) > 0 {
+		r.now = records[0].Time
+	}
+	return r
+}
+
+// HTTPClient returns an HTTP client served by the replay, for
+// ClientOptions.HttpClient.
+func (r *Replay) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Now returns the replay clock: the time of the last record played.
+func (r *Replay) Now() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.now
+}
+
+// Remaining returns the number of records not yet played.
+func (r *Replay) Remaining() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.records) - r.pos
+}
+
+// Step plays the next record without pausing and returns it. It returns
+// false at the end of the recording.
+func (r *Replay) Step() (wallex.MarketRecord, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.pos >= len(r.records) {
+		return wallex.MarketRecord{}, false
+	}
+	rec := r.records[r.pos]
+	r.latest[replayKey{rec.Endpoint, rec.Symbol}] = r.pos
+	r.now = rec.Time
+	r.pos++
+	return rec, true
+}
+
+// Run plays the remaining records, pausing between them to keep the pace
+// set by Speed, and calls fn, if set, after each one. It returns nil at
+// the end of the recording, the error of fn, or that of ctx.
+func (r *Replay) Run(ctx context.Context, fn func(wallex.MarketRecord) error) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	<-timer.C
+
+	for {
+		r.mu.RLock()
+		done := r.pos >= len(r.records)
+		var gap time.Duration
+		if !done {
+			gap = r.records[r.pos].Time.Sub(r.now)
+		}
+		r.mu.RUnlock()
+		if done {
+			return nil
+		}
+
+		if r.speed > 0 && gap > 0 {
+			timer.Reset(time.Duration(float64(gap) / r.speed))
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-timer.C:
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		rec, ok := r.Step()
+		if !ok {
+			return nil
+		}
+		if fn != nil {
+			if err := fn(rec); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replay) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	key := replayKey{
+		endpoint: req.Method + " " + req.URL.Path,
+		symbol:   req.URL.Query().Get("symbol"),
+	}
+
+	r.mu.RLock()
+	i, ok := r.latest[key]
+	var body json.RawMessage
+	var at time.Time
+	if ok {
+		body, at = r.records[i].Body, r.records[i].Time
+	}
+	r.mu.RUnlock()
+
+	if !ok {
+		return replayResponse(req, http.StatusNotFound, []byte(`{"success":false,"message":"not in replay"}`), time.Time{}), nil
+	}
+	return replayResponse(req, http.StatusOK, body, at), nil
+}
+
+func replayResponse(req *http.Request, status int, body []byte, at time.Time) *http.Response {
+	h := make(http.Header)
+	h.Set("Content-Type", "application/json")
+	if !at.IsZero() {
+		h.Set("X-Replay-Time", at.UTC().Format(time.RFC3339Nano))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Events converts recorded order books and trades to strategy events,
+// ordered by time, for backtest.Run. Each order book response becomes one
+// Book event stamped with its receive time; trades are deduplicated across
+// overlapping polls and stamped with their execution time. Other records
+// and records that fail to decode are skipped.
+func Events(records []wallex.MarketRecord) []wallex.MarketEvent {
+	type tradeKey struct {
+		nanos      int64
+		price, qty t.StringOrNumber
+		isBuyOrder bool
+	}
+	type tradeState struct {
+		newest time.Time
+		seen   map[tradeKey]struct{}
+	}
+	tradeStates := make(map[string]*tradeState)
+
+	var events []wallex.MarketEvent
+	for _, rec := range records {
+		switch rec.Endpoint {
+		case wallex.EndpointDepth:
+			var depth t.Depth
+			if json.Unmarshal(rec.Body, &depth) != nil {
+				continue
+			}
+			book := depth.Result
+			events = append(events, wallex.MarketEvent{Symbol: rec.Symbol, Time: rec.Time, Book: &book})
+
+		case wallex.EndpointTrades:
+			var trades t.Trades
+			if json.Unmarshal(rec.Body, &trades) != nil {
+				continue
+			}
+			st := tradeStates[rec.Symbol]
+			if st == nil {
+				st = &tradeState{seen: make(map[tradeKey]struct{})}
+				tradeStates[rec.Symbol] = st
+			}
+			batchNewest := st.newest
+			var fresh []t.Trade
+			for _, tr := range trades.Result.LatestTrades {
+				k := tradeKey{tr.Timestamp.UnixNano(), tr.Price, tr.Quantity, tr.IsBuyOrder}
+				if tr.Timestamp.Before(st.newest) {
+					continue
+				}
+				if _, dup := st.seen[k]; dup {
+					continue
+				}
+				if tr.Symbol == "" {
+					tr.Symbol = rec.Symbol
+				}
+				fresh = append(fresh, tr)
+				if tr.Timestamp.After(batchNewest) {
+					batchNewest = tr.Timestamp.Time
+				}
+			}
+			if batchNewest.After(st.newest) {
+				st.newest = batchNewest
+				st.seen = make(map[tradeKey]struct{})
+			}
+			for i := range fresh {
+				tr := fresh[i]
+				if tr.Timestamp.Equal(st.newest) {
+					st.seen[tradeKey{tr.Timestamp.UnixNano(), tr.Price, tr.Quantity, tr.IsBuyOrder}] = struct{}{}
+				}
+				events = append(events, wallex.MarketEvent{Symbol: tr.Symbol, Time: tr.Timestamp.Time, Trade: &tr})
+			}
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
@@ -0,0 +1,135 @@
+package wallex
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Redacted replaces secrets in every string the SDK emits.
+const Redacted = "[REDACTED]"
+
+// minRedactLen is the shortest key that is redacted by substring. Shorter
+// values would match unrelated text.
+const minRedactLen = 6
+
+// RedactKey returns s with every occurrence of key replaced by Redacted.
+func RedactKey(s, key string) string {
+	if len(key) < minRedactLen || !strings.Contains(s, key) {
+		return s
+	}
+	return strings.ReplaceAll(s, key, Redacted)
+}
+
+// redactedError hides the API key in the message of a wrapped error while
+// keeping it reachable through errors.Is and errors.As.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// redactError scrubs key from an SDK error before it leaves the client:
+// messages, parsed API error fields and wrapped causes.
+func redactError(err error, key string) error {
+	if err == nil || len(key) < minRedactLen {
+		return err
+	}
+	switch e := err.(type) {
+	case *RequestError:
+		redactBase(&e.GoWallexError, key)
+	case *APIError:
+		redactBase(&e.GoWallexError, key)
+		e.Message = RedactKey(e.Message, key)
+		for k, v := range e.Fields {
+			for i := range v {
+				v[i] = RedactKey(v[i], key)
+			}
+			e.Fields[k] = v
+		}
+		if len(e.Result) > 0 && strings.Contains(string(e.Result), key) {
+			e.Result = nil
+		}
+	case *MaintenanceError:
+		redactError(&e.APIError, key)
+	case *GoWallexError:
+		redactBase(e, key)
+	default:
+		if msg := err.Error(); strings.Contains(msg, key) {
+			return &redactedError{msg: RedactKey(msg, key), err: err}
+		}
+	}
+	return err
+}
+
+func redactBase(e *GoWallexError, key string) {
+	e.Message = RedactKey(e.Message, key)
+	if e.Err != nil {
+		if msg := e.Err.Error(); strings.Contains(msg, key) {
+			e.Err = &redactedError{msg: RedactKey(msg, key), err: e.Err}
+		}
+	}
+}
+
+// String describes the client without its API key, so that printing a
+// Client with %v or %+v never reveals it.
+func (c *Client) String() string {
+	return fmt.Sprintf("wallex.Client{BaseUrl: %q, Version: %q, ApiKey: %s}", c.baseUrl, c.Version, c.redactedKey())
+}
+
+// GoString is like String, for %#v.
+func (c *Client) GoString() string {
+	return c.String()
+}
+
+// LogValue implements slog.LogValuer without exposing the API key.
+func (c *Client) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("base_url", c.baseUrl),
+		slog.String("version", c.Version),
+		slog.String("api_key", c.redactedKey()),
+	)
+}
+
+// String describes the options without the API key.
+func (o ClientOptions) String() string {
+	return fmt.Sprintf("wallex.ClientOptions{BaseUrl: %q, Version: %q, ApiKey: %s, Timeout: %s, MaxRetries: %d}",
+		o.BaseUrl, o.Version, redactedIfSet(o.ApiKey), o.Timeout, o.MaxRetries)
+}
+
+// GoString is like String, for %#v.
+func (o ClientOptions) GoString() string {
+	return o.String()
+}
+
+// LogValue implements slog.LogValuer without exposing the API key.
+func (o ClientOptions) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("base_url", o.BaseUrl),
+		slog.String("version", o.Version),
+		slog.String("api_key", redactedIfSet(o.ApiKey)),
+	)
+}
+
+// redactedKey is redactedIfSet for the client's key. Custom KeyProviders
+// are not called; their key is assumed to be set.
+func (c *Client) redactedKey() string {
+	switch k := c.keys.(type) {
+	case nil:
+		return redactedIfSet("")
+	case *RotatingKey:
+		return redactedIfSet(k.Current())
+	case StaticKey:
+		return redactedIfSet(string(k))
+	}
+	return Redacted
+}
+
+func redactedIfSet(key string) string {
+	if key == "" {
+		return `""`
+	}
+	return Redacted
+}
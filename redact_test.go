@@ -0,0 +1,162 @@
+package wallex
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testApiKey = "wx-test-key-5f3a9c1e"
+
+// recordingSink keeps every MarketRecord it receives.
+type recordingSink struct {
+	mu      sync.Mutex
+	records []MarketRecord
+}
+
+func (s *recordingSink) Record(rec MarketRecord) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, rec)
+}
+
+// assertNoKey fails the test if out contains the API key.
+func assertNoKey(t *testing.T, what, out string) {
+	t.Helper()
+	if strings.Contains(out, testApiKey) {
+		t.Errorf("%s leaks the API key: %s", what, out)
+	}
+}
+
+// echoServer answers every request by echoing the API key it received:
+// authenticated requests fail with the key in the message, detail and
+// extra fields of the error, public ones succeed with it in the body.
+func echoServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-API-Key")
+		w.Header().Set("Content-Type", "application/json")
+		if key == "" {
+			fmt.Fprintf(w, `{"success":true,"message":"ok","result":{"latestTrades":[]},"echo":%q}`, key)
+			return
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprintf(w, `{"success":false,"code":1201,"message":"invalid key %s","detail":"key %s","result":{"key":%q}}`, key, key, key)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestRedactKey(t *testing.T) {
+	if got := RedactKey("key="+testApiKey+"&key="+testApiKey, testApiKey); got != "key="+Redacted+"&key="+Redacted {
+		t.Errorf("RedactKey = %q", got)
+	}
+	if got := RedactKey("abc", "ab"); got != "abc" {
+		t.Errorf("short keys must not be redacted, got %q", got)
+	}
+}
+
+func TestRedactionOfClientOutput(t *testing.T) {
+	srv := echoServer(t)
+	var logs bytes.Buffer
+	var events []string
+	sink := &recordingSink{}
+	opts := ClientOptions{
+		BaseUrl:    srv.URL,
+		ApiKey:     testApiKey,
+		MaxRetries: 1,
+		Backoff:    ConstantBackoff{},
+		Logger:     slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		OnError: func(_ context.Context, ev ErrorEvent) {
+			events = append(events, fmt.Sprintf("%v %+v", ev.Err, ev))
+		},
+		Recorder: sink,
+	}
+	client, err := NewClient(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.GetWallets()
+	if err == nil {
+		t.Fatal("expected the authenticated request to fail")
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected a 401 *APIError, got %#v", err)
+	}
+	if !strings.Contains(err.Error(), Redacted) {
+		t.Errorf("expected the echoed key to be replaced, got %q", err.Error())
+	}
+	assertNoKey(t, "Error", err.Error())
+	assertNoKey(t, "%+v of the error", fmt.Sprintf("%+v", err))
+	assertNoKey(t, "%#v of the error", fmt.Sprintf("%#v", err))
+	assertNoKey(t, "APIError.Fields", fmt.Sprint(apiErr.Fields))
+	assertNoKey(t, "APIError.Result", string(apiErr.Result))
+
+	if _, err := client.GetRecentTrades("BTCUSDT"); err != nil {
+		t.Fatal(err)
+	}
+	if len(sink.records) == 0 {
+		t.Fatal("expected the public response to be recorded")
+	}
+	for _, rec := range sink.records {
+		assertNoKey(t, "recorded "+rec.Endpoint, string(rec.Body))
+	}
+
+	if len(events) == 0 {
+		t.Fatal("expected OnError events")
+	}
+	for _, ev := range events {
+		assertNoKey(t, "OnError event", ev)
+	}
+	if logs.Len() == 0 {
+		t.Fatal("expected log records")
+	}
+	assertNoKey(t, "log records", logs.String())
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		assertNoKey(t, format+" of the client", fmt.Sprintf(format, client))
+		assertNoKey(t, format+" of the options", fmt.Sprintf(format, opts))
+	}
+	logs.Reset()
+	slog.New(slog.NewTextHandler(&logs, nil)).Info("client", "client", client, "options", opts)
+	assertNoKey(t, "logged client", logs.String())
+}
+
+// keyEchoTransport fails every request with an error quoting its URL and
+// API key, like a proxy or transport wrapper might.
+type keyEchoTransport struct{}
+
+var errTransport = errors.New("transport down")
+
+func (keyEchoTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return nil, fmt.Errorf("%s with key %s: %w", req.URL, req.Header.Get("X-API-Key"), errTransport)
+}
+
+func TestRedactionOfTransportErrors(t *testing.T) {
+	client, err := NewClient(ClientOptions{
+		BaseUrl:    "http://wallex.invalid",
+		ApiKey:     testApiKey,
+		HttpClient: &http.Client{Transport: keyEchoTransport{}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.GetWallets()
+	if err == nil {
+		t.Fatal("expected the request to fail")
+	}
+	assertNoKey(t, "Error", err.Error())
+	assertNoKey(t, "%+v of the error", fmt.Sprintf("%+v", err))
+	if !errors.Is(err, errTransport) {
+		t.Errorf("redaction must keep the cause reachable, got %v", err)
+	}
+}
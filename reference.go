@@ -0,0 +1,108 @@
+package wallex
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ReferencePrice supplies benchmark prices of Wallex symbols from outside
+// Wallex, e.g. the global BTC/USDT price of another venue for BTCUSDT.
+// Mapping Wallex symbols to the external instruments, and converting
+// currencies such as TMN, is up to the implementation.
+//
+// It returns the price and when it was observed, and false when it has none
+// for symbol. It is called synchronously from the components using it, e.g.
+// for every trade by an AnomalyDetector, so it should return quickly;
+// ReferencePrices suits feeds pushed from another venue's stream.
+type ReferencePrice interface {
+	ReferencePrice(ctx context.Context, symbol string) (price float64, at time.Time, ok bool)
+}
+
+// ReferencePriceFunc adapts a function to a ReferencePrice.
+type ReferencePriceFunc func(ctx context.Context, symbol string) (float64, time.Time, bool)
+
+// ReferencePrice implements ReferencePrice.
+func (f ReferencePriceFunc) ReferencePrice(ctx context.Context, symbol string) (float64, time.Time, bool) {
+	return f(ctx, symbol)
+}
+
+// ReferencePrices is a ReferencePrice holding the latest price set per
+// symbol, for external feeds to push into. It is safe for concurrent use.
+type ReferencePrices struct {
+	mu     sync.RWMutex
+	prices map[string]referenceEntry
+}
+
+type referenceEntry struct {
+	price float64
+	at    time.Time
+}
+
+// NewReferencePrices returns an empty ReferencePrices.
+func NewReferencePrices() *ReferencePrices {
+	return &ReferencePrices{prices: make(map[string]referenceEntry)}
+}
+
+// Set records price as the reference of symbol, observed at at. Prices
+// older than the recorded one are ignored.
+func (r *ReferencePrices) Set(symbol string, price float64, at time.Time) {
+	symbol = NormalizeSymbol(symbol)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if e, ok := r.prices[symbol]; ok && at.Before(e.at) {
+		return
+	}
+	r.prices[symbol] = referenceEntry{price: price, at: at}
+}
+
+// ReferencePrice implements ReferencePrice.
+func (r *ReferencePrices) ReferencePrice(_ context.Context, symbol string) (float64, time.Time, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.prices[NormalizeSymbol(symbol)]
+	return e.price, e.at, ok && e.price > 0
+}
+
+// ReferenceComparison compares a Wallex price with its external reference.
+type ReferenceComparison struct {
+	Symbol string
+
+	// Price is the Wallex price, Reference the external one observed at
+	// ReferenceAt.
+	Price       float64
+	Reference   float64
+	ReferenceAt time.Time
+
+	// Premium is Price/Reference - 1: positive when Wallex trades above
+	// the reference, e.g. 0.004 for 40 bps.
+	Premium float64
+}
+
+// CompareReference returns how the price of symbol from prices, e.g.
+// c.PriceSource(PriceMid), compares with its reference, for arbitrage
+// monitoring. A reference older than maxAge is an error; zero accepts any
+// age.
+func CompareReference(ctx context.Context, prices PriceSource, ref ReferencePrice, symbol string, maxAge time.Duration) (*ReferenceComparison, error) {
+	reference, at, ok := ref.ReferencePrice(ctx, symbol)
+	if !ok || reference <= 0 {
+		return nil, &GoWallexError{Message: "no reference price for " + symbol, Err: nil}
+	}
+	if maxAge > 0 && time.Since(at) > maxAge {
+		return nil, &GoWallexError{
+			Message: "reference price for " + symbol + " is " + time.Since(at).Round(time.Millisecond).String() + " old",
+			Err:     nil,
+		}
+	}
+	price, err := prices.Price(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return &ReferenceComparison{
+		Symbol:      symbol,
+		Price:       price,
+		Reference:   reference,
+		ReferenceAt: at,
+		Premium:     price/reference - 1,
+	}, nil
+}
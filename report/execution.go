@@ -0,0 +1,241 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// ParentOrder is an order executed through one or more child orders, e.g.
+// the slices of a TWAP or a single market order.
+type ParentOrder struct {
+	// ID identifies the parent order in the report.
+	ID string
+
+	Symbol string
+
+	// Side is t.SideBuy or t.SideSell.
+	Side string
+
+	// ArrivalTime is when the decision to trade was made, and ArrivalMid
+	// the mid price of the book at that time, e.g. from
+	// MarketDataCache.LastQuote. Without ArrivalMid, arrival slippage and
+	// cost are not computed.
+	ArrivalTime time.Time
+	ArrivalMid  float64
+
+	// Children are the final snapshots of the child orders, with fills.
+	Children []t.BaseOrder
+}
+
+// ExecutionQuality measures how well a parent order was executed.
+//
+// Slippages are in basis points of the benchmark and signed so that a
+// positive value is a cost: paying more than the benchmark on a buy, or
+// receiving less on a sell.
+type ExecutionQuality struct {
+	ID     string
+	Symbol string
+	Side   string
+
+	// Start is the arrival time, or the first child's creation when it is
+	// unknown; End is the last fill.
+	Start time.Time
+	End   time.Time
+
+	// Quantity and Notional are the executed totals; AvgPrice is their
+	// ratio. Fills counts the executions across all children.
+	Quantity float64
+	Notional float64
+	AvgPrice float64
+	Fills    int
+
+	ArrivalMid         float64
+	ArrivalSlippageBps float64
+
+	// VWAP is the volume-weighted price of all market trades between
+	// Start and End, the fills included. MarketVolume is their quantity
+	// and Participation the share of it the order took. All three are zero
+	// when no market trades are known for the period.
+	VWAP            float64
+	VWAPSlippageBps float64
+	MarketVolume    float64
+	Participation   float64
+
+	// Fees are the fees paid by asset, and FeeCost their value in the
+	// quote asset; fees in the base asset are valued at AvgPrice.
+	Fees    map[string]float64
+	FeeCost float64
+
+	// Shortfall is the cost against the arrival mid in the quote asset,
+	// (AvgPrice - ArrivalMid) × Quantity for a buy. TotalCost adds FeeCost,
+	// and TotalCostBps expresses it relative to the arrival value.
+	Shortfall    float64
+	TotalCost    float64
+	TotalCostBps float64
+}
+
+// AnalyzeExecution measures the execution quality of parent against the
+// public trades of its market, e.g. from TradeTape or a recording. Trades
+// of other symbols are ignored.
+func AnalyzeExecution(parent ParentOrder, market []t.Trade) ExecutionQuality {
+	q := ExecutionQuality{
+		ID:         parent.ID,
+		Symbol:     parent.Symbol,
+		Side:       parent.Side,
+		Start:      parent.ArrivalTime,
+		ArrivalMid: parent.ArrivalMid,
+	}
+	sign := 1.0
+	if parent.Side == t.SideSell {
+		sign = -1
+	}
+
+	var baseFees float64
+	_, quote, splitErr := SplitSymbol(parent.Symbol, nil)
+	for _, child := range parent.Children {
+		if created := child.CreatedAt.Time; parent.ArrivalTime.IsZero() && !created.IsZero() && (q.Start.IsZero() || created.Before(q.Start)) {
+			q.Start = created
+		}
+		for _, f := range child.Fills {
+			q.Fills++
+			q.Quantity += f.Quantity.Float()
+			q.Notional += f.Notional()
+			if ts := f.Timestamp.Time; ts.After(q.End) {
+				q.End = ts
+			}
+			fee := f.Commission.Float()
+			if fee == 0 {
+				continue
+			}
+			if q.Fees == nil {
+				q.Fees = make(map[string]float64)
+			}
+			q.Fees[f.CommissionAsset] += fee
+			if splitErr == nil && f.CommissionAsset == quote {
+				q.FeeCost += fee
+			} else {
+				baseFees += fee
+			}
+		}
+	}
+	if q.Quantity == 0 {
+		return q
+	}
+	q.AvgPrice = q.Notional / q.Quantity
+	q.FeeCost += baseFees * q.AvgPrice
+
+	if q.ArrivalMid > 0 {
+		q.ArrivalSlippageBps = sign * (q.AvgPrice - q.ArrivalMid) / q.ArrivalMid * 1e4
+		q.Shortfall = sign * (q.AvgPrice - q.ArrivalMid) * q.Quantity
+		q.TotalCost = q.Shortfall + q.FeeCost
+		q.TotalCostBps = q.TotalCost / (q.ArrivalMid * q.Quantity) * 1e4
+	}
+
+	var volume, value float64
+	for _, tr := range market {
+		if tr.Symbol != "" && tr.Symbol != parent.Symbol {
+			continue
+		}
+		if ts := tr.Timestamp.Time; ts.Before(q.Start) || ts.After(q.End) {
+			continue
+		}
+		price, err1 := strconv.ParseFloat(tr.Price.String(), 64)
+		qty, err2 := strconv.ParseFloat(tr.Quantity.String(), 64)
+		if err1 != nil || err2 != nil || qty <= 0 {
+			continue
+		}
+		volume += qty
+		value += price * qty
+	}
+	if volume > 0 {
+		q.MarketVolume = volume
+		q.VWAP = value / volume
+		q.VWAPSlippageBps = sign * (q.AvgPrice - q.VWAP) / q.VWAP * 1e4
+		q.Participation = q.Quantity / volume
+		if q.Participation > 1 {
+			q.Participation = 1
+		}
+	}
+	return q
+}
+
+// ExecutionReport is the execution quality of a set of parent orders.
+type ExecutionReport struct {
+	Orders []ExecutionQuality
+
+	// AvgArrivalSlippageBps and AvgVWAPSlippageBps are averages weighted by
+	// notional, over the orders that have the benchmark.
+	AvgArrivalSlippageBps float64
+	AvgVWAPSlippageBps    float64
+}
+
+// AnalyzeExecutions runs AnalyzeExecution for every parent order and
+// aggregates the results.
+func AnalyzeExecutions(parents []ParentOrder, market []t.Trade) *ExecutionReport {
+	rep := &ExecutionReport{Orders: make([]ExecutionQuality, 0, len(parents))}
+	var arrivalW, vwapW float64
+	for _, p := range parents {
+		q := AnalyzeExecution(p, market)
+		rep.Orders = append(rep.Orders, q)
+		if q.ArrivalMid > 0 && q.Notional > 0 {
+			rep.AvgArrivalSlippageBps += q.ArrivalSlippageBps * q.Notional
+			arrivalW += q.Notional
+		}
+		if q.VWAP > 0 {
+			rep.AvgVWAPSlippageBps += q.VWAPSlippageBps * q.Notional
+			vwapW += q.Notional
+		}
+	}
+	if arrivalW > 0 {
+		rep.AvgArrivalSlippageBps /= arrivalW
+	}
+	if vwapW > 0 {
+		rep.AvgVWAPSlippageBps /= vwapW
+	}
+	return rep
+}
+
+// WriteCSV writes one row per parent order with a header row.
+func (r *ExecutionReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"id", "symbol", "side", "start", "end", "quantity", "notional", "avg_price", "fills",
+		"arrival_mid", "arrival_slippage_bps", "vwap", "vwap_slippage_bps", "participation",
+		"fee_cost", "shortfall", "total_cost", "total_cost_bps",
+	})
+	for _, q := range r.Orders {
+		_ = cw.Write([]string{
+			q.ID,
+			q.Symbol,
+			q.Side,
+			formatTime(q.Start),
+			formatTime(q.End),
+			formatFloat(q.Quantity),
+			formatFloat(q.Notional),
+			formatFloat(q.AvgPrice),
+			strconv.Itoa(q.Fills),
+			formatFloat(q.ArrivalMid),
+			formatFloat(q.ArrivalSlippageBps),
+			formatFloat(q.VWAP),
+			formatFloat(q.VWAPSlippageBps),
+			formatFloat(q.Participation),
+			formatFloat(q.FeeCost),
+			formatFloat(q.Shortfall),
+			formatFloat(q.TotalCost),
+			formatFloat(q.TotalCostBps),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatTime(ts time.Time) string {
+	if ts.IsZero() {
+		return ""
+	}
+	return ts.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,196 @@
+package report
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// FeeTotal aggregates fees for one asset or one symbol.
+type FeeTotal struct {
+	// Key is the fee asset (in FeeSummary.ByAsset) or the market symbol
+	// (in FeeSummary.BySymbol).
+	Key string
+
+	// Amount is the raw fee amount. It is only meaningful in ByAsset, where
+	// every fee shares the same asset.
+	Amount float64
+
+	// Value is the fee converted into the summary currency.
+	Value float64
+
+	// Trades is the number of trades that contributed.
+	Trades int
+}
+
+// FeeSummary is the result of SummarizeFees.
+type FeeSummary struct {
+	Currency string
+	From     time.Time
+	To       time.Time
+
+	// Total is the sum of all fees in Currency.
+	Total float64
+
+	ByAsset  []FeeTotal
+	BySymbol []FeeTotal
+}
+
+// SummarizeFees aggregates the fees paid in trades within [opts.From,
+// opts.To], per fee asset and per market symbol, converting each fee into
+// opts.Currency (TMN by default) with opts.Rate at the trade time.
+//
+// Only Currency, the period and Rate of opts are used.
+func SummarizeFees(trades []t.UserTrade, opts Options) (*FeeSummary, error) {
+	opts = opts.withPeriod()
+	currency := opts.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	g := &generator{opts: opts, currency: currency}
+
+	byAsset := make(map[string]*FeeTotal)
+	bySymbol := make(map[string]*FeeTotal)
+	sum := &FeeSummary{Currency: currency, From: opts.From, To: opts.To}
+
+	for _, tr := range trades {
+		if !g.inPeriod(tr.Timestamp) {
+			continue
+		}
+		fee := tr.Fee.Float()
+		if fee == 0 {
+			continue
+		}
+		rate, err := g.rate(tr.FeeAsset, tr.Timestamp)
+		if err != nil {
+			return nil, err
+		}
+		value := fee * rate
+
+		a := byAsset[tr.FeeAsset]
+		if a == nil {
+			a = &FeeTotal{Key: tr.FeeAsset}
+			byAsset[tr.FeeAsset] = a
+		}
+		a.Amount += fee
+		a.Value += value
+		a.Trades++
+
+		s := bySymbol[tr.Symbol]
+		if s == nil {
+			s = &FeeTotal{Key: tr.Symbol}
+			bySymbol[tr.Symbol] = s
+		}
+		s.Value += value
+		s.Trades++
+
+		sum.Total += value
+	}
+
+	sum.ByAsset = sortedTotals(byAsset)
+	sum.BySymbol = sortedTotals(bySymbol)
+	return sum, nil
+}
+
+// sortedTotals returns the totals ordered by descending value.
+func sortedTotals(m map[string]*FeeTotal) []FeeTotal {
+	out := make([]FeeTotal, 0, len(m))
+	for _, v := range m {
+		out = append(out, *v)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Value != out[j].Value {
+			return out[i].Value > out[j].Value
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// MarketRates returns a RateFunc that values assets at their current last
+// price on Wallex, as reported by GetMarketsInfo.
+//
+// An asset is valued through its market against currency directly
+// (e.g. BTCTMN for BTC in TMN), or through USDT when no direct market exists
+// (e.g. XUSDT × USDTTMN). The time argument is ignored, so the function is
+// meant for monitoring recent costs rather than historical accounting.
+func MarketRates(markets *t.MarketInformation, currency string) RateFunc {
+	last := func(_ context.Context, symbol string) (float64, error) {
+		return markets.Result.Symbols[symbol].Stats.LastPriceFloat(), nil
+	}
+	return SourceRates(context.Background(), markets, priceFunc(last), currency)
+}
+
+// PriceSource supplies the price of a market. wallex.PriceSource satisfies
+// it, e.g. client.PriceSource(wallex.PriceMid).
+type PriceSource interface {
+	Price(ctx context.Context, symbol string) (float64, error)
+}
+
+type priceFunc func(ctx context.Context, symbol string) (float64, error)
+
+func (f priceFunc) Price(ctx context.Context, symbol string) (float64, error) {
+	return f(ctx, symbol)
+}
+
+// SourceRates is MarketRates valuing assets at the prices of prices instead
+// of the last price. markets tells which markets exist; prices is queried
+// with ctx for the markets of a route on every call.
+func SourceRates(ctx context.Context, markets *t.MarketInformation, prices PriceSource, currency string) RateFunc {
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+	price := func(symbol string) (float64, bool, error) {
+		if markets == nil {
+			return 0, false, nil
+		}
+		if _, ok := markets.Result.Symbols[symbol]; !ok {
+			return 0, false, nil
+		}
+		p, err := prices.Price(ctx, symbol)
+		if err != nil {
+			return 0, false, err
+		}
+		return p, p > 0, nil
+	}
+
+	return func(asset string, _ time.Time) (float64, error) {
+		if asset == currency {
+			return 1, nil
+		}
+		if p, ok, err := price(asset + currency); ok || err != nil {
+			return p, err
+		}
+		if p, ok, err := price(currency + asset); ok || err != nil {
+			if err != nil {
+				return 0, err
+			}
+			return 1 / p, nil
+		}
+		p, ok, err := price(asset + "USDT")
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			u, ok, err := price("USDT" + currency)
+			if err != nil {
+				return 0, err
+			}
+			if ok {
+				return p * u, nil
+			}
+		}
+		return 0, &rateError{asset: asset, currency: currency}
+	}
+}
+
+type rateError struct {
+	asset    string
+	currency string
+}
+
+func (e *rateError) Error() string {
+	return "report: no market to convert " + e.asset + " into " + e.currency
+}
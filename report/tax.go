@@ -0,0 +1,457 @@
+// Package report builds accounting reports from Wallex user trade history.
+//
+// Reports are denominated in a single currency (TMN by default). Trades in
+// other quote currencies, such as USDT markets, are valued through a
+// caller-supplied RateFunc.
+package report
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// DefaultCurrency is the currency reports are denominated in by default.
+const DefaultCurrency = "TMN"
+
+// RateFunc returns the value of one unit of asset, in the report currency,
+// at the given time. It is only consulted for assets other than the report
+// currency.
+type RateFunc func(asset string, at time.Time) (float64, error)
+
+// Options configures report generation.
+type Options struct {
+	// Currency is the denomination of all values. Defaults to TMN.
+	// IRR is supported as well: Toman amounts are converted to Rial, and
+	// Rate is expected to return values in Toman, which are scaled
+	// accordingly.
+	Currency string
+
+	// From and To bound the reporting period (inclusive). Zero values leave
+	// the respective side open. Trades before From are still used to build
+	// the FIFO lots that later disposals consume.
+	From time.Time
+	To   time.Time
+
+	// JalaliFrom and JalaliTo, when their Year is set, replace From and To
+	// with the start of JalaliFrom and the end of JalaliTo in Tehran time,
+	// since Iranian accounting periods follow the Jalali calendar. For a
+	// whole fiscal year, u.JalaliYearRange gives From and To directly.
+	JalaliFrom u.JalaliDate
+	JalaliTo   u.JalaliDate
+
+	// Rate converts non-report-currency assets into Currency.
+	Rate RateFunc
+
+	// Markets optionally maps symbols to their metadata, used to split a
+	// symbol into base and quote assets. Without it, symbols are split on
+	// the well-known Wallex quote assets (TMN, USDT).
+	Markets map[string]t.SymbolInfo
+}
+
+// EventType classifies report ledger entries.
+type EventType string
+
+const (
+	Acquisition EventType = "ACQUIRE"
+	Disposal    EventType = "DISPOSE"
+)
+
+// Event is a single ledger entry produced from a trade leg.
+type Event struct {
+	Time   time.Time
+	Type   EventType
+	Asset  string
+	Symbol string
+
+	// Quantity of Asset acquired or disposed.
+	Quantity float64
+
+	// Value is the acquisition cost or disposal proceeds in report currency.
+	Value float64
+
+	// CostBasis is the FIFO cost of the disposed quantity (disposals only).
+	CostBasis float64
+
+	// Gain is Value - CostBasis (disposals only).
+	Gain float64
+
+	// Fee is the trading fee attributed to this leg, in report currency.
+	Fee float64
+
+	// Unmatched is the part of a disposal that had no acquisition lot to
+	// match against; its cost basis is counted as zero.
+	Unmatched float64
+}
+
+// AssetSummary aggregates all events of one asset within the period.
+type AssetSummary struct {
+	Asset string
+
+	AcquiredQty  float64
+	AcquiredCost float64
+
+	DisposedQty  float64
+	Proceeds     float64
+	CostBasis    float64
+	RealizedGain float64
+
+	Fees float64
+
+	// RemainingQty and RemainingCost describe the open FIFO lots at the end
+	// of the period.
+	RemainingQty  float64
+	RemainingCost float64
+}
+
+// TaxReport is the result of GenerateTaxReport.
+type TaxReport struct {
+	Currency string
+	From     time.Time
+	To       time.Time
+	Events   []Event
+	Assets   []AssetSummary
+}
+
+type lot struct {
+	qty  float64
+	cost float64 // per unit
+}
+
+// GenerateTaxReport computes per-asset acquisition/disposal summaries with
+// FIFO cost basis from the user's trade history.
+//
+// Each trade has two legs: buying BTC on BTCUSDT acquires BTC and disposes of
+// USDT. Legs in the report currency itself are cash and are not tracked.
+// Fees paid in the base asset reduce the acquired quantity; fees paid in the
+// quote asset add to the cost of a buy and reduce the proceeds of a sale.
+// Trades may be given in any order.
+func GenerateTaxReport(trades []t.UserTrade, opts Options) (*TaxReport, error) {
+	opts = opts.withPeriod()
+	currency := opts.Currency
+	if currency == "" {
+		currency = DefaultCurrency
+	}
+
+	sorted := make([]t.UserTrade, len(trades))
+	copy(sorted, trades)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Timestamp.Before(sorted[j].Timestamp)
+	})
+
+	g := &generator{
+		opts:     opts,
+		currency: currency,
+		lots:     make(map[string][]lot),
+		summary:  make(map[string]*AssetSummary),
+	}
+
+	for _, tr := range sorted {
+		if !opts.To.IsZero() && tr.Timestamp.After(opts.To) {
+			break
+		}
+		if err := g.apply(tr); err != nil {
+			return nil, err
+		}
+	}
+
+	rep := &TaxReport{
+		Currency: currency,
+		From:     opts.From,
+		To:       opts.To,
+		Events:   g.events,
+	}
+	for asset, s := range g.summary {
+		for _, l := range g.lots[asset] {
+			s.RemainingQty += l.qty
+			s.RemainingCost += l.qty * l.cost
+		}
+		rep.Assets = append(rep.Assets, *s)
+	}
+	sort.Slice(rep.Assets, func(i, j int) bool { return rep.Assets[i].Asset < rep.Assets[j].Asset })
+
+	return rep, nil
+}
+
+// withPeriod returns opts with the Jalali bounds converted into From and
+// To.
+func (opts Options) withPeriod() Options {
+	if opts.JalaliFrom.Year != 0 {
+		opts.From, _ = u.JalaliRange(opts.JalaliFrom, opts.JalaliFrom)
+	}
+	if opts.JalaliTo.Year != 0 {
+		_, opts.To = u.JalaliRange(opts.JalaliTo, opts.JalaliTo)
+	}
+	return opts
+}
+
+type generator struct {
+	opts     Options
+	currency string
+	lots     map[string][]lot
+	summary  map[string]*AssetSummary
+	events   []Event
+}
+
+func (g *generator) rate(asset string, at time.Time) (float64, error) {
+	if asset == g.currency {
+		return 1, nil
+	}
+	if r, ok := u.FiatRate(asset, g.currency); ok {
+		return r, nil
+	}
+	if g.currency == u.IRR {
+		r, err := g.rateIn(asset, at)
+		return u.TomanToRial(r), err
+	}
+	return g.rateIn(asset, at)
+}
+
+// isCash reports whether asset is the report currency (or its TMN/IRR
+// counterpart), whose legs are not tracked as holdings.
+func (g *generator) isCash(asset string) bool {
+	_, fiat := u.FiatRate(asset, g.currency)
+	return asset == g.currency || fiat
+}
+
+// rateIn consults the user RateFunc.
+func (g *generator) rateIn(asset string, at time.Time) (float64, error) {
+	if g.opts.Rate == nil {
+		return 0, fmt.Errorf("report: no rate available to convert %s into %s", asset, g.currency)
+	}
+	r, err := g.opts.Rate(asset, at)
+	if err != nil {
+		return 0, fmt.Errorf("report: rate for %s at %s: %w", asset, at.Format(time.RFC3339), err)
+	}
+	return r, nil
+}
+
+func (g *generator) inPeriod(at time.Time) bool {
+	return (g.opts.From.IsZero() || !at.Before(g.opts.From)) && (g.opts.To.IsZero() || !at.After(g.opts.To))
+}
+
+func (g *generator) assetSummary(asset string) *AssetSummary {
+	s, ok := g.summary[asset]
+	if !ok {
+		s = &AssetSummary{Asset: asset}
+		g.summary[asset] = s
+	}
+	return s
+}
+
+func (g *generator) apply(tr t.UserTrade) error {
+	base, quote, err := SplitSymbol(tr.Symbol, g.opts.Markets)
+	if err != nil {
+		return err
+	}
+
+	qty, err := parse(tr.Quantity, "quantity", tr)
+	if err != nil {
+		return err
+	}
+	sum := tr.Sum.Float()
+	if sum == 0 {
+		price, err := parse(tr.Price, "price", tr)
+		if err != nil {
+			return err
+		}
+		sum = price * qty
+	}
+	fee := tr.Fee.Float()
+
+	quoteRate, err := g.rate(quote, tr.Timestamp)
+	if err != nil {
+		return err
+	}
+	value := sum * quoteRate
+
+	feeValue := 0.0
+	if fee != 0 {
+		feeRate, err := g.rate(tr.FeeAsset, tr.Timestamp)
+		if err != nil {
+			return err
+		}
+		feeValue = fee * feeRate
+	}
+
+	if tr.IsBuyer {
+		acquired, spent, cost := qty, sum, value
+		switch tr.FeeAsset {
+		case base:
+			acquired -= fee
+		case quote:
+			spent += fee
+			cost += feeValue
+		}
+		g.acquire(base, tr, acquired, cost, feeValue)
+		if !g.isCash(quote) {
+			g.dispose(quote, tr, spent, cost, 0)
+		}
+		return nil
+	}
+
+	proceeds := value
+	if tr.FeeAsset == quote {
+		proceeds -= feeValue
+	}
+	g.dispose(base, tr, qty, proceeds, feeValue)
+	if !g.isCash(quote) {
+		received := sum
+		if tr.FeeAsset == quote {
+			received -= fee
+		}
+		g.acquire(quote, tr, received, received*quoteRate, 0)
+	}
+	return nil
+}
+
+func (g *generator) acquire(asset string, tr t.UserTrade, qty, cost, fee float64) {
+	if qty <= 0 {
+		return
+	}
+	g.lots[asset] = append(g.lots[asset], lot{qty: qty, cost: cost / qty})
+
+	if !g.inPeriod(tr.Timestamp) {
+		return
+	}
+	s := g.assetSummary(asset)
+	s.AcquiredQty += qty
+	s.AcquiredCost += cost
+	s.Fees += fee
+	g.events = append(g.events, Event{
+		Time:     tr.Timestamp,
+		Type:     Acquisition,
+		Asset:    asset,
+		Symbol:   tr.Symbol,
+		Quantity: qty,
+		Value:    cost,
+		Fee:      fee,
+	})
+}
+
+func (g *generator) dispose(asset string, tr t.UserTrade, qty, proceeds, fee float64) {
+	remaining := qty
+	basis := 0.0
+	lots := g.lots[asset]
+	for remaining > 0 && len(lots) > 0 {
+		take := lots[0].qty
+		if take > remaining {
+			take = remaining
+		}
+		basis += take * lots[0].cost
+		lots[0].qty -= take
+		remaining -= take
+		if lots[0].qty <= 1e-12 {
+			lots = lots[1:]
+		}
+	}
+	g.lots[asset] = lots
+
+	if !g.inPeriod(tr.Timestamp) {
+		return
+	}
+	s := g.assetSummary(asset)
+	s.DisposedQty += qty
+	s.Proceeds += proceeds
+	s.CostBasis += basis
+	s.RealizedGain += proceeds - basis
+	s.Fees += fee
+	g.events = append(g.events, Event{
+		Time:      tr.Timestamp,
+		Type:      Disposal,
+		Asset:     asset,
+		Symbol:    tr.Symbol,
+		Quantity:  qty,
+		Value:     proceeds,
+		CostBasis: basis,
+		Gain:      proceeds - basis,
+		Fee:       fee,
+		Unmatched: remaining,
+	})
+}
+
+func parse(s t.StringOrNumber, field string, tr t.UserTrade) (float64, error) {
+	v, err := strconv.ParseFloat(s.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("report: invalid %s %q in %s trade at %s: %w", field, s, tr.Symbol, tr.Timestamp.Format(time.RFC3339), err)
+	}
+	return v, nil
+}
+
+// knownQuotes lists Wallex quote assets, longest first.
+var knownQuotes = []string{"USDT", "TMN"}
+
+// SplitSymbol splits a Wallex symbol into base and quote assets, using
+// markets when it contains the symbol and the known quote assets otherwise.
+func SplitSymbol(symbol string, markets map[string]t.SymbolInfo) (string, string, error) {
+	if info, ok := markets[symbol]; ok && info.BaseAsset != "" && info.QuoteAsset != "" {
+		return info.BaseAsset, info.QuoteAsset, nil
+	}
+	for _, q := range knownQuotes {
+		if strings.HasSuffix(symbol, q) && len(symbol) > len(q) {
+			return strings.TrimSuffix(symbol, q), q, nil
+		}
+	}
+	return "", "", fmt.Errorf("report: cannot determine base/quote of symbol %q", symbol)
+}
+
+// WriteCSV writes the ledger events as CSV with a header row.
+func (r *TaxReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"time", "type", "asset", "symbol", "quantity",
+		"value_" + strings.ToLower(r.Currency), "cost_basis", "gain", "fee", "unmatched_qty",
+	})
+	for _, e := range r.Events {
+		_ = cw.Write([]string{
+			e.Time.UTC().Format(time.RFC3339),
+			string(e.Type),
+			e.Asset,
+			e.Symbol,
+			formatFloat(e.Quantity),
+			formatFloat(e.Value),
+			formatFloat(e.CostBasis),
+			formatFloat(e.Gain),
+			formatFloat(e.Fee),
+			formatFloat(e.Unmatched),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteSummaryCSV writes the per-asset summaries as CSV with a header row.
+func (r *TaxReport) WriteSummaryCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{
+		"asset", "acquired_qty", "acquired_cost", "disposed_qty", "proceeds",
+		"cost_basis", "realized_gain", "fees", "remaining_qty", "remaining_cost",
+	})
+	for _, s := range r.Assets {
+		_ = cw.Write([]string{
+			s.Asset,
+			formatFloat(s.AcquiredQty),
+			formatFloat(s.AcquiredCost),
+			formatFloat(s.DisposedQty),
+			formatFloat(s.Proceeds),
+			formatFloat(s.CostBasis),
+			formatFloat(s.RealizedGain),
+			formatFloat(s.Fees),
+			formatFloat(s.RemainingQty),
+			formatFloat(s.RemainingCost),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
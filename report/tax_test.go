@@ -0,0 +1,185 @@
+package report
+
+import (
+	"math"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+func userTrade(at time.Time, buy bool, qty, price, fee float64, feeAsset string) types.UserTrade {
+	format := func(v float64) types.StringOrNumber {
+		return types.StringOrNumber(strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	return types.UserTrade{
+		Symbol:    "BTCTMN",
+		Quantity:  format(qty),
+		Price:     format(price),
+		Fee:       format(fee),
+		FeeAsset:  feeAsset,
+		IsBuyer:   buy,
+		Timestamp: at,
+	}
+}
+
+// btcRate values BTC fees at a fixed 100 TMN.
+func btcRate(asset string, _ time.Time) (float64, error) {
+	return 100, nil
+}
+
+func TestGenerateTaxReportFIFO(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	tehran := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, u.Tehran)
+	}
+
+	cases := []struct {
+		name      string
+		trades    []types.UserTrade
+		opts      Options
+		want      AssetSummary
+		unmatched float64
+	}{
+		{
+			// The sale consumes the first lot, net of its BTC fee, and
+			// half of the second, whose TMN fee adds to its cost; the
+			// sale's TMN fee reduces the proceeds.
+			name: "partial lot with fees",
+			trades: []types.UserTrade{
+				userTrade(day(3), false, 1.5, 300, 4.5, "TMN"),
+				userTrade(day(1), true, 1, 100, 0.01, "BTC"),
+				userTrade(day(2), true, 1, 200, 2, "TMN"),
+			},
+			want: AssetSummary{
+				AcquiredQty:   1.99,
+				AcquiredCost:  302,
+				DisposedQty:   1.5,
+				Proceeds:      445.5,
+				CostBasis:     100 + 0.51*202,
+				RealizedGain:  445.5 - (100 + 0.51*202),
+				Fees:          1 + 2 + 4.5,
+				RemainingQty:  0.49,
+				RemainingCost: 0.49 * 202,
+			},
+		},
+		{
+			name: "sale beyond the lots",
+			trades: []types.UserTrade{
+				userTrade(day(1), true, 1, 100, 0, "TMN"),
+				userTrade(day(2), false, 1.5, 300, 0, "TMN"),
+			},
+			want: AssetSummary{
+				AcquiredQty:  1,
+				AcquiredCost: 100,
+				DisposedQty:  1.5,
+				Proceeds:     450,
+				CostBasis:    100,
+				RealizedGain: 350,
+			},
+			unmatched: 0.5,
+		},
+		{
+			// Lots bought before the period still back sales within it.
+			name: "lot bought before the period",
+			trades: []types.UserTrade{
+				userTrade(day(1), true, 2, 100, 0, "TMN"),
+				userTrade(day(5), false, 1, 300, 3, "TMN"),
+				userTrade(day(9), false, 1, 400, 0, "TMN"),
+			},
+			opts: Options{From: day(4), To: day(6)},
+			want: AssetSummary{
+				DisposedQty:   1,
+				Proceeds:      297,
+				CostBasis:     100,
+				RealizedGain:  197,
+				Fees:          3,
+				RemainingQty:  1,
+				RemainingCost: 100,
+			},
+		},
+		{
+			// 1403 is a leap year: its last day, 30 Esfand, is 20 March
+			// 2025 and lies outside the fiscal year 1404, which starts at
+			// midnight on 21 March in Tehran and ends before Nowruz 1405.
+			name: "Jalali fiscal year",
+			trades: []types.UserTrade{
+				userTrade(tehran(2025, time.March, 20, 23, 30), true, 2, 100, 0, "TMN"),
+				userTrade(tehran(2025, time.March, 21, 0, 30), false, 1, 300, 0, "TMN"),
+				userTrade(tehran(2026, time.March, 20, 23, 30), false, 0.5, 400, 0, "TMN"),
+				userTrade(tehran(2026, time.March, 21, 0, 30), false, 0.5, 500, 0, "TMN"),
+			},
+			opts: Options{
+				JalaliFrom: u.JalaliDate{Year: 1404, Month: 1, Day: 1},
+				JalaliTo:   u.JalaliDate{Year: 1404, Month: 12, Day: 29},
+			},
+			want: AssetSummary{
+				DisposedQty:   1.5,
+				Proceeds:      500,
+				CostBasis:     150,
+				RealizedGain:  350,
+				RemainingQty:  0.5,
+				RemainingCost: 50,
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := tc.opts
+			opts.Rate = btcRate
+			rep, err := GenerateTaxReport(tc.trades, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var got *AssetSummary
+			for i := range rep.Assets {
+				if rep.Assets[i].Asset == "BTC" {
+					got = &rep.Assets[i]
+				}
+			}
+			if got == nil {
+				t.Fatalf("no BTC summary in %+v", rep.Assets)
+			}
+			want := tc.want
+			want.Asset = "BTC"
+			for _, f := range []struct {
+				name      string
+				got, want float64
+			}{
+				{"AcquiredQty", got.AcquiredQty, want.AcquiredQty},
+				{"AcquiredCost", got.AcquiredCost, want.AcquiredCost},
+				{"DisposedQty", got.DisposedQty, want.DisposedQty},
+				{"Proceeds", got.Proceeds, want.Proceeds},
+				{"CostBasis", got.CostBasis, want.CostBasis},
+				{"RealizedGain", got.RealizedGain, want.RealizedGain},
+				{"Fees", got.Fees, want.Fees},
+				{"RemainingQty", got.RemainingQty, want.RemainingQty},
+				{"RemainingCost", got.RemainingCost, want.RemainingCost},
+			} {
+				if math.Abs(f.got-f.want) > 1e-9 {
+					t.Errorf("%s = %g, want %g", f.name, f.got, f.want)
+				}
+			}
+
+			var unmatched float64
+			for _, e := range rep.Events {
+				unmatched += e.Unmatched
+			}
+			if math.Abs(unmatched-tc.unmatched) > 1e-9 {
+				t.Errorf("unmatched = %g, want %g", unmatched, tc.unmatched)
+			}
+		})
+	}
+}
+
+func TestGenerateTaxReportInvalidQuantity(t *testing.T) {
+	tr := userTrade(time.Now(), true, 1, 100, 0, "TMN")
+	tr.Quantity = "-"
+	if _, err := GenerateTaxReport([]types.UserTrade{tr}, Options{}); err == nil {
+		t.Fatal("a trade without quantity was accepted")
+	}
+}
@@ -0,0 +1,84 @@
+package wallex
+
+import (
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+// RequestIDHeader is the header carrying the correlation ID of a request.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx whose requests carry id as their
+// correlation ID instead of a generated one, so an application can reuse
+// its own trace or job identifier.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the correlation ID set with WithRequestID.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// requestID returns the correlation ID for a request made with ctx. All
+// attempts of a retried request share the same ID.
+func requestID(ctx context.Context) string {
+	if id, ok := RequestIDFromContext(ctx); ok {
+		return id
+	}
+	var b [8]byte
+	v := rand.Uint64()
+	for i := range b {
+		b[i] = byte(v >> (8 * i))
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// annotateError records the correlation ID and endpoint on the SDK error
+// types that carry them.
+func annotateError(err error, id, endpoint string) error {
+	switch e := err.(type) {
+	case *RequestError:
+		e.RequestID = id
+		e.Endpoint = endpoint
+	case *APIError:
+		e.RequestID = id
+		e.Endpoint = endpoint
+	case *MaintenanceError:
+		e.RequestID = id
+		e.Endpoint = endpoint
+	}
+	return err
+}
+
+// logAttempt reports a finished request attempt to the client Logger.
+// Successful attempts are logged at debug level, failures at warn.
+func (c *Client) logAttempt(ctx context.Context, method, url, id string, attempt int, took time.Duration, err error) {
+	if c.Logger == nil {
+		return
+	}
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+	}
+	if !c.Logger.Enabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("request_id", id),
+		slog.String("method", method),
+		slog.String("endpoint", c.endpointKey(method, url)),
+		slog.Int("attempt", attempt),
+		slog.Duration("took", took),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.Logger.LogAttrs(ctx, level, "wallex request", attrs...)
+}
@@ -0,0 +1,97 @@
+package wallex
+
+import (
+	"context"
+
+	"github.com/darhelm/go-wallex/risk"
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// PreTradeCheck validates an order before CreateOrder sends it. A non-nil
+// error aborts the order without contacting Wallex. *risk.Checker
+// implements it.
+type PreTradeCheck interface {
+	Check(ctx context.Context, params t.CreateOrderParams) error
+}
+
+// RiskExposure returns a risk.Exposure backed by this client. Each check
+// queries Wallex: open orders via GET /v1/account/openOrders, positions via
+// GET /v1/account/balances and MARKET prices via GET /v1/depth.
+func (c *Client) RiskExposure() risk.Exposure {
+	return clientExposure{c: c}
+}
+
+// RiskExposureWithPrices is RiskExposure valuing orders without a price at
+// the price of prices, e.g. c.PriceSource(PriceMark), instead of the best
+// opposite price in the book.
+func (c *Client) RiskExposureWithPrices(prices PriceSource) risk.Exposure {
+	return clientExposure{c: c, prices: prices}
+}
+
+type clientExposure struct {
+	c      *Client
+	prices PriceSource
+}
+
+func (e clientExposure) OpenOrderCount(ctx context.Context, symbol string) (int, error) {
+	open, err := e.c.getOpenOrders(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	return len(open.Result.Orders), nil
+}
+
+func (e clientExposure) Position(ctx context.Context, symbol string) (float64, error) {
+	base, err := e.baseAsset(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	wallets, err := e.c.getWallets(ctx)
+	if err != nil {
+		return 0, err
+	}
+	balance, _ := wallets.Get(base)
+	return balance.Total(), nil
+}
+
+// baseAsset looks up the base asset of symbol, using the symbol cache when
+// ValidateSymbols is enabled.
+func (e clientExposure) baseAsset(ctx context.Context, symbol string) (string, error) {
+	if e.c.symbols != nil {
+		info, err := e.c.symbols.Info(ctx, symbol)
+		if err != nil {
+			return "", err
+		}
+		return info.BaseAsset, nil
+	}
+	markets, err := e.c.getMarketsInfo(ctx)
+	if err != nil {
+		return "", err
+	}
+	info, ok := markets.Get(symbol)
+	if !ok {
+		return "", &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "unknown symbol " + symbol, Err: nil},
+			Symbol:        symbol,
+		}
+	}
+	return info.BaseAsset, nil
+}
+
+func (e clientExposure) MarketPrice(ctx context.Context, symbol, side string) (float64, error) {
+	if e.prices != nil {
+		return e.prices.Price(ctx, symbol)
+	}
+	depth, err := e.c.getOrderBook(ctx, symbol)
+	if err != nil {
+		return 0, err
+	}
+	levels := depth.Result.Ask
+	if side == t.SideSell {
+		levels = depth.Result.Bid
+	}
+	if len(levels) == 0 {
+		return 0, &GoWallexError{Message: "order book of " + symbol + " is empty", Err: nil}
+	}
+	return levels[0].Price, nil
+}
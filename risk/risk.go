@@ -0,0 +1,230 @@
+// Package risk implements local pre-trade risk limits.
+//
+// A Checker validates orders against configured Limits before they are sent
+// to Wallex. Plug it into a client so every CreateOrder is checked:
+//
+//	client, _ := wallex.NewClient(wallex.ClientOptions{ApiKey: key})
+//	checker := risk.New(risk.Limits{
+//	    MaxOrderNotional: 5_000,
+//	    MaxOpenOrders:    10,
+//	    MaxPosition:      map[string]float64{"BTCUSDT": 0.5},
+//	}, client.RiskExposure())
+//	trading := client.With(wallex.WithPreTrade(checker))
+//
+// Violations are rejected locally with a *RiskError. The kill switch
+// (Kill/Resume) blocks all new orders until it is released.
+package risk
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Rule identifies the limit an order violated.
+type Rule string
+
+const (
+	RuleKillSwitch       Rule = "kill_switch"
+	RuleMaxOrderNotional Rule = "max_order_notional"
+	RuleMaxOpenOrders    Rule = "max_open_orders"
+	RuleMaxPosition      Rule = "max_position"
+	RuleInvalidOrder     Rule = "invalid_order"
+)
+
+// RiskError is returned when an order is rejected by a Checker. Limit and
+// Value are the configured limit and the value the order would have
+// reached; both are zero for the kill switch.
+type RiskError struct {
+	Rule   Rule
+	Symbol string
+	Limit  float64
+	Value  float64
+	Reason string
+}
+
+func (e *RiskError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("risk: %s rejected order on %s: %s", e.Rule, e.Symbol, e.Reason)
+	}
+	return fmt.Sprintf("risk: %s rejected order on %s: %g exceeds limit %g", e.Rule, e.Symbol, e.Value, e.Limit)
+}
+
+// Limits configures a Checker. Zero values disable the corresponding check.
+type Limits struct {
+	// MaxOrderNotional caps price×quantity of a single order, in the quote
+	// asset of its market.
+	MaxOrderNotional float64
+
+	// MaxOrderNotionalBySymbol overrides MaxOrderNotional per symbol.
+	MaxOrderNotionalBySymbol map[string]float64
+
+	// MaxOpenOrders caps the number of open orders per symbol, including
+	// the order being checked.
+	MaxOpenOrders int
+
+	// MaxPosition caps the base-asset holdings a BUY may reach, per symbol.
+	// SELL orders reduce the position and are never rejected by this rule.
+	MaxPosition map[string]float64
+}
+
+func (l Limits) orderNotional(symbol string) float64 {
+	if v, ok := l.MaxOrderNotionalBySymbol[symbol]; ok {
+		return v
+	}
+	return l.MaxOrderNotional
+}
+
+// Exposure supplies the account state the checks need. The client provides
+// one through Client.RiskExposure; strategies that already track their
+// orders and balances can implement it to avoid extra requests.
+type Exposure interface {
+	// OpenOrderCount returns the number of open orders on symbol.
+	OpenOrderCount(ctx context.Context, symbol string) (int, error)
+
+	// Position returns the holdings of symbol's base asset.
+	Position(ctx context.Context, symbol string) (float64, error)
+
+	// MarketPrice returns the price a MARKET order on side would execute
+	// at, used to value orders that carry no price.
+	MarketPrice(ctx context.Context, symbol, side string) (float64, error)
+}
+
+// Checker validates orders against Limits. It is safe for concurrent use.
+type Checker struct {
+	mu         sync.RWMutex
+	limits     Limits
+	exposure   Exposure
+	killed     bool
+	killReason string
+}
+
+// New returns a Checker enforcing limits. exposure may be nil when only
+// MaxOrderNotional for LIMIT orders and the kill switch are used.
+func New(limits Limits, exposure Exposure) *Checker {
+	return &Checker{limits: limits, exposure: exposure}
+}
+
+// SetLimits replaces the limits. Orders already checked are not affected.
+func (c *Checker) SetLimits(limits Limits) {
+	c.mu.Lock()
+	c.limits = limits
+	c.mu.Unlock()
+}
+
+// Limits returns the current limits.
+func (c *Checker) Limits() Limits {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.limits
+}
+
+// Kill engages the kill switch: every order is rejected with reason until
+// Resume is called.
+func (c *Checker) Kill(reason string) {
+	c.mu.Lock()
+	c.killed = true
+	c.killReason = reason
+	c.mu.Unlock()
+}
+
+// Resume releases the kill switch.
+func (c *Checker) Resume() {
+	c.mu.Lock()
+	c.killed = false
+	c.killReason = ""
+	c.mu.Unlock()
+}
+
+// Killed reports whether the kill switch is engaged, and why.
+func (c *Checker) Killed() (bool, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.killed, c.killReason
+}
+
+// Check validates params against the limits. It returns a *RiskError for
+// violations and the Exposure's error if account state is unavailable.
+func (c *Checker) Check(ctx context.Context, params t.CreateOrderParams) error {
+	c.mu.RLock()
+	limits := c.limits
+	killed, reason := c.killed, c.killReason
+	c.mu.RUnlock()
+
+	if killed {
+		if reason == "" {
+			reason = "kill switch engaged"
+		}
+		return &RiskError{Rule: RuleKillSwitch, Symbol: params.Symbol, Reason: reason}
+	}
+
+	qty, err := strconv.ParseFloat(params.Quantity, 64)
+	if err != nil || qty <= 0 {
+		return &RiskError{Rule: RuleInvalidOrder, Symbol: params.Symbol, Reason: fmt.Sprintf("invalid quantity %q", params.Quantity)}
+	}
+
+	if limit := limits.orderNotional(params.Symbol); limit > 0 {
+		price, err := c.orderPrice(ctx, params)
+		if err != nil {
+			return err
+		}
+		if notional := price * qty; notional > limit {
+			return &RiskError{Rule: RuleMaxOrderNotional, Symbol: params.Symbol, Limit: limit, Value: notional}
+		}
+	}
+
+	if limits.MaxOpenOrders > 0 {
+		if c.exposure == nil {
+			return errNoExposure(RuleMaxOpenOrders)
+		}
+		n, err := c.exposure.OpenOrderCount(ctx, params.Symbol)
+		if err != nil {
+			return fmt.Errorf("risk: open orders of %s: %w", params.Symbol, err)
+		}
+		if n+1 > limits.MaxOpenOrders {
+			return &RiskError{Rule: RuleMaxOpenOrders, Symbol: params.Symbol, Limit: float64(limits.MaxOpenOrders), Value: float64(n + 1)}
+		}
+	}
+
+	if limit, ok := limits.MaxPosition[params.Symbol]; ok && params.Side == t.SideBuy {
+		if c.exposure == nil {
+			return errNoExposure(RuleMaxPosition)
+		}
+		pos, err := c.exposure.Position(ctx, params.Symbol)
+		if err != nil {
+			return fmt.Errorf("risk: position of %s: %w", params.Symbol, err)
+		}
+		if pos+qty > limit {
+			return &RiskError{Rule: RuleMaxPosition, Symbol: params.Symbol, Limit: limit, Value: pos + qty}
+		}
+	}
+
+	return nil
+}
+
+// orderPrice returns the LIMIT price, or the market price for orders
+// without one.
+func (c *Checker) orderPrice(ctx context.Context, params t.CreateOrderParams) (float64, error) {
+	if params.Price != "" {
+		price, err := strconv.ParseFloat(params.Price, 64)
+		if err != nil || price <= 0 {
+			return 0, &RiskError{Rule: RuleInvalidOrder, Symbol: params.Symbol, Reason: fmt.Sprintf("invalid price %q", params.Price)}
+		}
+		return price, nil
+	}
+	if c.exposure == nil {
+		return 0, errNoExposure(RuleMaxOrderNotional)
+	}
+	price, err := c.exposure.MarketPrice(ctx, params.Symbol, params.Side)
+	if err != nil {
+		return 0, fmt.Errorf("risk: market price of %s: %w", params.Symbol, err)
+	}
+	return price, nil
+}
+
+func errNoExposure(rule Rule) error {
+	return fmt.Errorf("risk: %s requires an Exposure", rule)
+}
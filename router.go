@@ -0,0 +1,300 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Defaults of ConversionRouterOptions.
+const (
+	DefaultConversionSlippageBps = 50
+	DefaultLegTimeout            = 30 * time.Second
+	DefaultLegPollInterval       = 500 * time.Millisecond
+)
+
+// ConversionRouterOptions configures a ConversionRouter.
+type ConversionRouterOptions struct {
+	// MaxSlippageBps bounds every leg as ExecuteMarketWithLimit does.
+	// Defaults to DefaultConversionSlippageBps.
+	MaxSlippageBps float64
+
+	// FeeRate is the fee rate reserved when a leg buys with a quote
+	// amount, as in AllocateBudget.
+	FeeRate float64
+
+	// LegTimeout is how long a leg may take to fill, which bounds the
+	// time the intermediate asset is held. The unfilled rest of a leg is
+	// canceled. Defaults to DefaultLegTimeout.
+	LegTimeout time.Duration
+
+	// PollInterval is how often a leg's order is polled. Defaults to
+	// DefaultLegPollInterval.
+	PollInterval time.Duration
+
+	// PriceTTL is how long routes and prices are reused, see NewConverter.
+	PriceTTL time.Duration
+}
+
+// ConversionLeg is one executed hop of a conversion.
+type ConversionLeg struct {
+	Step ConversionStep
+
+	// Order is the final snapshot of the leg's order.
+	Order *t.BaseOrder
+
+	// Spent is the amount of Step.From sold and Received the amount of
+	// Step.To bought, net of fees.
+	Spent    float64
+	Received float64
+
+	// TimedOut is set when the order did not fill within LegTimeout and
+	// its rest was canceled.
+	TimedOut bool
+}
+
+// ConversionExposure is an intermediate asset left over by a conversion
+// whose later leg failed or filled partially. Converting it back, or
+// onwards, is up to the caller.
+type ConversionExposure struct {
+	Asset  string
+	Amount float64
+}
+
+// ConversionResult is the outcome of ConversionRouter.Convert.
+type ConversionResult struct {
+	From   string
+	To     string
+	Amount float64
+	Route  *ConversionRoute
+
+	// Legs are the legs executed, in order, including a failed one that
+	// placed an order.
+	Legs []ConversionLeg
+
+	// Received is the amount of To received.
+	Received float64
+
+	// Exposure is set when the conversion stopped with an intermediate
+	// asset held.
+	Exposure *ConversionExposure
+}
+
+// ConversionError is returned by ConversionRouter.Convert when a leg
+// fails. Result holds the legs executed so far and the exposure left.
+type ConversionError struct {
+	GoWallexError
+
+	// Leg is the index of the failed leg.
+	Leg    int
+	Result *ConversionResult
+}
+
+// ConversionRouter executes asset-to-asset conversions as market orders
+// along the routes of a Converter, e.g. DOGE→TMN→USDT when no DOGEUSDT
+// market exists.
+//
+// Legs are executed one after the other. Before each leg the available
+// balance of the asset to sell is checked, and a leg never sells more than
+// the previous one received. Each leg is a protective LIMIT order, see
+// ExecuteMarketWithLimit, given LegTimeout to fill. When a later leg fails,
+// the intermediate asset held is reported in the ConversionError so the
+// caller can roll back.
+//
+// ConversionRouter is safe for concurrent use.
+type ConversionRouter struct {
+	client    *Client
+	converter *Converter
+	opts      ConversionRouterOptions
+}
+
+// NewConversionRouter returns a ConversionRouter trading through c.
+func NewConversionRouter(c *Client, opts ConversionRouterOptions) *ConversionRouter {
+	if opts.MaxSlippageBps <= 0 {
+		opts.MaxSlippageBps = DefaultConversionSlippageBps
+	}
+	if opts.LegTimeout <= 0 {
+		opts.LegTimeout = DefaultLegTimeout
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = DefaultLegPollInterval
+	}
+	return &ConversionRouter{
+		client:    c,
+		converter: NewConverter(c, opts.PriceTTL),
+		opts:      opts,
+	}
+}
+
+// Convert converts amount of from into to, e.g.
+// Convert(ctx, 1000, "DOGE", "USDT").
+//
+// Authentication: REQUIRED.
+func (r *ConversionRouter) Convert(ctx context.Context, amount float64, from, to string) (*ConversionResult, error) {
+	if amount <= 0 || from == to {
+		return nil, &GoWallexError{
+			Message: "conversion amount must be positive and between different assets",
+			Err:     nil,
+		}
+	}
+
+	route, markets, err := r.converter.Route(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+	res := &ConversionResult{From: from, To: to, Amount: amount, Route: route}
+
+	for i, step := range route.Steps {
+		leg, err := r.leg(ctx, markets, step, amount)
+		if leg != nil {
+			res.Legs = append(res.Legs, *leg)
+			amount = leg.Received
+		}
+		if err == nil && amount <= 0 {
+			err = &GoWallexError{Message: "leg received nothing", Err: nil}
+		}
+		if err != nil {
+			res.Exposure = intermediateExposure(res)
+			return res, &ConversionError{
+				GoWallexError: GoWallexError{
+					Message: "leg " + strconv.Itoa(i+1) + " (" + step.Symbol + ") of " + routeString(route) + " failed",
+					Err:     err,
+				},
+				Leg:    i,
+				Result: res,
+			}
+		}
+	}
+	res.Received = amount
+	res.Exposure = intermediateExposure(res)
+	return res, nil
+}
+
+// intermediateExposure returns what the legs of res left of the
+// intermediate asset of a two-hop route: the part of the first leg's
+// proceeds the second leg did not sell, because it failed, filled partially
+// or left a remainder of its budget.
+func intermediateExposure(res *ConversionResult) *ConversionExposure {
+	if len(res.Route.Steps) < 2 || len(res.Legs) == 0 {
+		return nil
+	}
+	asset := res.Route.Steps[0].To
+	amount := res.Legs[0].Received
+	if len(res.Legs) > 1 {
+		amount -= res.Legs[1].Spent
+	}
+	if amount <= 0 {
+		return nil
+	}
+	return &ConversionExposure{Asset: asset, Amount: amount}
+}
+
+// leg sells amount of step.From along step. The leg is nil when no order
+// was placed.
+func (r *ConversionRouter) leg(ctx context.Context, markets *t.MarketInformation, step ConversionStep, amount float64) (*ConversionLeg, error) {
+	wallets, err := r.client.getWallets(ctx)
+	if err != nil {
+		return nil, err
+	}
+	balance, _ := wallets.Get(step.From)
+	if avail := balance.Available(); avail < amount {
+		if avail <= 0 {
+			return nil, &GoWallexError{Message: "no " + step.From + " available", Err: CodeInsufficientBalance}
+		}
+		amount = avail
+	}
+
+	info, ok := markets.Get(step.Symbol)
+	if !ok {
+		return nil, &UnknownSymbolError{
+			GoWallexError: GoWallexError{Message: "unknown symbol " + step.Symbol, Err: nil},
+			Symbol:        step.Symbol,
+		}
+	}
+
+	side, qty := t.SideSell, amount
+	if step.Inverted {
+		// Buying the base asset with a quote amount: size the order so
+		// that it fits at the protective price, the most it may cost.
+		side = t.SideBuy
+		depth, err := r.client.getOrderBook(ctx, step.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		limit := protectivePrice(depth.Result.Ask, side, r.opts.MaxSlippageBps, int(info.TickSize))
+		if limit <= 0 {
+			return nil, &GoWallexError{Message: "no asks in " + step.Symbol, Err: nil}
+		}
+		alloc, err := AllocateBudget(info, amount, limit, r.opts.FeeRate)
+		if err != nil {
+			return nil, err
+		}
+		qty = alloc.Quantity
+	}
+
+	resp, err := r.client.ExecuteMarketWithLimit(ctx, step.Symbol, side, qty, r.opts.MaxSlippageBps)
+	if err != nil {
+		return nil, err
+	}
+	order, timedOut, err := r.await(ctx, resp.Result)
+
+	leg := &ConversionLeg{Step: step, Order: &order, TimedOut: timedOut}
+	st := ComputeFillStats(order)
+	if step.Inverted {
+		leg.Spent = st.Notional + st.Fees[step.From]
+		leg.Received = st.Quantity - st.Fees[step.To]
+	} else {
+		leg.Spent = st.Quantity + st.Fees[step.From]
+		leg.Received = st.Notional - st.Fees[step.To]
+	}
+	return leg, err
+}
+
+// await polls order until it is closed or LegTimeout passed, then cancels
+// the rest and returns the final snapshot.
+func (r *ConversionRouter) await(ctx context.Context, order t.BaseOrder) (t.BaseOrder, bool, error) {
+	deadline := time.NewTimer(r.opts.LegTimeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+
+	id := order.ClientOrderId
+	for !IsTerminalStatus(order.Status) {
+		select {
+		case <-ctx.Done():
+			return order, false, ctx.Err()
+		case <-deadline.C:
+			if _, err := r.client.cancelOrder(ctx, id); err != nil {
+				var apiErr *APIError
+				if !errors.As(err, &apiErr) || apiErr.StatusCode >= 500 || apiErr.StatusCode == 429 {
+					return order, true, err
+				}
+			}
+			resp, err := r.client.getOrderStatus(ctx, id)
+			if err != nil {
+				return order, true, err
+			}
+			return resp.Result, true, nil
+		case <-ticker.C:
+			resp, err := r.client.getOrderStatus(ctx, id)
+			if err != nil {
+				continue
+			}
+			order = resp.Result
+		}
+	}
+	return order, false, nil
+}
+
+// routeString formats a route as its assets, e.g. "DOGE→TMN→USDT".
+func routeString(route *ConversionRoute) string {
+	assets := []string{route.From}
+	for _, s := range route.Steps {
+		assets = append(assets, s.To)
+	}
+	return strings.Join(assets, "→")
+}
@@ -0,0 +1,328 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// ScheduledOrder is an order to be placed at a future time, once or on a
+// recurring schedule.
+type ScheduledOrder struct {
+	// ID identifies the schedule. Assigned by OrderScheduler.Schedule when
+	// empty.
+	ID string `json:"id"`
+
+	// Params is the order to place. When ClientOrderId is set, the run
+	// number is appended to it, "<clientOrderId>-<run>", so that every run
+	// places a distinct order; when empty, "<schedule id>-<run>" is used.
+	Params t.CreateOrderParams `json:"params"`
+
+	// At is when the order is next due.
+	At time.Time `json:"at"`
+
+	// Every repeats the order at this interval; zero places it once.
+	// Until, when set, ends the recurrence.
+	Every time.Duration `json:"every,omitempty"`
+	Until time.Time     `json:"until,omitzero"`
+
+	// Runs counts the runs so far. LastRun is the time of the last one,
+	// LastOrderId the client order id it used, and LastError the error
+	// it failed with, if any.
+	Runs        int       `json:"runs"`
+	LastRun     time.Time `json:"lastRun,omitzero"`
+	LastOrderId string    `json:"lastOrderId,omitempty"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// ScheduleStore persists scheduled orders so that pending schedules survive
+// process restarts. Implementations must be safe for concurrent use.
+type ScheduleStore interface {
+	// SaveSchedule inserts or replaces the schedule keyed by its ID.
+	SaveSchedule(ctx context.Context, s ScheduledOrder) error
+
+	// LoadSchedules returns all stored schedules.
+	LoadSchedules(ctx context.Context) ([]ScheduledOrder, error)
+
+	// DeleteSchedule removes a schedule. Deleting an unknown schedule is
+	// not an error.
+	DeleteSchedule(ctx context.Context, id string) error
+}
+
+// DefaultScheduleInterval is how often an OrderScheduler checks for due
+// orders when OrderSchedulerOptions.Interval is zero.
+const DefaultScheduleInterval = time.Second
+
+// OrderSchedulerOptions configures an OrderScheduler.
+type OrderSchedulerOptions struct {
+	// Store persists the schedules. Defaults to an in-memory store, which
+	// loses them on exit.
+	Store ScheduleStore
+
+	// Tracker, when set, tracks every placed order.
+	Tracker *OrderTracker
+
+	// MaxLateness skips runs that are due for longer than this, e.g.
+	// after the process was down, instead of placing them late. Zero
+	// places late runs.
+	MaxLateness time.Duration
+
+	// Interval is how often Run checks for due orders. Defaults to
+	// DefaultScheduleInterval.
+	Interval time.Duration
+
+	// OnRun is called after every run with the updated schedule and the
+	// placed order, or the error.
+	OnRun func(s ScheduledOrder, order *t.BaseOrder, err error)
+}
+
+// OrderScheduler places orders at scheduled times (good-after-time
+// orders), once or repeatedly, e.g. a weekly DCA buy.
+//
+// Schedules are written to the Store before each run is placed, so a crash
+// during a run never places it twice after a restart; the run is lost
+// instead. Runs are placed through EnsureOrder, so a lost response does not
+// lose track of an order that was created: an order whose creation cannot
+// be confirmed fails the run with an *AmbiguousOrderError, and LastOrderId
+// identifies it for reconciliation.
+//
+// OrderScheduler implements Closer and is safe for concurrent use.
+type OrderScheduler struct {
+	c    *Client
+	opts OrderSchedulerOptions
+
+	mu        sync.Mutex
+	schedules map[string]ScheduledOrder
+	seq       uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewOrderScheduler returns a scheduler placing orders through c, with the
+// schedules of opts.Store restored. Call Run to start placing them.
+func NewOrderScheduler(ctx context.Context, c *Client, opts OrderSchedulerOptions) (*OrderScheduler, error) {
+	if opts.Store == nil {
+		opts.Store = NewMemoryStore()
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultScheduleInterval
+	}
+
+	stored, err := opts.Store.LoadSchedules(ctx)
+	if err != nil {
+		return nil, &GoWallexError{
+			Message: "failed to restore scheduled orders",
+			Err:     err,
+		}
+	}
+
+	s := &OrderScheduler{
+		c:         c,
+		opts:      opts,
+		schedules: make(map[string]ScheduledOrder, len(stored)),
+		stop:      make(chan struct{}),
+	}
+	for _, so := range stored {
+		s.schedules[so.ID] = so
+	}
+	return s, nil
+}
+
+// Schedule adds so, or replaces the schedule with the same ID, and returns
+// it with its ID assigned.
+func (s *OrderScheduler) Schedule(ctx context.Context, so ScheduledOrder) (ScheduledOrder, error) {
+	if so.At.IsZero() || so.Every < 0 || so.Params.Symbol == "" || so.Params.Side == "" || so.Params.Type == "" {
+		return so, &GoWallexError{
+			Message: "scheduled order needs a time, a symbol, a side and a type",
+			Err:     nil,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if so.ID == "" {
+		s.seq++
+		so.ID = fmt.Sprintf("sched-%d-%d", time.Now().UnixMilli(), s.seq)
+	}
+	if err := validateClientOrderId(so.ID); err != nil {
+		return so, err
+	}
+	if so.Params.ClientOrderId != "" {
+		if err := validateClientOrderId(so.Params.ClientOrderId); err != nil {
+			return so, err
+		}
+	}
+	if err := s.opts.Store.SaveSchedule(ctx, so); err != nil {
+		return so, &GoWallexError{Message: "failed to save scheduled order", Err: err}
+	}
+	s.schedules[so.ID] = so
+	return so, nil
+}
+
+// List returns the pending schedules, the next due first.
+func (s *OrderScheduler) List() []ScheduledOrder {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]ScheduledOrder, 0, len(s.schedules))
+	for _, so := range s.schedules {
+		out = append(out, so)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].At.Before(out[j].At) })
+	return out
+}
+
+// Get returns the pending schedule with the given ID.
+func (s *OrderScheduler) Get(id string) (ScheduledOrder, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	so, ok := s.schedules[id]
+	return so, ok
+}
+
+// Cancel removes a pending schedule. Orders it already placed are not
+// affected. Canceling an unknown schedule is not an error.
+func (s *OrderScheduler) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.opts.Store.DeleteSchedule(ctx, id); err != nil {
+		return &GoWallexError{Message: "failed to delete scheduled order", Err: err}
+	}
+	delete(s.schedules, id)
+	return nil
+}
+
+// RunDue places every order due at now and advances its schedule; schedules
+// without further runs are removed. Failures of individual runs are
+// recorded in LastError and returned joined.
+func (s *OrderScheduler) RunDue(ctx context.Context, now time.Time) error {
+	var errs []error
+	for _, so := range s.List() {
+		if so.At.After(now) {
+			break
+		}
+		if err := s.run(ctx, so, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run advances so past now, persists it and then places the run, unless it
+// is too late.
+func (s *OrderScheduler) run(ctx context.Context, so ScheduledOrder, now time.Time) error {
+	due := so.At
+	late := s.opts.MaxLateness > 0 && now.Sub(due) > s.opts.MaxLateness
+
+	next := so
+	next.At = time.Time{}
+	if so.Every > 0 {
+		k := now.Sub(due)/so.Every + 1
+		next.At = due.Add(k * so.Every)
+		if !so.Until.IsZero() && next.At.After(so.Until) {
+			next.At = time.Time{}
+		}
+	}
+
+	var params t.CreateOrderParams
+	if !late {
+		next.Runs++
+		next.LastRun = now
+		next.LastError = ""
+		params = so.Params
+		params.ClientOrderId = so.ID
+		if so.Params.ClientOrderId != "" {
+			params.ClientOrderId = so.Params.ClientOrderId
+		}
+		params.ClientOrderId = fmt.Sprintf("%s-%d", params.ClientOrderId, next.Runs)
+		next.LastOrderId = params.ClientOrderId
+	}
+
+	if err := s.save(ctx, next); err != nil {
+		return err
+	}
+	if late {
+		return nil
+	}
+
+	resp, err := s.c.EnsureOrder(ctx, params)
+	var order *t.BaseOrder
+	if err == nil {
+		order = &resp.Result
+		if s.opts.Tracker != nil {
+			s.opts.Tracker.Track(resp.Result)
+		}
+	} else {
+		err = &GoWallexError{Message: "scheduled order " + so.ID + " failed", Err: err}
+		next.LastError = err.Error()
+		if !next.At.IsZero() {
+			_ = s.save(ctx, next)
+		}
+	}
+	if s.opts.OnRun != nil {
+		s.opts.OnRun(next, order, err)
+	}
+	return err
+}
+
+// save stores so, or removes it when it has no next run.
+func (s *OrderScheduler) save(ctx context.Context, so ScheduledOrder) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.schedules[so.ID]; !ok {
+		// Canceled meanwhile.
+		return nil
+	}
+	if so.At.IsZero() {
+		if err := s.opts.Store.DeleteSchedule(ctx, so.ID); err != nil {
+			return &GoWallexError{Message: "failed to delete scheduled order", Err: err}
+		}
+		delete(s.schedules, so.ID)
+		return nil
+	}
+	if err := s.opts.Store.SaveSchedule(ctx, so); err != nil {
+		return &GoWallexError{Message: "failed to save scheduled order", Err: err}
+	}
+	s.schedules[so.ID] = so
+	return nil
+}
+
+// Run calls RunDue every Interval until ctx is done, the scheduler is
+// closed or the client is shut down. Errors are passed to onError when it
+// is non-nil.
+func (s *OrderScheduler) Run(ctx context.Context, onError func(error)) {
+	s.loops.Add(1)
+	defer s.loops.Done()
+
+	ticker := time.NewTicker(s.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-s.c.Done():
+			return
+		case now := <-ticker.C:
+			if err := s.RunDue(ctx, now); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Close implements Closer. It stops Run loops and waits for them to return.
+// Pending schedules are kept in the Store.
+func (s *OrderScheduler) Close(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	return waitGroupDone(ctx, &s.loops)
+}
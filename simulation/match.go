@@ -0,0 +1,141 @@
+package simulation
+
+import (
+	"math"
+	"sort"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// execution is one proposed fill of an order.
+type execution struct {
+	price float64
+	qty   float64
+	maker bool
+}
+
+// matcher decides how orders execute against market data.
+type matcher struct {
+	slippage      float64
+	participation float64
+}
+
+// candle fills an order against one candle. Orders reaching the book since
+// the previous event (fresh) arrive at the open: market orders and limit
+// orders crossing the open execute there as taker. Resting limit orders
+// execute at their price as maker when the candle trades through it.
+func (m matcher) candle(o *order, c t.Candle) []execution {
+	qty := m.cap(o.remaining(), c.Volume)
+	if qty <= 0 {
+		return nil
+	}
+
+	if o.typ == t.OrderTypeMarket {
+		return []execution{{price: m.slip(o.side, c.Open), qty: qty}}
+	}
+	switch o.side {
+	case t.SideBuy:
+		if o.fresh && c.Open <= o.price {
+			return []execution{{price: c.Open, qty: qty}}
+		}
+		if c.Low < o.price || c.Low == o.price && c.High > c.Low {
+			return []execution{{price: o.price, qty: qty, maker: true}}
+		}
+	case t.SideSell:
+		if o.fresh && c.Open >= o.price {
+			return []execution{{price: c.Open, qty: qty}}
+		}
+		if c.High > o.price || c.High == o.price && c.High > c.Low {
+			return []execution{{price: o.price, qty: qty, maker: true}}
+		}
+	}
+	return nil
+}
+
+// trade fills an order against one public trade. A resting limit order
+// executes at its price when a trade prints through it.
+func (m matcher) trade(o *order, price, qty float64) []execution {
+	qty = m.cap(o.remaining(), qty)
+	if qty <= 0 {
+		return nil
+	}
+
+	if o.typ == t.OrderTypeMarket {
+		return []execution{{price: m.slip(o.side, price), qty: qty}}
+	}
+	if o.side == t.SideBuy && price <= o.price || o.side == t.SideSell && price >= o.price {
+		if o.fresh {
+			return []execution{{price: price, qty: qty}}
+		}
+		return []execution{{price: o.price, qty: qty, maker: true}}
+	}
+	return nil
+}
+
+// book fills an order against an order book snapshot. Fresh orders take
+// liquidity level by level from the best price, market orders without
+// limit and limit orders up to their price, as taker. Resting limit orders
+// execute at their price as maker against the opposite liquidity that has
+// moved through it.
+func (m matcher) book(o *order, book t.OrderBook) []execution {
+	levels := book.Ask
+	better := func(a, b float64) bool { return a < b }
+	if o.side == t.SideSell {
+		levels = book.Bid
+		better = func(a, b float64) bool { return a > b }
+	}
+	levels = append([]t.Order(nil), levels...)
+	sort.SliceStable(levels, func(i, j int) bool { return better(levels[i].Price, levels[j].Price) })
+
+	var out []execution
+	remaining := o.remaining()
+	budget := o.locked // bounds what a MARKET buy may spend
+	for _, l := range levels {
+		if remaining <= 0 {
+			break
+		}
+		if o.typ == t.OrderTypeLimit && better(o.price, l.Price) {
+			break
+		}
+		available := float64(l.Quantity)
+		if m.participation > 0 {
+			available *= m.participation
+		}
+		qty := math.Min(remaining, available)
+		if o.typ == t.OrderTypeMarket && o.side == t.SideBuy {
+			qty = math.Min(qty, budget/l.Price)
+			budget -= qty * l.Price
+		}
+		if qty <= 0 {
+			continue
+		}
+		if o.fresh {
+			out = append(out, execution{price: l.Price, qty: qty})
+		} else {
+			out = append(out, execution{price: o.price, qty: qty, maker: true})
+		}
+		remaining -= qty
+	}
+	return out
+}
+
+// cap limits qty to the participation share of the available volume.
+func (m matcher) cap(remaining, volume float64) float64 {
+	if m.participation <= 0 {
+		return remaining
+	}
+	return math.Min(remaining, volume*m.participation)
+}
+
+func (m matcher) slip(side string, price float64) float64 {
+	if side == t.SideBuy {
+		return price * (1 + m.slippage)
+	}
+	return price * (1 - m.slippage)
+}
+
+// onGrid reports whether v has at most decimals decimal places.
+func onGrid(v float64, decimals int) bool {
+	return math.Abs(u.RoundDown(v, decimals)-v) < 1e-9*math.Max(1, math.Abs(v))
+}
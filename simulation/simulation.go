@@ -0,0 +1,561 @@
+// Package simulation implements a simulated Wallex matching engine for
+// paper trading and backtesting.
+//
+// An Exchange holds balances and orders and implements wallex.Broker. It
+// validates orders against the precision rules and minimums of their
+// market, locks funds like Wallex and charges maker/taker fees. Orders
+// execute when market data is fed in through OnCandle, OnTrade or OnBook:
+//
+//	ex := simulation.New(simulation.Config{
+//	    Markets:  map[string]types.SymbolInfo{"BTCUSDT": info},
+//	    Balances: map[string]float64{"USDT": 1_000},
+//	    Latency:  50 * time.Millisecond,
+//	})
+//	depth, _ := client.GetOrderBook("BTCUSDT")
+//	fills := ex.OnBook("BTCUSDT", depth.Result, time.Now())
+//
+// The simulated clock is the time of the latest market data. An Exchange
+// is safe for concurrent use.
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	wallex "github.com/darhelm/go-wallex"
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// Fees is a maker/taker fee schedule, as fractions of the traded amount.
+type Fees struct {
+	Maker float64
+	Taker float64
+}
+
+// DefaultFees is the entry tier of the Wallex spot fee schedule. Accounts
+// with higher 30-day volume pay less; pass their actual rates in Config.
+var DefaultFees = Fees{Maker: 0.002, Taker: 0.0025}
+
+// Config configures an Exchange.
+type Config struct {
+	// Markets holds the rules of every market that may be traded.
+	Markets map[string]t.SymbolInfo
+
+	// Balances is the starting balance per asset.
+	Balances map[string]float64
+
+	// Fees defaults to DefaultFees.
+	Fees *Fees
+
+	// Slippage moves the execution price of market orders filled against
+	// candles or trades against the order, as a fraction of the price.
+	// Fills against a book pay the book's own prices instead.
+	Slippage float64
+
+	// Participation caps each fill at this fraction of the volume of the
+	// candle, trade or book level it executes against. Zero fills the
+	// whole remaining quantity.
+	Participation float64
+
+	// Latency is the simulated round trip to the exchange: orders only
+	// match market data at least Latency after they were placed, and
+	// cancels only take effect Latency after they were requested.
+	Latency time.Duration
+}
+
+// OrderError is returned when an order violates the market rules or the
+// balance, mirroring a rejection by Wallex.
+type OrderError struct {
+	Symbol string
+	Reason string
+}
+
+func (e *OrderError) Error() string {
+	return fmt.Sprintf("simulation: order on %s rejected: %s", e.Symbol, e.Reason)
+}
+
+// Fill is one simulated execution.
+type Fill struct {
+	ClientOrderId string
+	Symbol        string
+	Side          string
+	Price         float64
+	Qty           float64
+
+	// Fee is charged in FeeAsset, the asset received: the base asset for
+	// buys and the quote asset for sells, as on Wallex.
+	Fee      float64
+	FeeAsset string
+
+	Maker bool
+	Time  time.Time
+}
+
+type order struct {
+	id       string
+	symbol   string
+	typ      string
+	side     string
+	price    float64
+	qty      float64
+	filled   float64
+	cost     float64
+	locked   float64
+	status   string
+	fresh    bool
+	placed   time.Time
+	activeAt time.Time
+	cancelAt time.Time
+}
+
+func (o *order) remaining() float64 {
+	return o.qty - o.filled
+}
+
+func (o *order) snapshot() t.BaseOrder {
+	b := t.BaseOrder{
+		Symbol:        o.symbol,
+		Type:          o.typ,
+		Side:          o.side,
+		Price:         formatFloat(o.price),
+		OrigQty:       formatFloat(o.qty),
+		ExecutedQty:   formatFloat(o.filled),
+		ExecutedSum:   formatFloat(o.cost),
+		Status:        o.status,
+		Active:        !wallex.IsTerminalStatus(o.status),
+		ClientOrderId: o.id,
+		CreatedAt:     t.NewWallexTime(o.placed),
+	}
+	if o.filled > 0 {
+		b.ExecutedPrice = formatFloat(o.cost / o.filled)
+		b.ExecutedPercent = o.filled / o.qty * 100
+	}
+	return b
+}
+
+// Exchange is a simulated Wallex account and matching engine.
+type Exchange struct {
+	mu      sync.Mutex
+	cfg     Config
+	fees    Fees
+	matcher matcher
+	now     time.Time
+	last    map[string]float64
+	total   map[string]float64
+	locked  map[string]float64
+	orders  map[string]*order
+	open    []*order
+	fills   []Fill
+	seq     int
+
+	submitted int
+	rejected  int
+}
+
+var _ wallex.Broker = (*Exchange)(nil)
+
+// New creates an Exchange holding cfg.Balances.
+func New(cfg Config) *Exchange {
+	fees := DefaultFees
+	if cfg.Fees != nil {
+		fees = *cfg.Fees
+	}
+	e := &Exchange{
+		cfg:     cfg,
+		fees:    fees,
+		matcher: matcher{slippage: cfg.Slippage, participation: cfg.Participation},
+		last:    make(map[string]float64),
+		total:   make(map[string]float64),
+		locked:  make(map[string]float64),
+		orders:  make(map[string]*order),
+	}
+	for asset, v := range cfg.Balances {
+		e.total[asset] = v
+	}
+	return e
+}
+
+// CreateOrder implements wallex.Broker. Rejected orders return an
+// *OrderError.
+func (e *Exchange) CreateOrder(_ context.Context, p t.CreateOrderParams) (*t.BaseOrder, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.submitted++
+	o, err := e.validate(p)
+	if err != nil {
+		e.rejected++
+		return nil, err
+	}
+	e.orders[o.id] = o
+	e.open = append(e.open, o)
+	snap := o.snapshot()
+	return &snap, nil
+}
+
+func (e *Exchange) validate(p t.CreateOrderParams) (*order, error) {
+	reject := func(format string, args ...interface{}) error {
+		return &OrderError{Symbol: p.Symbol, Reason: fmt.Sprintf(format, args...)}
+	}
+
+	market, ok := e.cfg.Markets[p.Symbol]
+	if !ok {
+		return nil, reject("unknown market")
+	}
+	if p.Side != t.SideBuy && p.Side != t.SideSell {
+		return nil, reject("invalid side %q", p.Side)
+	}
+	if p.Type != t.OrderTypeLimit && p.Type != t.OrderTypeMarket {
+		return nil, reject("invalid type %q", p.Type)
+	}
+	if p.Type == t.OrderTypeMarket && !market.IsMarketTypeEnable {
+		return nil, reject("market orders are disabled")
+	}
+
+	qty, err := strconv.ParseFloat(p.Quantity, 64)
+	if err != nil || qty <= 0 {
+		return nil, reject("invalid quantity %q", p.Quantity)
+	}
+	if !onGrid(qty, int(market.StepSize)) {
+		return nil, reject("quantity %s exceeds %d decimals", p.Quantity, market.StepSize)
+	}
+	if qty < market.MinQty.Float64() {
+		return nil, reject("quantity %s below minimum %g", p.Quantity, market.MinQty)
+	}
+
+	price := e.last[p.Symbol]
+	if p.Type == t.OrderTypeLimit {
+		price, err = strconv.ParseFloat(p.Price, 64)
+		if err != nil || price <= 0 {
+			return nil, reject("invalid price %q", p.Price)
+		}
+		if !onGrid(price, int(market.TickSize)) {
+			return nil, reject("price %s exceeds %d decimals", p.Price, market.TickSize)
+		}
+	} else if price <= 0 {
+		return nil, reject("no market price yet")
+	}
+	if qty*price < float64(market.MinNotional) {
+		return nil, reject("notional %g below minimum %d", qty*price, market.MinNotional)
+	}
+
+	asset, need := market.BaseAsset, qty
+	if p.Side == t.SideBuy {
+		asset, need = market.QuoteAsset, qty*price
+		if p.Type == t.OrderTypeMarket {
+			need *= 1 + e.cfg.Slippage
+		}
+	}
+	if avail := e.total[asset] - e.locked[asset]; avail < need-1e-12 {
+		return nil, reject("insufficient %s balance: need %g, available %g", asset, need, avail)
+	}
+	e.locked[asset] += need
+
+	id := p.ClientOrderId
+	if id == "" || e.orders[id] != nil {
+		e.seq++
+		id = "sim-" + strconv.Itoa(e.seq)
+	}
+	limit := 0.0
+	if p.Type == t.OrderTypeLimit {
+		limit = price
+	}
+	return &order{
+		id:       id,
+		symbol:   p.Symbol,
+		typ:      p.Type,
+		side:     p.Side,
+		price:    limit,
+		qty:      qty,
+		locked:   need,
+		status:   t.OrderStatusNew,
+		fresh:    true,
+		placed:   e.now,
+		activeAt: e.now.Add(e.cfg.Latency),
+	}, nil
+}
+
+// CancelOrder implements wallex.Broker. With Config.Latency set the order
+// may still fill until the cancel takes effect.
+func (e *Exchange) CancelOrder(_ context.Context, clientOrderId string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	o, ok := e.orders[clientOrderId]
+	if !ok || wallex.IsTerminalStatus(o.status) {
+		return &OrderError{Reason: "order " + clientOrderId + " is not open"}
+	}
+	if e.cfg.Latency <= 0 {
+		e.close(o, t.OrderStatusCanceled)
+		return nil
+	}
+	if o.cancelAt.IsZero() {
+		o.cancelAt = e.now.Add(e.cfg.Latency)
+	}
+	return nil
+}
+
+// OpenOrders implements wallex.Broker.
+func (e *Exchange) OpenOrders(_ context.Context, symbol string) ([]t.BaseOrder, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var out []t.BaseOrder
+	for _, o := range e.open {
+		if symbol == "" || o.symbol == symbol {
+			out = append(out, o.snapshot())
+		}
+	}
+	return out, nil
+}
+
+// Balance implements wallex.Broker.
+func (e *Exchange) Balance(_ context.Context, asset string) (t.Balance, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return t.Balance{
+		Asset:  asset,
+		Value:  formatFloat(e.total[asset]),
+		Locked: formatFloat(e.locked[asset]),
+	}, nil
+}
+
+// Order returns the order placed with clientOrderId, including filled and
+// canceled orders.
+func (e *Exchange) Order(clientOrderId string) (t.BaseOrder, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	o, ok := e.orders[clientOrderId]
+	if !ok {
+		return t.BaseOrder{}, false
+	}
+	return o.snapshot(), true
+}
+
+// OnCandle matches the open orders of symbol against a completed candle
+// and returns the resulting fills. The clock advances to the candle's
+// start time.
+func (e *Exchange) OnCandle(symbol string, c t.Candle) []Fill {
+	return e.feed(symbol, c.Time, c.Close, func(o *order) []execution {
+		return e.matcher.candle(o, c)
+	})
+}
+
+// OnTrade matches the open orders of the trade's market against a public
+// trade and returns the resulting fills.
+func (e *Exchange) OnTrade(trade t.Trade) []Fill {
+	price, _ := strconv.ParseFloat(trade.Price.String(), 64)
+	qty, _ := strconv.ParseFloat(trade.Quantity.String(), 64)
+	return e.feed(trade.Symbol, trade.Timestamp.Time, price, func(o *order) []execution {
+		return e.matcher.trade(o, price, qty)
+	})
+}
+
+// OnBook matches the open orders of symbol against an order book snapshot
+// taken at at and returns the resulting fills. The last price becomes the
+// mid price.
+func (e *Exchange) OnBook(symbol string, book t.OrderBook, at time.Time) []Fill {
+	var mid float64
+	if len(book.Ask) > 0 && len(book.Bid) > 0 {
+		mid = (bestPrice(book.Ask, false) + bestPrice(book.Bid, true)) / 2
+	}
+	return e.feed(symbol, at, mid, func(o *order) []execution {
+		return e.matcher.book(o, book)
+	})
+}
+
+func (e *Exchange) feed(symbol string, at time.Time, price float64, match func(*order) []execution) []Fill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if at.After(e.now) {
+		e.now = at
+	}
+	start := len(e.fills)
+	for _, o := range append([]*order(nil), e.open...) {
+		if o.symbol != symbol {
+			continue
+		}
+		if !o.cancelAt.IsZero() && !at.Before(o.cancelAt) {
+			e.close(o, t.OrderStatusCanceled)
+			continue
+		}
+		if at.Before(o.activeAt) {
+			continue
+		}
+		step := int(e.cfg.Markets[symbol].StepSize)
+		for _, x := range match(o) {
+			// Wallex fills whole steps only.
+			if x.qty = u.RoundDown(x.qty, step); x.qty > 0 {
+				e.execute(o, x)
+			}
+		}
+		o.fresh = false
+		if o.typ == t.OrderTypeMarket && !wallex.IsTerminalStatus(o.status) {
+			// Market orders do not rest: the unfilled part expires.
+			e.close(o, t.OrderStatusExpired)
+		}
+	}
+	if price > 0 {
+		e.last[symbol] = price
+	}
+	return append([]Fill(nil), e.fills[start:]...)
+}
+
+func (e *Exchange) execute(o *order, x execution) {
+	market := e.cfg.Markets[o.symbol]
+	rate := e.fees.Taker
+	if x.maker {
+		rate = e.fees.Maker
+	}
+	if market.IsZeroFee {
+		rate = 0
+	}
+
+	notional := x.qty * x.price
+	f := Fill{ClientOrderId: o.id, Symbol: o.symbol, Side: o.side, Price: x.price, Qty: x.qty, Maker: x.maker, Time: e.now}
+	if o.side == t.SideBuy {
+		f.Fee, f.FeeAsset = x.qty*rate, market.BaseAsset
+		release := o.locked * x.qty / o.remaining()
+		o.locked -= release
+		e.locked[market.QuoteAsset] -= release
+		e.total[market.QuoteAsset] -= notional
+		e.total[market.BaseAsset] += x.qty - f.Fee
+	} else {
+		f.Fee, f.FeeAsset = notional*rate, market.QuoteAsset
+		o.locked -= x.qty
+		e.locked[market.BaseAsset] -= x.qty
+		e.total[market.BaseAsset] -= x.qty
+		e.total[market.QuoteAsset] += notional - f.Fee
+	}
+	e.fills = append(e.fills, f)
+
+	o.filled += x.qty
+	o.cost += notional
+	if o.remaining() <= 1e-12 {
+		e.close(o, t.OrderStatusFilled)
+	} else {
+		o.status = t.OrderStatusPartiallyFilled
+	}
+}
+
+// close moves an order to a terminal status and releases its locked funds.
+func (e *Exchange) close(o *order, status string) {
+	market := e.cfg.Markets[o.symbol]
+	asset := market.BaseAsset
+	if o.side == t.SideBuy {
+		asset = market.QuoteAsset
+	}
+	e.locked[asset] -= o.locked
+	o.locked = 0
+	o.status = status
+
+	for i, open := range e.open {
+		if open == o {
+			e.open = append(e.open[:i], e.open[i+1:]...)
+			break
+		}
+	}
+}
+
+// Now returns the simulated clock: the time of the latest market data.
+func (e *Exchange) Now() time.Time {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.now
+}
+
+// LastPrice returns the latest price of symbol, or zero before any market
+// data was fed in.
+func (e *Exchange) LastPrice(symbol string) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.last[symbol]
+}
+
+// Balances returns the balance of every asset, including locked funds.
+func (e *Exchange) Balances() map[string]float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	out := make(map[string]float64, len(e.total))
+	for asset, v := range e.total {
+		out[asset] = v
+	}
+	return out
+}
+
+// Fills returns all fills so far, oldest first.
+func (e *Exchange) Fills() []Fill {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Fill(nil), e.fills...)
+}
+
+// OrderCounts returns how many orders were submitted and how many of them
+// were rejected.
+func (e *Exchange) OrderCounts() (submitted, rejected int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.submitted, e.rejected
+}
+
+// Equity values all balances in quote at the last prices. Assets without a
+// market quoted in quote count as zero.
+func (e *Exchange) Equity(quote string) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	var eq float64
+	for asset, v := range e.total {
+		eq += v * e.price(asset, quote)
+	}
+	return eq
+}
+
+// Price returns the value of one unit of asset in quote at the last
+// prices, or zero if no market links them.
+func (e *Exchange) Price(asset, quote string) float64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.price(asset, quote)
+}
+
+func (e *Exchange) price(asset, quote string) float64 {
+	if asset == quote {
+		return 1
+	}
+	symbols := make([]string, 0, len(e.cfg.Markets))
+	for s := range e.cfg.Markets {
+		symbols = append(symbols, s)
+	}
+	sort.Strings(symbols)
+	for _, s := range symbols {
+		m := e.cfg.Markets[s]
+		if m.BaseAsset == asset && m.QuoteAsset == quote {
+			return e.last[s]
+		}
+	}
+	return 0
+}
+
+func bestPrice(levels []t.Order, highest bool) float64 {
+	best := levels[0].Price
+	for _, l := range levels[1:] {
+		if highest && l.Price > best || !highest && l.Price < best {
+			best = l.Price
+		}
+	}
+	return best
+}
+
+func formatFloat(v float64) t.StringOrNumber {
+	return t.StringOrNumber(strconv.FormatFloat(v, 'f', -1, 64))
+}
@@ -0,0 +1,94 @@
+package wallex
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// StalePolicy selects what happens to market-data responses older than
+// Client.MaxResponseAge.
+type StalePolicy int
+
+const (
+	// StaleReject returns a *StaleResponseError instead of the data.
+	StaleReject StalePolicy = iota
+
+	// StaleFlag returns the data, reports a *StaleResponseError through the
+	// OnError hook and counts it in wallex_stale_responses_total.
+	StaleFlag
+)
+
+// StaleResponseError reports a market-data response older than
+// MaxResponseAge, typically served from a CDN or proxy cache.
+type StaleResponseError struct {
+	GoWallexError
+
+	Endpoint string
+
+	// Source tells which timestamp revealed the age: "Age header",
+	// "Date header" or "trade timestamp".
+	Source string
+
+	// DataTime is when the data was produced according to Source.
+	DataTime time.Time
+
+	Age    time.Duration
+	MaxAge time.Duration
+}
+
+// checkResponseHeaders applies the staleness check to the caching headers
+// of an unauthenticated response. Age reports how long a shared cache has
+// held the response; Date is when the origin generated it.
+func (c *Client) checkResponseHeaders(ctx context.Context, endpoint string, h http.Header) error {
+	if c.MaxResponseAge <= 0 {
+		return nil
+	}
+	now := time.Now()
+	if v := h.Get("Age"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			age := time.Duration(secs) * time.Second
+			if err := c.checkFreshness(ctx, endpoint, "Age header", now.Add(-age), now); err != nil {
+				return err
+			}
+		}
+	}
+	if v := h.Get("Date"); v != "" {
+		if date, err := http.ParseTime(v); err == nil {
+			// Date has one-second resolution; allow for it and clock skew.
+			return c.checkFreshness(ctx, endpoint, "Date header", date.Add(time.Second), now)
+		}
+	}
+	return nil
+}
+
+// checkFreshness compares dataTime with now and applies the StalePolicy.
+// It returns a non-nil error only under StaleReject.
+func (c *Client) checkFreshness(ctx context.Context, endpoint, source string, dataTime, now time.Time) error {
+	if c.MaxResponseAge <= 0 || dataTime.IsZero() {
+		return nil
+	}
+	age := now.Sub(dataTime)
+	if age <= c.MaxResponseAge {
+		return nil
+	}
+
+	err := &StaleResponseError{
+		GoWallexError: GoWallexError{
+			Message: "stale response from " + endpoint + ": " + source + " is " + age.Round(time.Millisecond).String() + " old",
+			Err:     nil,
+		},
+		Endpoint: endpoint,
+		Source:   source,
+		DataTime: dataTime,
+		Age:      age,
+		MaxAge:   c.MaxResponseAge,
+	}
+	if c.StalePolicy == StaleFlag {
+		c.metrics().Add("wallex_stale_responses_total", 1, Label{Name: "endpoint", Value: endpoint})
+		c.reportError(ctx, ErrorEvent{Err: err, Endpoint: endpoint, Local: true})
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,222 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrorClass groups errors by cause for Stats.
+type ErrorClass string
+
+const (
+	// ErrorClassNetwork: the request could not be sent or the response
+	// could not be read.
+	ErrorClassNetwork ErrorClass = "network"
+
+	// ErrorClassTimeout: a deadline expired.
+	ErrorClassTimeout ErrorClass = "timeout"
+
+	// ErrorClassCanceled: the context was canceled.
+	ErrorClassCanceled ErrorClass = "canceled"
+
+	// ErrorClassRateLimited: HTTP 429.
+	ErrorClassRateLimited ErrorClass = "rate_limited"
+
+	// ErrorClassAuth: HTTP 401 and 403.
+	ErrorClassAuth ErrorClass = "auth"
+
+	// ErrorClassClient: other HTTP 4xx responses.
+	ErrorClassClient ErrorClass = "client"
+
+	// ErrorClassServer: HTTP 5xx.
+	ErrorClassServer ErrorClass = "server"
+
+	// ErrorClassMaintenance: Wallex is in maintenance mode; see
+	// MaintenanceError.
+	ErrorClassMaintenance ErrorClass = "maintenance"
+
+	// ErrorClassDecode: the response could not be decoded.
+	ErrorClassDecode ErrorClass = "decode"
+
+	// ErrorClassLocal: the SDK refused the call without contacting
+	// Wallex, e.g. a ReadOnly client or a closed one.
+	ErrorClassLocal ErrorClass = "local"
+
+	// ErrorClassOther: anything else.
+	ErrorClassOther ErrorClass = "other"
+)
+
+// ClassifyError returns the class of an error returned by the client.
+// Errors raised locally, such as pre-trade rejections, are classified by
+// their type where possible and as ErrorClassOther otherwise.
+func ClassifyError(err error) ErrorClass {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	}
+
+	var mErr *MaintenanceError
+	if errors.As(err, &mErr) {
+		return ErrorClassMaintenance
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		switch s := apiErr.StatusCode; {
+		case s == http.StatusTooManyRequests:
+			return ErrorClassRateLimited
+		case s == http.StatusUnauthorized || s == http.StatusForbidden:
+			return ErrorClassAuth
+		case s >= 500:
+			return ErrorClassServer
+		case s >= 400:
+			return ErrorClassClient
+		}
+		return ErrorClassOther
+	}
+
+	var reqErr *RequestError
+	if errors.As(err, &reqErr) {
+		switch reqErr.Operation {
+		case "sending request", "reading response":
+			return ErrorClassNetwork
+		case "parsing response":
+			return ErrorClassDecode
+		}
+	}
+	return ErrorClassOther
+}
+
+// Stats is a snapshot of the activity of a client and its clones (see
+// With) since it was created or since ResetStats.
+type Stats struct {
+	// Since is when counting started.
+	Since time.Time
+
+	// Requests counts calls by endpoint key, e.g. EndpointDepth. A call
+	// is counted once however many attempts it took.
+	Requests map[string]uint64
+
+	// Attempts counts HTTP attempts; Retries those after the first of
+	// their call.
+	Attempts uint64
+	Retries  uint64
+
+	// Errors counts calls that failed, by class. Failed attempts that
+	// were retried are not counted.
+	Errors map[ErrorClass]uint64
+
+	// RateLimitWaits counts attempts the RateLimiter held back by more
+	// than a millisecond, and RateLimitWaitTime is the total time they
+	// waited. Together they show whether polling intervals exceed the
+	// rate budget.
+	RateLimitWaits    uint64
+	RateLimitWaitTime time.Duration
+
+	// BytesSent counts request bodies; BytesReceived counts response
+	// bodies as transferred, i.e. before decompression.
+	BytesSent     uint64
+	BytesReceived uint64
+}
+
+// statsRecorder accumulates Stats.
+type statsRecorder struct {
+	mu    sync.Mutex
+	stats Stats
+}
+
+func newStatsRecorder() *statsRecorder {
+	r := new(statsRecorder)
+	r.reset()
+	return r
+}
+
+func (r *statsRecorder) reset() {
+	r.mu.Lock()
+	r.stats = Stats{
+		Since:    time.Now(),
+		Requests: make(map[string]uint64),
+		Errors:   make(map[ErrorClass]uint64),
+	}
+	r.mu.Unlock()
+}
+
+func (r *statsRecorder) request(endpoint string) {
+	r.mu.Lock()
+	r.stats.Requests[endpoint]++
+	r.mu.Unlock()
+}
+
+func (r *statsRecorder) attempt(attempt int) {
+	r.mu.Lock()
+	r.stats.Attempts++
+	if attempt > 0 {
+		r.stats.Retries++
+	}
+	r.mu.Unlock()
+}
+
+func (r *statsRecorder) failure(class ErrorClass) {
+	r.mu.Lock()
+	r.stats.Errors[class]++
+	r.mu.Unlock()
+}
+
+func (r *statsRecorder) rateLimitWait(d time.Duration) {
+	if d <= time.Millisecond {
+		return
+	}
+	r.mu.Lock()
+	r.stats.RateLimitWaits++
+	r.stats.RateLimitWaitTime += d
+	r.mu.Unlock()
+}
+
+func (r *statsRecorder) transfer(sent, received int64) {
+	r.mu.Lock()
+	r.stats.BytesSent += uint64(sent)
+	r.stats.BytesReceived += uint64(received)
+	r.mu.Unlock()
+}
+
+// Stats returns the counters of the client, shared with its clones, for
+// operational dashboards and for tuning polling intervals against the
+// rate limit.
+func (c *Client) Stats() Stats {
+	c.stats.mu.Lock()
+	defer c.stats.mu.Unlock()
+
+	st := c.stats.stats
+	st.Requests = make(map[string]uint64, len(c.stats.stats.Requests))
+	for k, v := range c.stats.stats.Requests {
+		st.Requests[k] = v
+	}
+	st.Errors = make(map[ErrorClass]uint64, len(c.stats.stats.Errors))
+	for k, v := range c.stats.stats.Errors {
+		st.Errors[k] = v
+	}
+	return st
+}
+
+// ResetStats restarts the counters returned by Stats.
+func (c *Client) ResetStats() {
+	c.stats.reset()
+}
+
+// countingReader counts the bytes read from a response body.
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,165 @@
+package wallex
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// OrderStore persists order snapshots so that order tracking survives process
+// restarts. Implementations must be safe for concurrent use.
+type OrderStore interface {
+	// SaveOrder inserts or replaces the order keyed by its ClientOrderId.
+	// An order in a terminal state (see IsTerminalStatus) is deleted
+	// instead, so that the store holds open orders only.
+	SaveOrder(ctx context.Context, order t.BaseOrder) error
+
+	// LoadOrders returns all stored orders, none of them terminal.
+	LoadOrders(ctx context.Context) ([]t.BaseOrder, error)
+
+	// DeleteOrder removes an order. Deleting an unknown order is not an error.
+	DeleteOrder(ctx context.Context, clientOrderId string) error
+}
+
+// TradeStore persists user trades ingested by TradeSyncer. Implementations
+// must be safe for concurrent use.
+type TradeStore interface {
+	// AppendTrades stores trades. Trades already stored (by TradeKey) are
+	// ignored, so redelivery after a crash is harmless. This also drops a
+	// second fill identical to a stored one, see TradeKey.
+	AppendTrades(ctx context.Context, trades []t.UserTrade) error
+
+	// LoadTrades returns stored trades with a timestamp at or after since,
+	// oldest first. A zero since returns everything.
+	LoadTrades(ctx context.Context, since time.Time) ([]t.UserTrade, error)
+}
+
+// MemoryStore is an in-process implementation of OrderStore, TradeStore,
+// CursorStore and ScheduleStore. It is useful for tests and short-lived
+// programs; the filestore package provides a persistent implementation.
+type MemoryStore struct {
+	MemoryCursorStore
+
+	mu        sync.Mutex
+	orders    map[string]t.BaseOrder
+	trades    []t.UserTrade
+	keys      map[string]struct{}
+	schedules map[string]ScheduledOrder
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		MemoryCursorStore: MemoryCursorStore{cursors: make(map[string]TradeCursor)},
+		orders:            make(map[string]t.BaseOrder),
+		keys:              make(map[string]struct{}),
+		schedules:         make(map[string]ScheduledOrder),
+	}
+}
+
+// SaveOrder implements OrderStore.
+func (m *MemoryStore) SaveOrder(_ context.Context, order t.BaseOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if IsTerminalStatus(order.Status) {
+		delete(m.orders, order.ClientOrderId)
+		return nil
+	}
+	m.orders[order.ClientOrderId] = order
+	return nil
+}
+
+// LoadOrders implements OrderStore.
+func (m *MemoryStore) LoadOrders(_ context.Context) ([]t.BaseOrder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]t.BaseOrder, 0, len(m.orders))
+	for _, o := range m.orders {
+		out = append(out, o)
+	}
+	return out, nil
+}
+
+// DeleteOrder implements OrderStore.
+func (m *MemoryStore) DeleteOrder(_ context.Context, clientOrderId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.orders, clientOrderId)
+	return nil
+}
+
+// SaveSchedule implements ScheduleStore.
+func (m *MemoryStore) SaveSchedule(_ context.Context, s ScheduledOrder) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schedules[s.ID] = s
+	return nil
+}
+
+// LoadSchedules implements ScheduleStore.
+func (m *MemoryStore) LoadSchedules(_ context.Context) ([]ScheduledOrder, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]ScheduledOrder, 0, len(m.schedules))
+	for _, s := range m.schedules {
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// DeleteSchedule implements ScheduleStore.
+func (m *MemoryStore) DeleteSchedule(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.schedules, id)
+	return nil
+}
+
+// AppendTrades implements TradeStore.
+func (m *MemoryStore) AppendTrades(_ context.Context, trades []t.UserTrade) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, tr := range trades {
+		k := TradeKey(tr)
+		if _, ok := m.keys[k]; ok {
+			continue
+		}
+		m.keys[k] = struct{}{}
+		m.trades = append(m.trades, tr)
+	}
+	return nil
+}
+
+// LoadTrades implements TradeStore.
+func (m *MemoryStore) LoadTrades(_ context.Context, since time.Time) ([]t.UserTrade, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return FilterTradesSince(m.trades, since), nil
+}
+
+// FilterTradesSince returns the trades at or after since, sorted oldest first.
+// It is a helper for TradeStore implementations.
+func FilterTradesSince(trades []t.UserTrade, since time.Time) []t.UserTrade {
+	out := make([]t.UserTrade, 0, len(trades))
+	for _, tr := range trades {
+		if !since.IsZero() && tr.Timestamp.Before(since) {
+			continue
+		}
+		out = append(out, tr)
+	}
+	sortTradesByTime(out)
+	return out
+}
+
+// FilterTradesJalali returns the trades made on the Jalali dates from to to,
+// inclusive, in Tehran time, sorted oldest first.
+func FilterTradesJalali(trades []t.UserTrade, from, to u.JalaliDate) []t.UserTrade {
+	start, end := u.JalaliRange(from, to)
+	out := FilterTradesSince(trades, start)
+	i := sort.Search(len(out), func(i int) bool { return out[i].Timestamp.After(end) })
+	return out[:i]
+}
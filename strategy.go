@@ -0,0 +1,92 @@
+package wallex
+
+import (
+	"context"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// MarketEvent is one market data update delivered to a Strategy. Exactly
+// one of Candle, Trade and Book is set.
+type MarketEvent struct {
+	Symbol string
+	Time   time.Time
+	Candle *t.Candle
+	Trade  *t.Trade
+	Book   *t.OrderBook
+}
+
+// Broker is the order-entry surface a Strategy trades through. Client.Broker
+// returns the live implementation; simulation.Exchange is a simulated one,
+// used by the backtest package, so the same Strategy runs unchanged in both.
+type Broker interface {
+	// CreateOrder places an order and returns it as accepted.
+	CreateOrder(ctx context.Context, params t.CreateOrderParams) (*t.BaseOrder, error)
+
+	// CancelOrder cancels an open order.
+	CancelOrder(ctx context.Context, clientOrderId string) error
+
+	// OpenOrders returns the open orders of symbol, or of all markets when
+	// symbol is empty.
+	OpenOrders(ctx context.Context, symbol string) ([]t.BaseOrder, error)
+
+	// Balance returns the balance of asset; unknown assets are zero.
+	Balance(ctx context.Context, asset string) (t.Balance, error)
+}
+
+// Strategy is trading logic driven by market events. OnEvent is called
+// sequentially, once per event; returning an error stops a backtest.
+type Strategy interface {
+	OnEvent(ctx context.Context, ev MarketEvent, broker Broker) error
+}
+
+// StrategyFunc adapts a function to the Strategy interface.
+type StrategyFunc func(ctx context.Context, ev MarketEvent, broker Broker) error
+
+// OnEvent implements Strategy.
+func (f StrategyFunc) OnEvent(ctx context.Context, ev MarketEvent, broker Broker) error {
+	return f(ctx, ev, broker)
+}
+
+// Broker returns a Broker trading live through the client.
+func (c *Client) Broker() Broker {
+	return clientBroker{c}
+}
+
+type clientBroker struct {
+	c *Client
+}
+
+func (b clientBroker) CreateOrder(ctx context.Context, params t.CreateOrderParams) (*t.BaseOrder, error) {
+	resp, err := b.c.createOrder(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Result, nil
+}
+
+func (b clientBroker) CancelOrder(ctx context.Context, clientOrderId string) error {
+	_, err := b.c.cancelOrder(ctx, clientOrderId)
+	return err
+}
+
+func (b clientBroker) OpenOrders(ctx context.Context, symbol string) ([]t.BaseOrder, error) {
+	resp, err := b.c.getOpenOrders(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Result.Orders, nil
+}
+
+func (b clientBroker) Balance(ctx context.Context, asset string) (t.Balance, error) {
+	wallets, err := b.c.getWallets(ctx)
+	if err != nil {
+		return t.Balance{}, err
+	}
+	bal, ok := wallets.Get(asset)
+	if !ok {
+		bal.Asset = asset
+	}
+	return bal, nil
+}
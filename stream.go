@@ -0,0 +1,111 @@
+package wallex
+
+import "context"
+
+// OverflowPolicy selects what a producer does when its consumer's buffer
+// is full.
+type OverflowPolicy int
+
+const (
+	// DefaultOverflow, the zero value, selects the policy each stream
+	// documents as its default: DropOldest for a TradeTape, Block for
+	// WatchOrderBook and DropNewest for MarketDataCache subscriptions.
+	DefaultOverflow OverflowPolicy = iota
+
+	// DropOldest discards the oldest buffered item to make room, so that
+	// consumers that fall behind see the most recent data.
+	DropOldest
+
+	// DropNewest discards the item that did not fit.
+	DropNewest
+
+	// Block waits for the consumer, slowing down the producer.
+	Block
+
+	// Conflate discards every buffered item, so the consumer receives the
+	// newest one next. It suits streams of snapshots, where intermediate
+	// states are worthless once a newer one exists.
+	Conflate
+)
+
+// String returns the name of the policy, e.g. "drop-oldest".
+func (p OverflowPolicy) String() string {
+	switch p {
+	case DefaultOverflow:
+		return "default"
+	case DropOldest:
+		return "drop-oldest"
+	case DropNewest:
+		return "drop-newest"
+	case Block:
+		return "block"
+	case Conflate:
+		return "conflate"
+	}
+	return "unknown"
+}
+
+// SubscribeOptions configures a subscription to a stream.
+type SubscribeOptions struct {
+	// Buffer is the capacity of the subscription channel. Defaults to 1.
+	Buffer int
+
+	// Overflow is applied when the buffer is full.
+	Overflow OverflowPolicy
+}
+
+// or returns p, or def if p is DefaultOverflow.
+func (p OverflowPolicy) or(def OverflowPolicy) OverflowPolicy {
+	if p == DefaultOverflow {
+		return def
+	}
+	return p
+}
+
+// offer sends v to ch according to policy. Every message it discards is
+// passed to onDrop, if set, together with the message that replaces it, or
+// nil when v itself is discarded. It returns false, without sending, if
+// ctx is done while Block waits.
+//
+// ch must have a single sender, the caller, so that room made by dropping
+// a message cannot be taken by another sender.
+func offer[T any](ctx context.Context, ch chan T, v T, policy OverflowPolicy, onDrop func(dropped T, next *T)) bool {
+	select {
+	case ch <- v:
+		return true
+	default:
+	}
+
+	switch policy {
+	case DropNewest:
+		if onDrop != nil {
+			onDrop(v, nil)
+		}
+		return true
+	case DropOldest, Conflate:
+		for {
+			select {
+			case old := <-ch:
+				if onDrop != nil {
+					onDrop(old, &v)
+				}
+			default:
+			}
+			if policy == Conflate && len(ch) > 0 {
+				continue
+			}
+			select {
+			case ch <- v:
+				return true
+			default:
+			}
+		}
+	}
+
+	select {
+	case ch <- v:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
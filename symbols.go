@@ -0,0 +1,205 @@
+package wallex
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+	u "github.com/darhelm/go-wallex/utils"
+)
+
+// DefaultSymbolCacheTTL is how long a SymbolResolver trusts its cached market
+// list before refreshing it.
+const DefaultSymbolCacheTTL = 10 * time.Minute
+
+// symbolAliases maps alternative quote notations onto Wallex asset codes.
+var symbolAliases = map[string]string{
+	"IRT":     "TMN",
+	"TOMAN":   "TMN",
+	"TOMANS":  "TMN",
+	"USDTERC": "USDT",
+}
+
+// NormalizeSymbol converts common market notations into Wallex's symbol
+// format: "BTC/USDT", "btc-usdt", "btc_usdt", "BTC USDT" and "btcusdt" all
+// become "BTCUSDT". Persian digits are converted and IRT/TOMAN quote aliases
+// are mapped to TMN when a separator makes the quote asset identifiable.
+//
+// NormalizeSymbol does not check that the market exists; use
+// SymbolResolver.Resolve for validation.
+func NormalizeSymbol(symbol string) string {
+	s := strings.ToUpper(strings.TrimSpace(u.ToLatinDigits(symbol)))
+
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return r == '/' || r == '-' || r == '_' || r == ' ' || r == ':'
+	})
+	if len(parts) == 2 {
+		if alias, ok := symbolAliases[parts[1]]; ok {
+			parts[1] = alias
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// SymbolResolver validates symbols against the Wallex market list.
+//
+// The market list is fetched lazily on first use and refreshed after the
+// configured TTL. SymbolResolver is safe for concurrent use.
+type SymbolResolver struct {
+	client *Client
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	symbols   map[string]t.SymbolInfo
+	fetchedAt time.Time
+}
+
+// NewSymbolResolver creates a SymbolResolver that uses client to load market
+// information. A ttl <= 0 uses DefaultSymbolCacheTTL.
+func NewSymbolResolver(client *Client, ttl time.Duration) *SymbolResolver {
+	if ttl <= 0 {
+		ttl = DefaultSymbolCacheTTL
+	}
+	return &SymbolResolver{client: client, ttl: ttl}
+}
+
+// Resolve normalizes symbol and verifies that the market exists.
+//
+// Unknown symbols yield an *UnknownSymbolError, whose Suggestion holds the
+// closest known symbol when one is within a small edit distance.
+func (r *SymbolResolver) Resolve(ctx context.Context, symbol string) (string, error) {
+	norm := NormalizeSymbol(symbol)
+
+	symbols, err := r.load(ctx)
+	if err != nil {
+		return "", err
+	}
+	if _, ok := symbols[norm]; ok {
+		return norm, nil
+	}
+
+	suggestion := closestSymbol(norm, symbols)
+	message := "unknown Wallex symbol " + symbol
+	if suggestion != "" {
+		message += " (did you mean " + suggestion + "?)"
+	}
+
+	return "", &UnknownSymbolError{
+		GoWallexError: GoWallexError{
+			Message: message,
+			Err:     nil,
+		},
+		Symbol:     symbol,
+		Suggestion: suggestion,
+	}
+}
+
+// Info returns the market metadata of a symbol after resolving it.
+func (r *SymbolResolver) Info(ctx context.Context, symbol string) (t.SymbolInfo, error) {
+	norm, err := r.Resolve(ctx, symbol)
+	if err != nil {
+		return t.SymbolInfo{}, err
+	}
+	symbols, err := r.load(ctx)
+	if err != nil {
+		return t.SymbolInfo{}, err
+	}
+	return symbols[norm], nil
+}
+
+// Invalidate drops the cached market list so the next call refetches it.
+func (r *SymbolResolver) Invalidate() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.symbols = nil
+}
+
+func (r *SymbolResolver) load(ctx context.Context) (map[string]t.SymbolInfo, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.symbols != nil && time.Since(r.fetchedAt) < r.ttl {
+		return r.symbols, nil
+	}
+
+	markets, err := r.client.getMarketsInfo(ctx)
+	if err != nil {
+		if r.symbols != nil {
+			// Serve the stale list rather than failing every call while
+			// the markets endpoint is unavailable.
+			return r.symbols, nil
+		}
+		return nil, err
+	}
+
+	r.symbols = markets.Result.Symbols
+	r.fetchedAt = time.Now()
+	return r.symbols, nil
+}
+
+// resolveSymbol validates symbol when the client was created with
+// ValidateSymbols and returns it unchanged otherwise. The result is always
+// checked to be safe for use in a URL; an empty symbol is passed through.
+func (c *Client) resolveSymbol(ctx context.Context, symbol string) (string, error) {
+	if symbol == "" {
+		return symbol, nil
+	}
+	if c.symbols != nil {
+		resolved, err := c.symbols.Resolve(ctx, symbol)
+		var unknown *UnknownSymbolError
+		if errors.As(err, &unknown) {
+			c.reportError(ctx, ErrorEvent{Err: err, Final: true, Local: true})
+		}
+		if err != nil {
+			return resolved, err
+		}
+		symbol = resolved
+	}
+	if err := validateSymbol("symbol", symbol); err != nil {
+		c.reportError(ctx, ErrorEvent{Err: err, Final: true, Local: true})
+		return symbol, err
+	}
+	return symbol, nil
+}
+
+// closestSymbol returns the known symbol with the smallest edit distance to
+// s, provided the distance is at most 2.
+func closestSymbol(s string, symbols map[string]t.SymbolInfo) string {
+	names := make([]string, 0, len(symbols))
+	for name := range symbols {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best, bestDist := "", 3
+	for _, name := range names {
+		if d := levenshtein(s, name); d < bestDist {
+			best, bestDist = name, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
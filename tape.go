@@ -0,0 +1,325 @@
+package wallex
+
+import (
+	"container/heap"
+	"context"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Defaults used by NewTradeTape.
+const (
+	DefaultTapeInterval = time.Second
+	DefaultTapeBuffer   = 1024
+)
+
+// TapeTrade is one public trade on a TradeTape.
+type TapeTrade struct {
+	t.Trade
+
+	// Seq numbers the trades in delivery order, starting at 1.
+	Seq uint64
+
+	// ReceivedAt is when the trade was fetched.
+	ReceivedAt time.Time
+
+	// Late is set when the trade arrived after newer trades had already
+	// been delivered, so it breaks the time ordering of the tape.
+	Late bool
+}
+
+// TapeSink persists tape trades, e.g. to a file or a time-series database.
+// AppendTape is called from the tape goroutine with trades in delivery
+// order and must not block for long.
+type TapeSink interface {
+	AppendTape(ctx context.Context, trades []TapeTrade) error
+}
+
+// TradeTapeOptions tunes a TradeTape.
+type TradeTapeOptions struct {
+	// Symbols to follow. Required.
+	Symbols []string
+
+	// Interval between polls of GET /v1/trades per symbol. Defaults to
+	// DefaultTapeInterval.
+	Interval time.Duration
+
+	// ReorderWindow delays delivery so trades of different symbols, which
+	// are polled at different moments, can be merged in time order.
+	// Defaults to Interval.
+	ReorderWindow time.Duration
+
+	// Buffer is the capacity of the Trades channel. Defaults to
+	// DefaultTapeBuffer.
+	Buffer int
+
+	// Overflow selects what happens when Trades is full. Defaults to
+	// DropOldest.
+	Overflow OverflowPolicy
+
+	// Sink optionally persists every delivered trade, including trades
+	// dropped from a full channel.
+	Sink TapeSink
+
+	// OnError receives poll and sink errors. Polling continues.
+	OnError func(error)
+}
+
+// TradeTape merges the recent trades of many symbols into one time-ordered
+// stream tagged by symbol, for market-wide analytics.
+//
+// Each symbol is polled every Interval; new trades are deduplicated against
+// earlier polls and held for ReorderWindow, then delivered oldest first.
+// Polls go through the client RateLimiter: following n symbols at Interval
+// costs n/Interval requests per second.
+//
+// TradeTape implements Closer and is safe for concurrent use.
+type TradeTape struct {
+	client *Client
+	opts   TradeTapeOptions
+	out    chan TapeTrade
+
+	mu       sync.Mutex
+	pending  tapeHeap
+	lastSent time.Time
+	seq      uint64
+	dropped  atomic.Uint64
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	stop      chan struct{}
+	loops     sync.WaitGroup
+}
+
+// NewTradeTape returns a TradeTape following opts.Symbols. Call Run to start
+// it and read from Trades.
+func NewTradeTape(c *Client, opts TradeTapeOptions) *TradeTape {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultTapeInterval
+	}
+	if opts.ReorderWindow <= 0 {
+		opts.ReorderWindow = opts.Interval
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultTapeBuffer
+	}
+	opts.Overflow = opts.Overflow.or(DropOldest)
+	return &TradeTape{
+		client: c,
+		opts:   opts,
+		out:    make(chan TapeTrade, opts.Buffer),
+		stop:   make(chan struct{}),
+	}
+}
+
+// Trades returns the merged tape. It is closed when Run returns.
+func (tp *TradeTape) Trades() <-chan TapeTrade {
+	return tp.out
+}
+
+// Dropped returns the number of trades discarded by the Overflow policy.
+func (tp *TradeTape) Dropped() uint64 {
+	return tp.dropped.Load()
+}
+
+// Run polls all symbols and delivers trades until ctx is done, the tape is
+// closed or the client is shut down; pending trades are then flushed and
+// Trades is closed. A tape can only be run once.
+func (tp *TradeTape) Run(ctx context.Context) {
+	started := false
+	tp.startOnce.Do(func() { started = true })
+	if !started {
+		return
+	}
+
+	tp.loops.Add(1)
+	defer tp.loops.Done()
+	defer close(tp.out)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var pollers sync.WaitGroup
+	for _, symbol := range tp.opts.Symbols {
+		pollers.Add(1)
+		go func(symbol string) {
+			defer pollers.Done()
+			tp.poll(ctx, symbol)
+		}(symbol)
+	}
+
+	ticker := time.NewTicker(max(tp.opts.ReorderWindow/4, time.Millisecond))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+		case <-tp.stop:
+		case <-tp.client.Done():
+		case now := <-ticker.C:
+			tp.release(ctx, now.Add(-tp.opts.ReorderWindow))
+			continue
+		}
+		cancel()
+		pollers.Wait()
+		tp.release(context.Background(), time.Time{})
+		return
+	}
+}
+
+// Close implements Closer. It stops Run and waits for it to flush.
+func (tp *TradeTape) Close(ctx context.Context) error {
+	tp.stopOnce.Do(func() { close(tp.stop) })
+	return waitGroupDone(ctx, &tp.loops)
+}
+
+func (tp *TradeTape) poll(ctx context.Context, symbol string) {
+	var newest time.Time
+	seen := make(map[string]struct{})
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		}
+
+		trades, err := tp.client.getRecentTrades(ctx, symbol)
+		now := time.Now()
+		if err != nil {
+			if ctx.Err() == nil && tp.opts.OnError != nil {
+				tp.opts.OnError(err)
+			}
+			timer.Reset(tp.opts.Interval)
+			continue
+		}
+
+		var fresh []TapeTrade
+		batchNewest := newest
+		for _, tr := range trades.Result.LatestTrades {
+			if tr.Timestamp.Before(newest) {
+				continue
+			}
+			if tr.Symbol == "" {
+				tr.Symbol = symbol
+			}
+			if _, dup := seen[publicTradeKey(tr)]; dup {
+				continue
+			}
+			fresh = append(fresh, TapeTrade{Trade: tr, ReceivedAt: now})
+			if tr.Timestamp.After(batchNewest) {
+				batchNewest = tr.Timestamp.Time
+			}
+		}
+		if len(fresh) > 0 {
+			// Only the keys at the newest timestamp are needed to dedupe
+			// the overlap with the next poll.
+			if batchNewest.After(newest) {
+				seen = make(map[string]struct{})
+				newest = batchNewest
+			}
+			for _, tr := range fresh {
+				if tr.Timestamp.Equal(newest) {
+					seen[publicTradeKey(tr.Trade)] = struct{}{}
+				}
+			}
+
+			tp.mu.Lock()
+			for _, tr := range fresh {
+				heap.Push(&tp.pending, tr)
+			}
+			tp.mu.Unlock()
+		}
+
+		timer.Reset(tp.opts.Interval)
+	}
+}
+
+// release delivers pending trades with a timestamp at or before cutoff, or
+// all pending trades when cutoff is zero.
+func (tp *TradeTape) release(ctx context.Context, cutoff time.Time) {
+	tp.mu.Lock()
+	var batch []TapeTrade
+	for tp.pending.Len() > 0 {
+		next := tp.pending[0]
+		if !cutoff.IsZero() && next.Timestamp.After(cutoff) {
+			break
+		}
+		heap.Pop(&tp.pending)
+		tp.seq++
+		next.Seq = tp.seq
+		next.Late = next.Timestamp.Before(tp.lastSent)
+		if !next.Late {
+			tp.lastSent = next.Timestamp.Time
+		}
+		batch = append(batch, next)
+	}
+	tp.mu.Unlock()
+
+	if len(batch) == 0 {
+		return
+	}
+	if tp.opts.Sink != nil {
+		if err := tp.opts.Sink.AppendTape(ctx, batch); err != nil && tp.opts.OnError != nil {
+			tp.opts.OnError(&GoWallexError{
+				Message: "failed to persist tape trades",
+				Err:     err,
+			})
+		}
+	}
+	for _, tr := range batch {
+		tp.send(ctx, tr)
+	}
+}
+
+func (tp *TradeTape) send(ctx context.Context, tr TapeTrade) {
+	dropped := func(old TapeTrade, _ *TapeTrade) { tp.drop(old) }
+	if !offer(ctx, tp.out, tr, tp.opts.Overflow, dropped) {
+		tp.drop(tr)
+	}
+}
+
+func (tp *TradeTape) drop(tr TapeTrade) {
+	tp.dropped.Add(1)
+	tp.client.metrics().Add("wallex_tape_dropped_total", 1, Label{Name: "symbol", Value: tr.Symbol})
+}
+
+// publicTradeKey returns a stable identity for a public trade. Like user
+// trades, public trades carry no id.
+func publicTradeKey(tr t.Trade) string {
+	return strings.Join([]string{
+		strconv.FormatInt(tr.Timestamp.UnixNano(), 10),
+		tr.Symbol,
+		strconv.FormatBool(tr.IsBuyOrder),
+		keyNumber(tr.Price),
+		keyNumber(tr.Quantity),
+	}, "|")
+}
+
+// tapeHeap orders pending trades by timestamp, then by symbol.
+type tapeHeap []TapeTrade
+
+func (h tapeHeap) Len() int { return len(h) }
+func (h tapeHeap) Less(i, j int) bool {
+	if !h[i].Timestamp.Equal(h[j].Timestamp.Time) {
+		return h[i].Timestamp.Before(h[j].Timestamp.Time)
+	}
+	return h[i].Symbol < h[j].Symbol
+}
+func (h tapeHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *tapeHeap) Push(x interface{}) { *h = append(*h, x.(TapeTrade)) }
+func (h *tapeHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
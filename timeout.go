@@ -0,0 +1,83 @@
+package wallex
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Endpoint keys accepted by ClientOptions.EndpointTimeouts. A key is the
+// HTTP method followed by the versioned path, without query string.
+const (
+	EndpointMarkets        = "GET /v1/markets"
+	EndpointCurrencyStats  = "GET /v1/currencies/stats"
+	EndpointDepth          = "GET /v1/depth"
+	EndpointAllDepths      = "GET /v2/depth/all"
+	EndpointTrades         = "GET /v1/trades"
+	EndpointCandles        = "GET /v1/udf/history"
+	EndpointBalances       = "GET /v1/account/balances"
+	EndpointCreateOrder    = "POST /v1/account/orders"
+	EndpointCancelOrder    = "DELETE /v1/account/orders"
+	EndpointOpenOrders     = "GET /v1/account/openOrders"
+	EndpointOrderStatus    = "GET /v1/account/orders/{clientOrderId}"
+	EndpointUserTrades     = "GET /v1/account/trades"
+	EndpointNetworks       = "GET /v1/account/networks"
+	EndpointAccountFees    = "GET /v1/account/fee"
+	EndpointWithdrawFiat   = "POST /v1/account/money-withdrawal"
+	EndpointCryptoDeposits = "GET /v1/account/crypto-deposit"
+	EndpointCryptoWithdraw = "POST /v1/account/crypto-withdrawal"
+)
+
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout returns a copy of ctx that overrides the timeout of
+// each HTTP attempt made with it, taking precedence over
+// ClientOptions.EndpointTimeouts. Unlike context.WithTimeout, the limit
+// applies per attempt, so retries each get the full duration.
+func WithRequestTimeout(ctx context.Context, d time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, d)
+}
+
+// endpointKey derives the EndpointTimeouts key of a request URL. Path
+// segments holding an identifier are replaced by a placeholder, e.g.
+// EndpointOrderStatus, so that keys, and the latency, stats and metric
+// series keyed by them, do not grow with every order.
+func (c *Client) endpointKey(method, url string) string {
+	path := strings.TrimPrefix(url, c.baseUrl)
+	if i := strings.IndexByte(path, '?'); i >= 0 {
+		path = path[:i]
+	}
+	return method + " " + endpointTemplate(path)
+}
+
+// endpointTemplate replaces the identifier segment of path, if any.
+func endpointTemplate(path string) string {
+	const orders = "/v1/account/orders/"
+	if id, ok := strings.CutPrefix(path, orders); ok && id != "" && id != "history" && !strings.Contains(id, "/") {
+		return orders + "{clientOrderId}"
+	}
+	return path
+}
+
+// attemptTimeout returns the timeout for a single attempt of the request,
+// or zero when none is configured.
+func (c *Client) attemptTimeout(ctx context.Context, method, url string) time.Duration {
+	if d, ok := ctx.Value(requestTimeoutKey{}).(time.Duration); ok {
+		return d
+	}
+	if len(c.EndpointTimeouts) == 0 {
+		return 0
+	}
+	key := c.endpointKey(method, url)
+	if d, ok := c.EndpointTimeouts[key]; ok {
+		return d
+	}
+	// Paths with an identifier suffix (e.g. EndpointOrderStatus) fall back
+	// to their parent endpoint.
+	if i := strings.LastIndexByte(key, '/'); i > strings.IndexByte(key, ' ')+1 {
+		if d, ok := c.EndpointTimeouts[key[:i]]; ok {
+			return d
+		}
+	}
+	return 0
+}
@@ -0,0 +1,326 @@
+package wallex
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// OrderTransition describes a single observed change of an order's state.
+type OrderTransition struct {
+	// ClientOrderId identifies the order.
+	ClientOrderId string
+
+	// From is the previous status, or "" for the first observation.
+	From string
+
+	// To is the newly observed status.
+	To string
+
+	// Order is the order snapshot that triggered the transition.
+	Order t.BaseOrder
+
+	// Illegal is set when the transition is not allowed by the Wallex order
+	// lifecycle, e.g. leaving a terminal state or moving backwards.
+	Illegal bool
+
+	// Missed is set when intermediate states were skipped, e.g. NEW → CANCELED
+	// on an order that has executed quantity (a PARTIALLY_FILLED was missed).
+	Missed bool
+
+	// Fills aggregates the executions of the order so far; it is zero
+	// until the order executed.
+	Fills FillStats
+
+	// ObservedAt is the local time the update was processed.
+	ObservedAt time.Time
+}
+
+// OrderTracker models the lifecycle of user orders:
+//
+//	NEW → PARTIALLY_FILLED → FILLED
+//	  ↘         ↘
+//	   CANCELED / REJECTED / EXPIRED
+//
+// Updates may come from polling (Poll, Run) or be fed manually through
+// Update from any other source. Every distinct transition invokes the
+// registered callbacks exactly once, in the order updates are processed.
+// Re-observing the same status (for example on every poll) does not fire
+// callbacks; a PARTIALLY_FILLED order whose executed quantity grows is
+// reported as a PARTIALLY_FILLED → PARTIALLY_FILLED transition. Stale
+// snapshots that would move an order backwards, such as a NEW arriving after
+// PARTIALLY_FILLED, a shrinking executed quantity or any change after a
+// terminal state, are reported as Illegal transitions but not recorded.
+//
+// Callbacks run synchronously, one at a time, while the tracker holds its
+// delivery lock. They may call State, Active, FillStats, Untrack and
+// OnTransition, but must not call Update or Track, which would deadlock.
+//
+// OrderTracker is safe for concurrent use.
+type OrderTracker struct {
+	mu        sync.Mutex
+	orders    map[string]t.BaseOrder
+	callbacks []func(OrderTransition)
+	store     OrderStore
+	storeErr  func(error)
+
+	// cbMu serializes callback delivery so transitions are observed in order.
+	cbMu sync.Mutex
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewOrderTracker creates an empty OrderTracker.
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		orders: make(map[string]t.BaseOrder),
+		stop:   make(chan struct{}),
+	}
+}
+
+// NewPersistentOrderTracker creates an OrderTracker backed by store.
+//
+// Orders previously saved in store are restored without firing callbacks,
+// and every subsequent snapshot is written through to the store. Write
+// failures do not stop tracking; they are passed to onStoreError when it is
+// non-nil.
+func NewPersistentOrderTracker(ctx context.Context, store OrderStore, onStoreError func(error)) (*OrderTracker, error) {
+	orders, err := store.LoadOrders(ctx)
+	if err != nil {
+		return nil, &GoWallexError{
+			Message: "failed to restore tracked orders",
+			Err:     err,
+		}
+	}
+
+	tr := NewOrderTracker()
+	tr.store = store
+	tr.storeErr = onStoreError
+	for _, o := range orders {
+		tr.orders[o.ClientOrderId] = o
+	}
+	return tr, nil
+}
+
+// OnTransition registers fn to be called for every transition.
+func (tr *OrderTracker) OnTransition(fn func(OrderTransition)) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.callbacks = append(tr.callbacks, fn)
+}
+
+// Track starts tracking an order, typically the result of CreateOrder.
+// It is equivalent to Update.
+func (tr *OrderTracker) Track(order t.BaseOrder) {
+	tr.Update(order)
+}
+
+// Untrack stops tracking the given order.
+func (tr *OrderTracker) Untrack(clientOrderId string) {
+	tr.mu.Lock()
+	delete(tr.orders, clientOrderId)
+	store := tr.store
+	tr.mu.Unlock()
+
+	if store != nil {
+		tr.persist(store.DeleteOrder(context.Background(), clientOrderId))
+	}
+}
+
+func (tr *OrderTracker) persist(err error) {
+	if err != nil && tr.storeErr != nil {
+		tr.storeErr(err)
+	}
+}
+
+// State returns the last known snapshot of a tracked order.
+func (tr *OrderTracker) State(clientOrderId string) (t.BaseOrder, bool) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	o, ok := tr.orders[clientOrderId]
+	return o, ok
+}
+
+// FillStats returns the fill statistics of a tracked order, computed from
+// its last known snapshot.
+func (tr *OrderTracker) FillStats(clientOrderId string) (FillStats, bool) {
+	o, ok := tr.State(clientOrderId)
+	if !ok {
+		return FillStats{}, false
+	}
+	return ComputeFillStats(o), true
+}
+
+// Active returns the client order ids of all tracked orders that are not in
+// a terminal state.
+func (tr *OrderTracker) Active() []string {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	ids := make([]string, 0, len(tr.orders))
+	for id, o := range tr.orders {
+		if !IsTerminalStatus(o.Status) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Update feeds a new order snapshot into the tracker and fires callbacks if
+// it represents a transition. The detected transition, if any, is returned.
+// A snapshot moving the order backwards is reported as an Illegal transition
+// without replacing the recorded state.
+func (tr *OrderTracker) Update(order t.BaseOrder) *OrderTransition {
+	tr.cbMu.Lock()
+	defer tr.cbMu.Unlock()
+
+	tr.mu.Lock()
+	prev, known := tr.orders[order.ClientOrderId]
+	if known && prev.Status == order.Status && prev.ExecutedQty.Float() == order.ExecutedQty.Float() {
+		tr.mu.Unlock()
+		return nil
+	}
+	if known && IsTerminalStatus(prev.Status) && prev.Status == order.Status {
+		tr.mu.Unlock()
+		return nil
+	}
+
+	// Orders only move forward: a late snapshot behind the recorded state
+	// is only reported, as an illegal transition, and the state is kept.
+	regressed := known && (prev.Status != order.Status && !isLegalTransition(prev.Status, order.Status) ||
+		prev.Status == order.Status && order.ExecutedQty.Float() < prev.ExecutedQty.Float())
+	if !regressed {
+		tr.orders[order.ClientOrderId] = order
+	}
+	callbacks := append([]func(OrderTransition){}, tr.callbacks...)
+	store := tr.store
+	tr.mu.Unlock()
+
+	if store != nil && !regressed {
+		tr.persist(store.SaveOrder(context.Background(), order))
+	}
+
+	tn := OrderTransition{
+		ClientOrderId: order.ClientOrderId,
+		To:            order.Status,
+		Order:         order,
+		Fills:         ComputeFillStats(order),
+		ObservedAt:    time.Now(),
+	}
+	if known {
+		tn.From = prev.Status
+		tn.Illegal = regressed || !isLegalTransition(prev.Status, order.Status)
+		tn.Missed = prev.Status == t.OrderStatusNew &&
+			order.Status != t.OrderStatusPartiallyFilled &&
+			order.Status != t.OrderStatusFilled &&
+			hasExecuted(order)
+	}
+
+	for _, fn := range callbacks {
+		fn(tn)
+	}
+	return &tn
+}
+
+// Poll fetches the current status of every active tracked order once and
+// feeds the results through Update. The first request error is returned
+// after all orders were attempted.
+func (tr *OrderTracker) Poll(ctx context.Context, c *Client) error {
+	var firstErr error
+	for _, id := range tr.Active() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		resp, err := c.getOrderStatus(ctx, id)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		tr.Update(resp.Result)
+	}
+	return firstErr
+}
+
+// Run calls Poll every interval until ctx is done, the tracker is closed or
+// the client is shut down. Poll errors are not fatal; they are passed to
+// onError when it is non-nil.
+func (tr *OrderTracker) Run(ctx context.Context, c *Client, interval time.Duration, onError func(error)) {
+	tr.loops.Add(1)
+	defer tr.loops.Done()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tr.stop:
+			return
+		case <-c.Done():
+			return
+		case <-ticker.C:
+			if err := tr.Poll(ctx, c); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Close implements Closer. It stops all Run loops and waits for them, and for
+// any callback currently executing, to return. Update keeps working after
+// Close, so late snapshots can still be recorded.
+func (tr *OrderTracker) Close(ctx context.Context) error {
+	tr.stopOnce.Do(func() { close(tr.stop) })
+	if err := waitGroupDone(ctx, &tr.loops); err != nil {
+		return err
+	}
+
+	flushed := make(chan struct{})
+	go func() {
+		tr.cbMu.Lock()
+		tr.cbMu.Unlock()
+		close(flushed)
+	}()
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsTerminalStatus reports whether an order in the given status can no longer
+// change.
+func IsTerminalStatus(status string) bool {
+	switch status {
+	case t.OrderStatusFilled, t.OrderStatusCanceled, t.OrderStatusRejected, t.OrderStatusExpired:
+		return true
+	}
+	return false
+}
+
+func isLegalTransition(from, to string) bool {
+	if IsTerminalStatus(from) {
+		return false
+	}
+	switch from {
+	case t.OrderStatusNew:
+		return to != t.OrderStatusNew
+	case t.OrderStatusPartiallyFilled:
+		return to != t.OrderStatusNew && to != t.OrderStatusRejected
+	}
+	return true
+}
+
+func hasExecuted(o t.BaseOrder) bool {
+	return o.ExecutedPercent > 0 || !o.ExecutedQty.IsZero()
+}
@@ -0,0 +1,99 @@
+package wallex
+
+import (
+	"testing"
+
+	"github.com/darhelm/go-wallex/types"
+)
+
+func TestOrderTrackerTransitions(t *testing.T) {
+	snapshot := func(status, executed string) types.BaseOrder {
+		return types.BaseOrder{ClientOrderId: "o", Status: status, ExecutedQty: types.StringOrNumber(executed)}
+	}
+	cases := []struct {
+		name      string
+		snapshots []types.BaseOrder
+		want      []string // From→To of the fired transitions, "!" marks Illegal
+		final     string
+	}{
+		{
+			name: "forward",
+			snapshots: []types.BaseOrder{
+				snapshot(types.OrderStatusNew, "0"),
+				snapshot(types.OrderStatusPartiallyFilled, "0.1"),
+				snapshot(types.OrderStatusFilled, "0.2"),
+			},
+			want:  []string{"→NEW", "NEW→PARTIALLY_FILLED", "PARTIALLY_FILLED→FILLED"},
+			final: types.OrderStatusFilled,
+		},
+		{
+			name: "same quantity in another representation",
+			snapshots: []types.BaseOrder{
+				snapshot(types.OrderStatusPartiallyFilled, "0.10"),
+				snapshot(types.OrderStatusPartiallyFilled, "0.1"),
+			},
+			want:  []string{"→PARTIALLY_FILLED"},
+			final: types.OrderStatusPartiallyFilled,
+		},
+		{
+			name: "stale NEW after PARTIALLY_FILLED",
+			snapshots: []types.BaseOrder{
+				snapshot(types.OrderStatusNew, "0"),
+				snapshot(types.OrderStatusPartiallyFilled, "0.1"),
+				snapshot(types.OrderStatusNew, "0"),
+				snapshot(types.OrderStatusPartiallyFilled, "0.1"),
+			},
+			want:  []string{"→NEW", "NEW→PARTIALLY_FILLED", "!PARTIALLY_FILLED→NEW"},
+			final: types.OrderStatusPartiallyFilled,
+		},
+		{
+			name: "shrinking executed quantity",
+			snapshots: []types.BaseOrder{
+				snapshot(types.OrderStatusPartiallyFilled, "0.2"),
+				snapshot(types.OrderStatusPartiallyFilled, "0.1"),
+				snapshot(types.OrderStatusPartiallyFilled, "0.2"),
+			},
+			want:  []string{"→PARTIALLY_FILLED", "!PARTIALLY_FILLED→PARTIALLY_FILLED"},
+			final: types.OrderStatusPartiallyFilled,
+		},
+		{
+			name: "stale snapshot after terminal state",
+			snapshots: []types.BaseOrder{
+				snapshot(types.OrderStatusFilled, "0.2"),
+				snapshot(types.OrderStatusPartiallyFilled, "0.1"),
+				snapshot(types.OrderStatusFilled, "0.2"),
+			},
+			want:  []string{"→FILLED", "!FILLED→PARTIALLY_FILLED"},
+			final: types.OrderStatusFilled,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tr := NewOrderTracker()
+			var got []string
+			tr.OnTransition(func(tn OrderTransition) {
+				s := tn.From + "→" + tn.To
+				if tn.Illegal {
+					s = "!" + s
+				}
+				got = append(got, s)
+			})
+			for _, o := range tc.snapshots {
+				tr.Update(o)
+			}
+
+			if len(got) != len(tc.want) {
+				t.Fatalf("transitions = %v, want %v", got, tc.want)
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Fatalf("transitions = %v, want %v", got, tc.want)
+				}
+			}
+			if o, _ := tr.State("o"); o.Status != tc.final {
+				t.Errorf("recorded status = %s, want %s", o.Status, tc.final)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package wallex
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// DefaultStatsWindows are the windows of a TradeStatsEngine created without
+// explicit ones.
+var DefaultStatsWindows = []time.Duration{time.Minute, 5 * time.Minute, time.Hour}
+
+// TradeStats summarizes the trades of one symbol over a rolling window.
+// Volumes are in the base asset, QuoteVolume in the quote asset.
+type TradeStats struct {
+	Symbol string
+	Window time.Duration
+
+	// From and To bound the window; To is the reference time of the
+	// snapshot.
+	From time.Time
+	To   time.Time
+
+	Trades      int
+	Volume      float64
+	QuoteVolume float64
+	BuyVolume   float64
+	SellVolume  float64
+
+	// Imbalance is (BuyVolume - SellVolume) / Volume, in [-1, 1]. Buys
+	// and sells are classified by the taker side.
+	Imbalance float64
+
+	// VWAP is the volume-weighted average price, Last the most recent one.
+	VWAP float64
+	Last float64
+
+	// Volatility is the realized volatility over the window: the square
+	// root of the summed squared log returns between consecutive trades.
+	// It is not annualized.
+	Volatility float64
+}
+
+type statsSample struct {
+	at    time.Time
+	price float64
+	qty   float64
+	buy   bool
+}
+
+// TradeStatsEngine computes rolling per-symbol trade statistics, typically
+// fed from a TradeTape:
+//
+//	engine := wallex.NewTradeStatsEngine()
+//	go engine.Consume(ctx, tape.Trades())
+//	st, _ := engine.Snapshot("BTCUSDT", time.Minute)
+//
+// Samples older than the longest window, measured from the newest trade of
+// the symbol, are discarded. TradeStatsEngine is safe for concurrent use.
+type TradeStatsEngine struct {
+	windows []time.Duration
+	longest time.Duration
+
+	mu      sync.Mutex
+	symbols map[string][]statsSample
+}
+
+// NewTradeStatsEngine returns an engine keeping enough history for the given
+// windows, or DefaultStatsWindows when none are given.
+func NewTradeStatsEngine(windows ...time.Duration) *TradeStatsEngine {
+	if len(windows) == 0 {
+		windows = DefaultStatsWindows
+	}
+	windows = append([]time.Duration(nil), windows...)
+	sort.Slice(windows, func(i, j int) bool { return windows[i] < windows[j] })
+	return &TradeStatsEngine{
+		windows: windows,
+		longest: windows[len(windows)-1],
+		symbols: make(map[string][]statsSample),
+	}
+}
+
+// Add records a trade. Trades with an unparsable price or quantity are
+// ignored. Trades may arrive slightly out of order.
+func (e *TradeStatsEngine) Add(tr t.Trade) {
+	price, err := strconv.ParseFloat(tr.Price.String(), 64)
+	if err != nil || price <= 0 {
+		return
+	}
+	qty, err := strconv.ParseFloat(tr.Quantity.String(), 64)
+	if err != nil {
+		return
+	}
+	s := statsSample{at: tr.Timestamp.Time, price: price, qty: qty, buy: tr.IsBuyOrder}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	samples := e.symbols[tr.Symbol]
+	i := len(samples)
+	for i > 0 && samples[i-1].at.After(s.at) {
+		i--
+	}
+	samples = append(samples, statsSample{})
+	copy(samples[i+1:], samples[i:])
+	samples[i] = s
+
+	cutoff := samples[len(samples)-1].at.Add(-e.longest)
+	drop := sort.Search(len(samples), func(i int) bool { return !samples[i].at.Before(cutoff) })
+	if drop > 0 {
+		samples = append(samples[:0], samples[drop:]...)
+	}
+	e.symbols[tr.Symbol] = samples
+}
+
+// Consume adds every trade received from trades until the channel is
+// closed or ctx is done.
+func (e *TradeStatsEngine) Consume(ctx context.Context, trades <-chan TapeTrade) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tr, ok := <-trades:
+			if !ok {
+				return
+			}
+			e.Add(tr.Trade)
+		}
+	}
+}
+
+// Symbols returns the symbols with recorded trades, sorted.
+func (e *TradeStatsEngine) Symbols() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	out := make([]string, 0, len(e.symbols))
+	for s := range e.symbols {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Snapshot returns the statistics of symbol over the window ending now.
+// It reports false if no trade of symbol was recorded.
+func (e *TradeStatsEngine) Snapshot(symbol string, window time.Duration) (TradeStats, bool) {
+	return e.SnapshotAt(symbol, window, time.Now())
+}
+
+// SnapshotAt is Snapshot for the window ending at the given time, e.g. the
+// time of the newest trade when replaying historical data.
+func (e *TradeStatsEngine) SnapshotAt(symbol string, window time.Duration, at time.Time) (TradeStats, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	samples, ok := e.symbols[symbol]
+	if !ok {
+		return TradeStats{}, false
+	}
+
+	st := TradeStats{Symbol: symbol, Window: window, From: at.Add(-window), To: at}
+	var prev float64
+	var sumSq float64
+	for _, s := range samples {
+		if s.at.Before(st.From) || s.at.After(at) {
+			continue
+		}
+		st.Trades++
+		st.Volume += s.qty
+		st.QuoteVolume += s.qty * s.price
+		if s.buy {
+			st.BuyVolume += s.qty
+		} else {
+			st.SellVolume += s.qty
+		}
+		if prev > 0 {
+			r := math.Log(s.price / prev)
+			sumSq += r * r
+		}
+		prev = s.price
+		st.Last = s.price
+	}
+	if st.Volume > 0 {
+		st.Imbalance = (st.BuyVolume - st.SellVolume) / st.Volume
+		st.VWAP = st.QuoteVolume / st.Volume
+	}
+	st.Volatility = math.Sqrt(sumSq)
+	return st, true
+}
+
+// Snapshots returns the statistics of symbol for every configured window,
+// shortest first.
+func (e *TradeStatsEngine) Snapshots(symbol string) []TradeStats {
+	now := time.Now()
+	out := make([]TradeStats, 0, len(e.windows))
+	for _, w := range e.windows {
+		if st, ok := e.SnapshotAt(symbol, w, now); ok {
+			out = append(out, st)
+		}
+	}
+	return out
+}
@@ -0,0 +1,300 @@
+package wallex
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// TradeCursor marks the position of a TradeSyncer in the user trade history.
+//
+// Wallex user trades carry no trade id, so the cursor stores the timestamp of
+// the newest delivered trade together with the keys of all trades sharing
+// that timestamp, one entry per delivered fill. This lets the syncer skip
+// already-delivered trades when consecutive responses overlap.
+type TradeCursor struct {
+	Timestamp time.Time `json:"timestamp"`
+	Seen      []string  `json:"seen"`
+}
+
+// CursorStore persists TradeSyncer cursors between runs.
+//
+// LoadCursor must return (nil, nil) when no cursor was saved under key.
+type CursorStore interface {
+	LoadCursor(ctx context.Context, key string) (*TradeCursor, error)
+	SaveCursor(ctx context.Context, key string, cursor TradeCursor) error
+}
+
+// MemoryCursorStore is an in-process CursorStore. Cursors are lost when the
+// process exits; use a persistent implementation for real ingestion.
+// It is safe for concurrent use.
+type MemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]TradeCursor
+}
+
+// NewMemoryCursorStore creates an empty MemoryCursorStore.
+func NewMemoryCursorStore() *MemoryCursorStore {
+	return &MemoryCursorStore{cursors: make(map[string]TradeCursor)}
+}
+
+// LoadCursor implements CursorStore.
+func (m *MemoryCursorStore) LoadCursor(_ context.Context, key string) (*TradeCursor, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cur, ok := m.cursors[key]
+	if !ok {
+		return nil, nil
+	}
+	return &cur, nil
+}
+
+// SaveCursor implements CursorStore.
+func (m *MemoryCursorStore) SaveCursor(_ context.Context, key string, cursor TradeCursor) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cursors[key] = cursor
+	return nil
+}
+
+// TradeSyncer incrementally pulls the authenticated user's trades.
+//
+// Each Sync call pages back through the trade history until it reaches the
+// stored cursor, or through the whole history on the first call, drops
+// everything at or before the cursor, hands the remaining trades (oldest
+// first) to the caller and only then advances the cursor. If paging or the
+// handler fails, the cursor is left untouched and the same trades are
+// delivered again on the next Sync, giving at-least-once delivery into
+// downstream databases.
+//
+// Identical fills sharing a TradeKey are all delivered; see TradeKey.
+//
+// TradeSyncer is safe for concurrent use; concurrent Sync calls are
+// serialized.
+type TradeSyncer struct {
+	client *Client
+	store  CursorStore
+	params t.UserTradesParams
+	key    string
+
+	mu sync.Mutex
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewTradeSyncer creates a TradeSyncer for the trades selected by params.
+//
+// The cursor key is derived from the symbol and side filters, so several
+// syncers with different filters can share one store.
+func NewTradeSyncer(client *Client, store CursorStore, params t.UserTradesParams) *TradeSyncer {
+	return &TradeSyncer{
+		client: client,
+		store:  store,
+		params: params,
+		key:    "trades:" + params.Symbol + ":" + params.Side,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Key returns the key under which the syncer stores its cursor.
+func (s *TradeSyncer) Key() string {
+	return s.key
+}
+
+// Sync fetches new trades and passes them to handle. The cursor is saved only
+// if handle returns nil. Sync does not call handle when there is nothing new.
+func (s *TradeSyncer) Sync(ctx context.Context, handle func([]t.UserTrade) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cursor, err := s.store.LoadCursor(ctx, s.key)
+	if err != nil {
+		return &GoWallexError{
+			Message: "failed to load trade cursor",
+			Err:     err,
+		}
+	}
+
+	// Wallex lists trades newest first: page back until the cursor is
+	// reached, so that no trade of a long gap is skipped.
+	var fetched [][]t.UserTrade
+	for page, err := range pages(ctx, s.params.Page, s.params.PerPage, s.client.userTradesPage(s.params)) {
+		if err != nil {
+			return err
+		}
+		i := 0
+		for i < len(page) && (cursor == nil || !page[i].Timestamp.Before(cursor.Timestamp)) {
+			i++
+		}
+		fetched = append(fetched, page[:i])
+		if i < len(page) {
+			break
+		}
+	}
+
+	fresh := newTradesSince(cursor, fetched)
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if err := handle(fresh); err != nil {
+		return err
+	}
+
+	if err := s.store.SaveCursor(ctx, s.key, advanceCursor(cursor, fresh)); err != nil {
+		return &GoWallexError{
+			Message: "failed to save trade cursor",
+			Err:     err,
+		}
+	}
+	return nil
+}
+
+// SyncToStore is Sync with a handler that appends new trades to store.
+// The store keeps a single copy of identical fills; see TradeStore.
+func (s *TradeSyncer) SyncToStore(ctx context.Context, store TradeStore) error {
+	return s.Sync(ctx, func(trades []t.UserTrade) error {
+		return store.AppendTrades(ctx, trades)
+	})
+}
+
+// Run calls Sync every interval until ctx is done, the syncer is closed or
+// the client is shut down. Sync errors are passed to onError when it is
+// non-nil.
+func (s *TradeSyncer) Run(ctx context.Context, interval time.Duration, handle func([]t.UserTrade) error, onError func(error)) {
+	s.loops.Add(1)
+	defer s.loops.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(ctx, handle); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stop:
+			return
+		case <-s.client.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close implements Closer. It stops all Run loops and waits for an
+// in-progress Sync, including its handler and cursor write, to finish.
+func (s *TradeSyncer) Close(ctx context.Context) error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	return waitGroupDone(ctx, &s.loops)
+}
+
+// TradeKey returns a stable identity for a user trade. Wallex does not expose
+// trade ids, so the key is composed of the trade's immutable fields.
+//
+// Two distinct fills with the same timestamp, symbol, side, price, quantity
+// and fee therefore share a key. TradeSyncer tells them apart by count: it
+// delivers every copy found in one page and skips only as many copies as
+// its cursor has already seen.
+func TradeKey(tr t.UserTrade) string {
+	side := "S"
+	if tr.IsBuyer {
+		side = "B"
+	}
+	return strings.Join([]string{
+		tr.Timestamp.UTC().Format(time.RFC3339Nano),
+		tr.Symbol,
+		side,
+		keyNumber(tr.Price),
+		keyNumber(tr.Quantity),
+		keyNumber(tr.Fee),
+	}, "|")
+}
+
+// keyNumber formats v canonically, so that the same value sent as "0.10" and
+// as 0.1 yields the same key. Values that are not numbers are kept verbatim.
+func keyNumber(v t.StringOrNumber) string {
+	f, err := strconv.ParseFloat(v.String(), 64)
+	if err != nil {
+		return v.String()
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// newTradesSince returns the trades in pages newer than cursor, oldest
+// first.
+//
+// A key repeated within one page stands for that many fills, while pages
+// shifted by new trades may repeat a trade across pages. Each key is
+// therefore counted as often as it occurs in the page holding it most
+// often, less the copies already recorded in cursor.Seen.
+func newTradesSince(cursor *TradeCursor, pages [][]t.UserTrade) []t.UserTrade {
+	seen := make(map[string]int)
+	if cursor != nil {
+		for _, k := range cursor.Seen {
+			seen[k]++
+		}
+	}
+
+	var order []string
+	trades := make(map[string]t.UserTrade)
+	counts := make(map[string]int)
+	for _, page := range pages {
+		inPage := make(map[string]int)
+		for _, tr := range page {
+			if cursor != nil && tr.Timestamp.Before(cursor.Timestamp) {
+				continue
+			}
+			k := TradeKey(tr)
+			if _, ok := trades[k]; !ok {
+				trades[k] = tr
+				order = append(order, k)
+			}
+			inPage[k]++
+			counts[k] = max(counts[k], inPage[k])
+		}
+	}
+
+	var fresh []t.UserTrade
+	for _, k := range order {
+		for range counts[k] - seen[k] {
+			fresh = append(fresh, trades[k])
+		}
+	}
+
+	sortTradesByTime(fresh)
+	return fresh
+}
+
+func sortTradesByTime(trades []t.UserTrade) {
+	sort.SliceStable(trades, func(i, j int) bool {
+		return trades[i].Timestamp.Before(trades[j].Timestamp)
+	})
+}
+
+// advanceCursor moves cursor past the delivered trades, which must be sorted
+// oldest first.
+func advanceCursor(cursor *TradeCursor, delivered []t.UserTrade) TradeCursor {
+	newest := delivered[len(delivered)-1].Timestamp
+
+	next := TradeCursor{Timestamp: newest}
+	if cursor != nil && cursor.Timestamp.Equal(newest) {
+		next.Seen = append(next.Seen, cursor.Seen...)
+	}
+	for _, tr := range delivered {
+		if tr.Timestamp.Equal(newest) {
+			next.Seen = append(next.Seen, TradeKey(tr))
+		}
+	}
+	return next
+}
@@ -0,0 +1,117 @@
+package wallex
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-wallex/types"
+)
+
+// tradeServer serves the user trade history as pages, newest first. A
+// page beyond the last is empty.
+type tradeServer struct {
+	mu    sync.Mutex
+	pages [][]types.UserTrade
+}
+
+func (s *tradeServer) set(pages ...[]types.UserTrade) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pages = pages
+}
+
+func (s *tradeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/v1/account/trades" {
+		http.NotFound(w, r)
+		return
+	}
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	s.mu.Lock()
+	var trades []types.UserTrade
+	if page >= 1 && page <= len(s.pages) {
+		trades = s.pages[page-1]
+	}
+	s.mu.Unlock()
+
+	var resp types.UserTradesResponse
+	resp.Success = true
+	resp.Result.AccountLatestTrades = trades
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
+
+func TestTradeSyncerIdenticalFills(t *testing.T) {
+	at := time.Date(2024, 5, 12, 10, 52, 44, 0, time.UTC)
+	fill := func(qty string, sec int) types.UserTrade {
+		return types.UserTrade{
+			Symbol:    "BTCUSDT",
+			Quantity:  types.StringOrNumber(qty),
+			Price:     "60000",
+			Fee:       "0.0000008",
+			FeeAsset:  "BTC",
+			IsBuyer:   true,
+			Timestamp: at.Add(time.Duration(sec) * time.Second),
+		}
+	}
+	a, b, c := fill("0.0004", 2), fill("0.0004", 1), fill("0.001", 0)
+
+	cases := []struct {
+		name  string
+		syncs [][][]types.UserTrade // pages served for each Sync
+		want  []int                 // trades delivered by each Sync
+	}{
+		{
+			name: "identical fills in one page",
+			syncs: [][][]types.UserTrade{
+				{{a, a, c}},
+				{{a, a, c}},
+				{{a, a, a, c}},
+			},
+			want: []int{3, 0, 1},
+		},
+		{
+			// A new trade shifts b onto both pages.
+			name: "trade repeated across shifted pages",
+			syncs: [][][]types.UserTrade{
+				{{a, b}, {b, c}},
+			},
+			want: []int{3},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := &tradeServer{}
+			ts := httptest.NewServer(srv)
+			t.Cleanup(ts.Close)
+
+			client, err := NewClient(ClientOptions{BaseUrl: ts.URL, ApiKey: "key", MaxRetries: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			syncer := NewTradeSyncer(client, NewMemoryCursorStore(), types.UserTradesParams{PerPage: 2})
+			for i, pages := range tc.syncs {
+				srv.set(pages...)
+				var delivered int
+				if err := syncer.Sync(ctx, func(trades []types.UserTrade) error {
+					delivered += len(trades)
+					return nil
+				}); err != nil {
+					t.Fatal(err)
+				}
+				if delivered != tc.want[i] {
+					t.Errorf("Sync %d delivered %d trades, want %d", i+1, delivered, tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package wallex
+
+import (
+	"net/http"
+	"time"
+)
+
+// Connection pool defaults used when NewClient builds the HTTP client.
+// They keep enough idle connections to Wallex for sustained polling at the
+// 100 req/s limit without re-dialing; net/http's default of 2 idle
+// connections per host causes constant TLS handshakes at that rate.
+const (
+	DefaultMaxIdleConnsPerHost = 32
+	DefaultIdleConnTimeout     = 90 * time.Second
+	DefaultTLSHandshakeTimeout = 10 * time.Second
+)
+
+// newTransport returns a clone of http.DefaultTransport tuned with the
+// connection pool options.
+func newTransport(opts ClientOptions) *http.Transport {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+
+	tr.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	if opts.MaxIdleConnsPerHost > 0 {
+		tr.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	if tr.MaxIdleConns < tr.MaxIdleConnsPerHost {
+		tr.MaxIdleConns = tr.MaxIdleConnsPerHost
+	}
+
+	tr.IdleConnTimeout = DefaultIdleConnTimeout
+	if opts.IdleConnTimeout > 0 {
+		tr.IdleConnTimeout = opts.IdleConnTimeout
+	}
+
+	tr.TLSHandshakeTimeout = DefaultTLSHandshakeTimeout
+	if opts.TLSHandshakeTimeout > 0 {
+		tr.TLSHandshakeTimeout = opts.TLSHandshakeTimeout
+	}
+
+	return tr
+}
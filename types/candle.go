@@ -0,0 +1,137 @@
+package types
+
+import (
+	"sort"
+	"time"
+)
+
+// ExchangeLocation is the time zone Wallex aligns daily candles to: Iran
+// Standard Time, UTC+03:30 (Iran observes no daylight saving time).
+var ExchangeLocation = time.FixedZone("IRST", 3*3600+30*60)
+
+// Resolution is a candle resolution in TradingView UDF notation.
+type Resolution string
+
+// Resolutions accepted by GET /v1/udf/history.
+const (
+	Resolution1m  Resolution = "1"
+	Resolution5m  Resolution = "5"
+	Resolution15m Resolution = "15"
+	Resolution30m Resolution = "30"
+	Resolution1h  Resolution = "60"
+	Resolution3h  Resolution = "180"
+	Resolution4h  Resolution = "240"
+	Resolution6h  Resolution = "360"
+	Resolution12h Resolution = "720"
+	Resolution1D  Resolution = "1D"
+	Resolution1W  Resolution = "1W"
+)
+
+// Duration returns the length of one candle, or zero for an unknown
+// resolution.
+func (r Resolution) Duration() time.Duration {
+	switch r {
+	case Resolution1D:
+		return 24 * time.Hour
+	case Resolution1W:
+		return 7 * 24 * time.Hour
+	}
+	var minutes int
+	for _, c := range r {
+		if c < '0' || c > '9' {
+			return 0
+		}
+		minutes = minutes*10 + int(c-'0')
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// Candle is one OHLCV bar. Time is the start of the bar; Volume is in the
+// base asset.
+type Candle struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// CandleParams defines the query parameters of:
+//
+//	GET /v1/udf/history
+//
+// Symbol and Resolution are required; From and To are sent as unix
+// seconds.
+type CandleParams struct {
+	Symbol     string     `json:"symbol" url:"symbol"`
+	Resolution Resolution `json:"resolution" url:"resolution"`
+	From       time.Time  `json:"from" url:"from,unix,omitempty"`
+	To         time.Time  `json:"to" url:"to,unix,omitempty"`
+}
+
+// CandleHistory is the TradingView UDF response of GET /v1/udf/history.
+// Unlike other endpoints it is not wrapped in the success/result envelope:
+//
+//	{
+//	  "s": "ok",
+//	  "t": [1709251200, 1709254800],
+//	  "o": ["62010.5", "62200.0"],
+//	  "h": ["62350.0", "62410.2"],
+//	  "l": ["61920.1", "62050.0"],
+//	  "c": ["62200.0", "62311.7"],
+//	  "v": ["12.41", "9.87"]
+//	}
+//
+// Status is "ok", "no_data" (with NextTime set to the time of the previous
+// available bar, if any) or "error".
+type CandleHistory struct {
+	Status   string           `json:"s"`
+	Message  string           `json:"errmsg"`
+	NextTime int64            `json:"nextTime"`
+	Time     []int64          `json:"t"`
+	Open     []NumericOrEmpty `json:"o"`
+	High     []NumericOrEmpty `json:"h"`
+	Low      []NumericOrEmpty `json:"l"`
+	Close    []NumericOrEmpty `json:"c"`
+	Volume   []NumericOrEmpty `json:"v"`
+}
+
+// Candles converts the column arrays into candles sorted by time. Bars with
+// missing columns are skipped.
+func (h *CandleHistory) Candles() Candles {
+	if h == nil {
+		return nil
+	}
+	out := make(Candles, 0, len(h.Time))
+	for i, ts := range h.Time {
+		if i >= len(h.Open) || i >= len(h.High) || i >= len(h.Low) || i >= len(h.Close) {
+			break
+		}
+		c := Candle{
+			Time:  time.Unix(ts, 0).UTC(),
+			Open:  float64(h.Open[i]),
+			High:  float64(h.High[i]),
+			Low:   float64(h.Low[i]),
+			Close: float64(h.Close[i]),
+		}
+		if i < len(h.Volume) {
+			c.Volume = float64(h.Volume[i])
+		}
+		out = append(out, c)
+	}
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Time.Before(out[j].Time) })
+	return out
+}
+
+// Candles is a time-ordered candle series.
+type Candles []Candle
+
+// Closes returns the close prices of the series.
+func (cs Candles) Closes() []float64 {
+	out := make([]float64, len(cs))
+	for i, c := range cs {
+		out[i] = c.Close
+	}
+	return out
+}
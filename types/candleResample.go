@@ -0,0 +1,80 @@
+package types
+
+import "time"
+
+// AlignTime returns the start of the bar of length interval that contains
+// ts, using exchange time boundaries: intervals of a day or longer start at
+// midnight in loc (weeks on Saturday, the first day of the Iranian week),
+// shorter intervals are aligned to multiples of interval since midnight.
+// A nil loc means ExchangeLocation.
+func AlignTime(ts time.Time, interval time.Duration, loc *time.Location) time.Time {
+	if loc == nil {
+		loc = ExchangeLocation
+	}
+	local := ts.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	const day = 24 * time.Hour
+	switch {
+	case interval <= 0:
+		return ts
+	case interval < day:
+		return midnight.Add(local.Sub(midnight) / interval * interval).In(ts.Location())
+	case interval == 7*day:
+		back := (int(midnight.Weekday()) - int(time.Saturday) + 7) % 7
+		return midnight.AddDate(0, 0, -back).In(ts.Location())
+	default:
+		days := int(interval / day)
+		epoch := time.Date(1970, 1, 1, 0, 0, 0, 0, loc)
+		n := int(midnight.Sub(epoch).Round(day) / day)
+		return midnight.AddDate(0, 0, -(n % days)).In(ts.Location())
+	}
+}
+
+// Resample aggregates the series into bars of length interval aligned with
+// AlignTime, e.g. 1m candles into 5m, 1h or 1D ones. interval should be a
+// multiple of the source resolution. Opens and closes come from the first
+// and last source bars, highs and lows are the extremes, volumes are
+// summed. Empty target bars are not created; use FillGaps for that.
+func (cs Candles) Resample(interval time.Duration, loc *time.Location) Candles {
+	var out Candles
+	for _, c := range cs {
+		start := AlignTime(c.Time, interval, loc)
+		if n := len(out); n > 0 && out[n-1].Time.Equal(start) {
+			last := &out[n-1]
+			last.High = max(last.High, c.High)
+			last.Low = min(last.Low, c.Low)
+			last.Close = c.Close
+			last.Volume += c.Volume
+			continue
+		}
+		c.Time = start
+		out = append(out, c)
+	}
+	return out
+}
+
+// FillGaps inserts a flat, zero-volume candle at the previous close for
+// every missing bar of length interval, so the series has one bar per
+// interval. Bars are expected to be aligned already.
+func (cs Candles) FillGaps(interval time.Duration) Candles {
+	if len(cs) == 0 || interval <= 0 {
+		return cs
+	}
+	out := make(Candles, 0, len(cs))
+	out = append(out, cs[0])
+	for _, c := range cs[1:] {
+		prev := out[len(out)-1]
+		for next := prev.Time.Add(interval); next.Before(c.Time); next = next.Add(interval) {
+			out = append(out, Candle{
+				Time:  next,
+				Open:  prev.Close,
+				High:  prev.Close,
+				Low:   prev.Close,
+				Close: prev.Close,
+			})
+		}
+		out = append(out, c)
+	}
+	return out
+}
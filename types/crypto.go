@@ -0,0 +1,92 @@
+package types
+
+import "time"
+
+// CryptoTransferStatus is the processing state of an on-chain deposit or
+// withdrawal.
+type CryptoTransferStatus string
+
+// Crypto transfer statuses reported by Wallex.
+const (
+	CryptoStatusPending    CryptoTransferStatus = "PENDING"
+	CryptoStatusConfirming CryptoTransferStatus = "CONFIRMING"
+	CryptoStatusDone       CryptoTransferStatus = "DONE"
+	CryptoStatusRejected   CryptoTransferStatus = "REJECTED"
+	CryptoStatusCanceled   CryptoTransferStatus = "CANCELED"
+)
+
+// IsFinal reports whether the transfer can no longer change state.
+func (s CryptoTransferStatus) IsFinal() bool {
+	switch s {
+	case CryptoStatusDone, CryptoStatusRejected, CryptoStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// CryptoTransfer is a single on-chain deposit or withdrawal. Amount and Fee
+// are number-strings denominated in Asset.
+type CryptoTransfer struct {
+	ID                    int64                `json:"id"`
+	Asset                 string               `json:"asset"`
+	Network               string               `json:"network"`
+	Amount                StringOrNumber       `json:"amount"`
+	Fee                   StringOrNumber       `json:"fee"`
+	Address               string               `json:"address"`
+	Memo                  string               `json:"memo"`
+	TxID                  string               `json:"txId"`
+	Confirmations         int                  `json:"confirmations"`
+	RequiredConfirmations int                  `json:"requiredConfirmations"`
+	Status                CryptoTransferStatus `json:"status"`
+	CreatedAt             time.Time            `json:"created_at"`
+	UpdatedAt             time.Time            `json:"updated_at"`
+}
+
+// CryptoHistoryParams defines the query parameters of the crypto history
+// endpoints. All fields are optional; zero values use the server defaults.
+type CryptoHistoryParams struct {
+	Asset   string `json:"asset" url:"asset,omitempty"`
+	Page    int    `json:"page" url:"page,omitempty"`
+	PerPage int    `json:"per_page" url:"per_page,omitempty"`
+}
+
+// CryptoWithdrawalParams defines the payload used to request an on-chain
+// withdrawal via:
+//
+//	POST /v1/account/crypto-withdrawal
+//
+// Network is a network code from GET /v1/account/networks; Memo is required
+// only when the network says so. Amount is a number-string in Asset and
+// includes the network fee.
+type CryptoWithdrawalParams struct {
+	Asset   string `json:"asset"`
+	Network string `json:"network"`
+	Address string `json:"address"`
+	Memo    string `json:"memo,omitempty"`
+	Amount  string `json:"amount"`
+}
+
+// CryptoWithdrawalResponse wraps the withdrawal created by
+// POST /v1/account/crypto-withdrawal.
+type CryptoWithdrawalResponse struct {
+	BaseResponse
+	Result CryptoTransfer `json:"result"`
+}
+
+// CryptoHistoryResponse wraps a page of on-chain transfers returned by:
+//
+//	GET /v1/account/crypto-deposit
+//	GET /v1/account/crypto-withdrawal
+//
+// Response shape:
+//
+//	{
+//	  "success": true,
+//	  "result": [ ...list of CryptoTransfer... ],
+//	  "result_info": { "page": 1, "per_page": 20, "total_count": 7 }
+//	}
+type CryptoHistoryResponse struct {
+	BaseResponse
+	Result     []CryptoTransfer `json:"result"`
+	ResultInfo PageInfo         `json:"result_info"`
+}
@@ -0,0 +1,48 @@
+package types
+
+// CurrencyStat holds the global market statistics of one currency, as
+// returned by GET /v1/currencies/stats. Numeric fields may arrive as
+// numbers, number-strings or null.
+type CurrencyStat struct {
+	Key               string         `json:"key"`
+	Name              string         `json:"name"`
+	NameEn            string         `json:"name_en"`
+	Rank              IntOrEmpty     `json:"rank"`
+	Dominance         StringOrNumber `json:"dominance"`
+	Volume24h         StringOrNumber `json:"volume_24h"`
+	MarketCap         StringOrNumber `json:"market_cap"`
+	Price             StringOrNumber `json:"price"`
+	DailyHighPrice    StringOrNumber `json:"daily_high_price"`
+	DailyLowPrice     StringOrNumber `json:"daily_low_price"`
+	PercentChange1h   StringOrNumber `json:"percent_change_1h"`
+	PercentChange24h  StringOrNumber `json:"percent_change_24h"`
+	PercentChange7d   StringOrNumber `json:"percent_change_7d"`
+	CirculatingSupply StringOrNumber `json:"circulating_supply"`
+	TotalSupply       StringOrNumber `json:"total_supply"`
+	MaxSupply         StringOrNumber `json:"max_supply"`
+	UpdatedAt         WallexTime     `json:"updated_at"`
+}
+
+// CurrencyStatsResponse wraps the response of GET /v1/currencies/stats.
+//
+// Response shape:
+//
+//	{ "success": true, "result": [ { "key": "BTC", ... }, ... ] }
+type CurrencyStatsResponse struct {
+	BaseResponse
+	Result []CurrencyStat `json:"result"`
+}
+
+// Get returns the statistics of the currency with the given key, e.g.
+// Get("BTC").
+func (r *CurrencyStatsResponse) Get(key string) (CurrencyStat, bool) {
+	if r == nil {
+		return CurrencyStat{}, false
+	}
+	for _, s := range r.Result {
+		if s.Key == key {
+			return s, true
+		}
+	}
+	return CurrencyStat{}, false
+}
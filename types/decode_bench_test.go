@@ -0,0 +1,193 @@
+package types_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/darhelm/go-wallex/fixtures"
+	"github.com/darhelm/go-wallex/types"
+)
+
+// Sizes of the scaled payloads. They approximate production responses:
+// Wallex lists a few hundred markets, /v2/depth/all carries about 20 levels
+// per side, and /v1/trades returns the last 100 trades.
+const (
+	benchMarkets = 300
+	benchLevels  = 20
+	benchTrades  = 100
+)
+
+var (
+	payloadOnce sync.Once
+	marketsBody []byte
+	depthsBody  []byte
+	tradesBody  []byte
+)
+
+func loadPayloads() {
+	payloadOnce.Do(func() {
+		marketsBody = scaledMarkets(benchMarkets)
+		depthsBody = scaledAllDepths(benchMarkets, benchLevels)
+		tradesBody = scaledTrades(benchTrades)
+	})
+}
+
+// benchDecode decodes body into a fresh value from newValue on every
+// iteration, reporting throughput and allocations.
+func benchDecode(b *testing.B, body []byte, newValue func() any) {
+	b.SetBytes(int64(len(body)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := json.Unmarshal(body, newValue()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkDecodeMarkets measures decoding a /v1/markets response with
+// benchMarkets symbols.
+func BenchmarkDecodeMarkets(b *testing.B) {
+	loadPayloads()
+	benchDecode(b, marketsBody, func() any { return new(types.MarketInformation) })
+}
+
+// BenchmarkDecodeAllDepths measures decoding a /v2/depth/all response with
+// benchMarkets books of benchLevels levels per side.
+func BenchmarkDecodeAllDepths(b *testing.B) {
+	loadPayloads()
+	benchDecode(b, depthsBody, func() any { return new(types.AllDepths) })
+}
+
+// BenchmarkDecodeTrades measures decoding a /v1/trades response with
+// benchTrades trades.
+func BenchmarkDecodeTrades(b *testing.B) {
+	loadPayloads()
+	benchDecode(b, tradesBody, func() any { return new(types.Trades) })
+}
+
+// BenchmarkDecodeDepth measures decoding the bundled single-symbol
+// /v1/depth response.
+func BenchmarkDecodeDepth(b *testing.B) {
+	benchDecode(b, fixtures.MustLoad(fixtures.Depth), func() any { return new(types.Depth) })
+}
+
+// BenchmarkDecodeWallets measures decoding the bundled
+// /v1/account/balances response.
+func BenchmarkDecodeWallets(b *testing.B) {
+	benchDecode(b, fixtures.MustLoad(fixtures.Wallets), func() any { return new(types.Wallets) })
+}
+
+// BenchmarkDecodeAllDepthsGet measures the typical collector access
+// pattern: decode a /v2/depth/all response and read three books from it.
+func BenchmarkDecodeAllDepthsGet(b *testing.B) {
+	loadPayloads()
+	b.SetBytes(int64(len(depthsBody)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var depths types.AllDepths
+		if err := json.Unmarshal(depthsBody, &depths); err != nil {
+			b.Fatal(err)
+		}
+		for _, symbol := range []string{"SYM000", "SYM001", "SYM002"} {
+			if _, err := depths.Result.Book(symbol); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// scaledMarkets returns a /v1/markets payload with n symbols, cloned from
+// the bundled fixture.
+func scaledMarkets(n int) []byte {
+	var doc struct {
+		Result struct {
+			Symbols map[string]map[string]json.RawMessage `json:"symbols"`
+		} `json:"result"`
+		Success bool `json:"success"`
+	}
+	mustDecode(fixtures.Markets, &doc)
+
+	templates := make([]map[string]json.RawMessage, 0, len(doc.Result.Symbols))
+	for _, s := range doc.Result.Symbols {
+		templates = append(templates, s)
+	}
+	symbols := make(map[string]map[string]json.RawMessage, n)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("SYM%03d", i)
+		entry := make(map[string]json.RawMessage, len(templates[0]))
+		for k, v := range templates[i%len(templates)] {
+			entry[k] = v
+		}
+		entry["symbol"] = mustMarshal(name)
+		symbols[name] = entry
+	}
+	doc.Result.Symbols = symbols
+	return mustMarshal(doc)
+}
+
+// scaledAllDepths returns a /v2/depth/all payload with n symbols and the
+// given number of levels per side.
+func scaledAllDepths(n, levels int) []byte {
+	var doc struct {
+		Result  map[string]map[string][]json.RawMessage `json:"result"`
+		Success bool                                    `json:"success"`
+	}
+	mustDecode(fixtures.AllDepths, &doc)
+
+	books := make([]map[string][]json.RawMessage, 0, len(doc.Result))
+	for _, b := range doc.Result {
+		books = append(books, b)
+	}
+	result := make(map[string]map[string][]json.RawMessage, n)
+	for i := 0; i < n; i++ {
+		src := books[i%len(books)]
+		book := make(map[string][]json.RawMessage, len(src))
+		for side, lv := range src {
+			out := make([]json.RawMessage, levels)
+			for j := range out {
+				out[j] = lv[j%len(lv)]
+			}
+			book[side] = out
+		}
+		result[fmt.Sprintf("SYM%03d", i)] = book
+	}
+	doc.Result = result
+	return mustMarshal(doc)
+}
+
+// scaledTrades returns a /v1/trades payload with n trades.
+func scaledTrades(n int) []byte {
+	var doc struct {
+		Result struct {
+			LatestTrades []json.RawMessage `json:"latestTrades"`
+		} `json:"result"`
+		Success bool `json:"success"`
+	}
+	mustDecode(fixtures.Trades, &doc)
+
+	src := doc.Result.LatestTrades
+	out := make([]json.RawMessage, n)
+	for i := range out {
+		out[i] = src[i%len(src)]
+	}
+	doc.Result.LatestTrades = out
+	return mustMarshal(doc)
+}
+
+func mustDecode(name string, v any) {
+	if err := fixtures.Decode(name, v); err != nil {
+		panic(err)
+	}
+}
+
+func mustMarshal(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
@@ -0,0 +1,99 @@
+package types_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/darhelm/go-wallex/types"
+)
+
+// decodeCase is one JSON input for a tolerant type. want is the decoded
+// value in tolerant mode, and in strict mode unless strictErr is set.
+type decodeCase[T any] struct {
+	in        string
+	want      T
+	strictErr bool
+}
+
+// testDecode decodes every case in tolerant and in strict mode.
+func testDecode[T any](t *testing.T, cases []decodeCase[T], equal func(a, b T) bool) {
+	t.Helper()
+	t.Cleanup(func() { types.SetStrictDecoding(false) })
+
+	for _, strict := range []bool{false, true} {
+		types.SetStrictDecoding(strict)
+		for _, tc := range cases {
+			var got T
+			err := json.Unmarshal([]byte(tc.in), &got)
+			if strict && tc.strictErr {
+				if err == nil {
+					t.Errorf("strict: %s decoded to %v, want an error", tc.in, got)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("strict=%v: %s: %v", strict, tc.in, err)
+				continue
+			}
+			if !equal(got, tc.want) {
+				t.Errorf("strict=%v: %s decoded to %v, want %v", strict, tc.in, got, tc.want)
+			}
+		}
+	}
+}
+
+func equal[T comparable](a, b T) bool { return a == b }
+
+func TestDecodeNumericOrEmpty(t *testing.T) {
+	testDecode(t, []decodeCase[types.NumericOrEmpty]{
+		{in: `"-"`, want: 0},
+		{in: `""`, want: 0},
+		{in: `null`, want: 0},
+		{in: `12.5`, want: 12.5},
+		{in: `"12.5"`, want: 12.5},
+		{in: `"abc"`, want: 0, strictErr: true},
+		{in: `true`, want: 0, strictErr: true},
+	}, equal)
+}
+
+func TestDecodeIntOrEmpty(t *testing.T) {
+	testDecode(t, []decodeCase[types.IntOrEmpty]{
+		{in: `"-"`, want: 0},
+		{in: `""`, want: 0},
+		{in: `null`, want: 0},
+		{in: `12`, want: 12},
+		{in: `12.0`, want: 12},
+		{in: `"12"`, want: 12},
+		{in: `"abc"`, want: 0, strictErr: true},
+		{in: `true`, want: 0, strictErr: true},
+	}, equal)
+}
+
+func TestDecodeStringOrNumber(t *testing.T) {
+	testDecode(t, []decodeCase[types.StringOrNumber]{
+		{in: `"-"`, want: "-"},
+		{in: `""`, want: ""},
+		{in: `null`, want: ""},
+		{in: `0.00040000`, want: "0.00040000"},
+		{in: `"0.00040000"`, want: "0.00040000"},
+		{in: `"abc"`, want: "abc", strictErr: true},
+		{in: `true`, want: "", strictErr: true},
+	}, equal)
+}
+
+func TestDecodeWallexTime(t *testing.T) {
+	at := time.Date(2024, 5, 12, 10, 41, 5, 0, time.UTC)
+	testDecode(t, []decodeCase[types.WallexTime]{
+		{in: `"-"`},
+		{in: `""`},
+		{in: `null`},
+		{in: `1715510465000`, want: types.NewWallexTime(at)},
+		{in: `1715510465`, want: types.NewWallexTime(at)},
+		{in: `"1715510465000"`, want: types.NewWallexTime(at)},
+		{in: `"2024-05-12T10:41:05Z"`, want: types.NewWallexTime(at)},
+		{in: `"2024-05-12 10:41:05"`, want: types.NewWallexTime(at)},
+		{in: `"abc"`, strictErr: true},
+		{in: `true`, strictErr: true},
+	}, func(a, b types.WallexTime) bool { return a.Equal(b.Time) })
+}
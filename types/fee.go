@@ -0,0 +1,36 @@
+package types
+
+// SymbolFee is the account's trading fee in one market. Rates are fractions
+// of the order value, e.g. "0.0025" for 0.25%.
+type SymbolFee struct {
+	MakerFeeRate  StringOrNumber `json:"makerFeeRate"`
+	TakerFeeRate  StringOrNumber `json:"takerFeeRate"`
+	RecentDaysSum StringOrNumber `json:"recent_days_sum"` // Recent trading volume the fee tier is based on
+}
+
+// MakerRate returns the maker fee rate.
+func (f SymbolFee) MakerRate() float64 { return f.MakerFeeRate.Float() }
+
+// TakerRate returns the taker fee rate.
+func (f SymbolFee) TakerRate() float64 { return f.TakerFeeRate.Float() }
+
+// AccountFeesResponse wraps the response of:
+//
+//	GET /v1/account/fee
+//
+// Response shape:
+//
+//	{ "success": true, "result": { "BTCTMN": { "makerFeeRate": "0.0025", ... }, ... } }
+type AccountFeesResponse struct {
+	BaseResponse
+	Result map[string]SymbolFee `json:"result"`
+}
+
+// Get returns the fee of symbol.
+func (r *AccountFeesResponse) Get(symbol string) (SymbolFee, bool) {
+	if r == nil {
+		return SymbolFee{}, false
+	}
+	f, ok := r.Result[symbol]
+	return f, ok
+}
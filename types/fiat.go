@@ -0,0 +1,96 @@
+package types
+
+import "time"
+
+// FiatTransferStatus is the processing state of a Toman deposit or
+// withdrawal.
+type FiatTransferStatus string
+
+// Fiat transfer statuses reported by Wallex.
+const (
+	FiatStatusPending    FiatTransferStatus = "PENDING"
+	FiatStatusProcessing FiatTransferStatus = "PROCESSING"
+	FiatStatusDone       FiatTransferStatus = "DONE"
+	FiatStatusRejected   FiatTransferStatus = "REJECTED"
+	FiatStatusCanceled   FiatTransferStatus = "CANCELED"
+)
+
+// IsFinal reports whether the transfer can no longer change state.
+func (s FiatTransferStatus) IsFinal() bool {
+	switch s {
+	case FiatStatusDone, FiatStatusRejected, FiatStatusCanceled:
+		return true
+	}
+	return false
+}
+
+// PageInfo describes the position of a paginated list response.
+// It appears under result_info in history endpoints.
+type PageInfo struct {
+	Page       int `json:"page"`
+	PerPage    int `json:"per_page"`
+	TotalCount int `json:"total_count"`
+}
+
+// HasNext reports whether more pages follow this one.
+func (p PageInfo) HasNext() bool {
+	return p.PerPage > 0 && p.Page*p.PerPage < p.TotalCount
+}
+
+// HistoryParams defines the pagination query parameters shared by history
+// endpoints. Zero values use the server defaults.
+type HistoryParams struct {
+	Page    int `json:"page" url:"page,omitempty"`
+	PerPage int `json:"per_page" url:"per_page,omitempty"`
+}
+
+// FiatTransfer is a single Toman deposit or withdrawal. Value and Fee are
+// number-strings in TMN.
+type FiatTransfer struct {
+	ID           int64              `json:"id"`
+	Value        StringOrNumber     `json:"value"`
+	Fee          StringOrNumber     `json:"fee"`
+	Iban         string             `json:"iban"`
+	CardNumber   string             `json:"cardNumber"`
+	TrackingCode string             `json:"trackingCode"`
+	Status       FiatTransferStatus `json:"status"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// FiatWithdrawalParams defines the payload used to request a Toman
+// withdrawal via:
+//
+//	POST /v1/account/money-withdrawal
+//
+// Iban must be one of the account's registered and verified IBANs
+// (e.g. "IR820540102680020817909002"). Value is the amount in TMN.
+type FiatWithdrawalParams struct {
+	Iban  string `json:"iban"`
+	Value string `json:"value"`
+}
+
+// FiatWithdrawalResponse wraps the withdrawal created by
+// POST /v1/account/money-withdrawal.
+type FiatWithdrawalResponse struct {
+	BaseResponse
+	Result FiatTransfer `json:"result"`
+}
+
+// FiatHistoryResponse wraps a page of Toman transfers returned by:
+//
+//	GET /v1/account/money-deposit
+//	GET /v1/account/money-withdrawal
+//
+// Response shape:
+//
+//	{
+//	  "success": true,
+//	  "result": [ ...list of FiatTransfer... ],
+//	  "result_info": { "page": 1, "per_page": 20, "total_count": 53 }
+//	}
+type FiatHistoryResponse struct {
+	BaseResponse
+	Result     []FiatTransfer `json:"result"`
+	ResultInfo PageInfo       `json:"result_info"`
+}
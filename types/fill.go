@@ -0,0 +1,74 @@
+package types
+
+import "encoding/json"
+
+// Fill is a single execution of an order, as listed in the "fills" array of
+// order-status and cancel responses.
+//
+// Wallex has used both its own naming (quantity, fee, feeAsset) and
+// Binance-style naming (qty, commission, commissionAsset, tradeId) for these
+// entries; both decode into the same fields.
+type Fill struct {
+	TradeID         string         `json:"tradeId"`
+	Symbol          string         `json:"symbol"`
+	Price           StringOrNumber `json:"price"`
+	Quantity        StringOrNumber `json:"quantity"`
+	Sum             StringOrNumber `json:"sum"`
+	Commission      StringOrNumber `json:"fee"`
+	CommissionAsset string         `json:"feeAsset"`
+	FeeCoefficient  StringOrNumber `json:"feeCoefficient"`
+	IsBuyer         bool           `json:"isBuyer"`
+	Timestamp       WallexTime     `json:"timestamp"`
+}
+
+func (f *Fill) UnmarshalJSON(data []byte) error {
+	type raw Fill
+	var aux struct {
+		raw
+		Qty                StringOrNumber  `json:"qty"`
+		AltCommission      StringOrNumber  `json:"commission"`
+		AltCommissionAsset string          `json:"commissionAsset"`
+		ID                 json.RawMessage `json:"id"`
+		RawTradeID         json.RawMessage `json:"tradeId"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	*f = Fill(aux.raw)
+	if f.Quantity == "" {
+		f.Quantity = aux.Qty
+	}
+	if f.Commission == "" {
+		f.Commission = aux.AltCommission
+	}
+	if f.CommissionAsset == "" {
+		f.CommissionAsset = aux.AltCommissionAsset
+	}
+	f.TradeID = rawID(aux.RawTradeID)
+	if f.TradeID == "" {
+		f.TradeID = rawID(aux.ID)
+	}
+	return nil
+}
+
+// rawID renders a JSON string or number identifier as a string.
+func rawID(data json.RawMessage) string {
+	if len(data) == 0 || string(data) == "null" {
+		return ""
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s
+	}
+	return string(data)
+}
+
+// Notional returns price × quantity of the fill, preferring the reported
+// sum when present.
+func (f Fill) Notional() float64 {
+	if s := f.Sum.Float(); s != 0 {
+		return s
+	}
+	return f.Price.Float() * f.Quantity.Float()
+}
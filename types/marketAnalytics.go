@@ -0,0 +1,42 @@
+package types
+
+import "sort"
+
+// TopGainers returns up to n markets with the highest 24h price change.
+// If quote is non-empty, only markets quoted in that asset are considered.
+// A non-positive n returns all matching markets.
+func (m *MarketInformation) TopGainers(quote string, n int) []SymbolInfo {
+	return m.top(quote, n, func(a, b SymbolInfo) bool {
+		return a.Stats.DayCh > b.Stats.DayCh
+	})
+}
+
+// TopLosers returns up to n markets with the lowest 24h price change.
+// If quote is non-empty, only markets quoted in that asset are considered.
+// A non-positive n returns all matching markets.
+func (m *MarketInformation) TopLosers(quote string, n int) []SymbolInfo {
+	return m.top(quote, n, func(a, b SymbolInfo) bool {
+		return a.Stats.DayCh < b.Stats.DayCh
+	})
+}
+
+// TopByVolume returns up to n markets with the highest 24h quote volume.
+// Volumes are compared in the quote asset, so pass a quote filter when
+// mixing TMN and USDT markets would be misleading.
+// A non-positive n returns all matching markets.
+func (m *MarketInformation) TopByVolume(quote string, n int) []SymbolInfo {
+	return m.top(quote, n, func(a, b SymbolInfo) bool {
+		return a.Stats.QuoteVolumeDayFloat() > b.Stats.QuoteVolumeDayFloat()
+	})
+}
+
+func (m *MarketInformation) top(quote string, n int, less func(a, b SymbolInfo) bool) []SymbolInfo {
+	markets := m.Filter(func(s SymbolInfo) bool {
+		return quote == "" || s.QuoteAsset == quote
+	})
+	sort.SliceStable(markets, func(i, j int) bool { return less(markets[i], markets[j]) })
+	if n > 0 && len(markets) > n {
+		markets = markets[:n]
+	}
+	return markets
+}
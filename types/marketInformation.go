@@ -1,8 +1,12 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
+	"sort"
 	"strconv"
+	"sync"
 	"time"
 )
 
@@ -36,6 +40,10 @@ func (d *Direction) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
+	if StrictDecoding() {
+		return fmt.Errorf("wallex: cannot decode direction %s", data)
+	}
+
 	// Fallback: leave empty
 	*d = Direction{}
 	return nil
@@ -46,31 +54,75 @@ func (d *Direction) UnmarshalJSON(data []byte) error {
 // This object appears inside the SymbolInfo struct in GET /v1/markets.
 //
 // Numeric price and volume values are returned as **number strings** in the
-// Wallex API, while percentage changes are returned as numeric values.
+// Wallex API, while percentage changes are returned as numeric values. The raw
+// strings are kept as-is; the XxxFloat accessors return parsed values, with
+// placeholders such as "-" reported as 0.
 //
 // Relevant endpoint:
 //
 //	GET /v1/markets (result.symbols[*].stats)
 type Stats struct {
-	BidPrice       string         `json:"bidPrice"`
-	AskPrice       string         `json:"askPrice"`
+	BidPrice       StringOrNumber `json:"bidPrice"`
+	AskPrice       StringOrNumber `json:"askPrice"`
 	DayCh          NumericOrEmpty `json:"24h_ch"`
 	WeekCh         NumericOrEmpty `json:"7d_ch"`
-	DayVolume      string         `json:"24h_volume"`
-	WeekVolume     string         `json:"7d_volume"`
-	QuoteVolumeDay string         `json:"24h_quoteVolume"`
-	HighPriceDay   string         `json:"24h_highPrice"`
-	LowPriceDay    string         `json:"24h_lowPrice"`
-	LastPrice      string         `json:"lastPrice"`
-	LastQty        string         `json:"lastQty"`
+	DayVolume      StringOrNumber `json:"24h_volume"`
+	WeekVolume     StringOrNumber `json:"7d_volume"`
+	QuoteVolumeDay StringOrNumber `json:"24h_quoteVolume"`
+	HighPriceDay   StringOrNumber `json:"24h_highPrice"`
+	LowPriceDay    StringOrNumber `json:"24h_lowPrice"`
+	LastPrice      StringOrNumber `json:"lastPrice"`
+	LastQty        StringOrNumber `json:"lastQty"`
 	LastTradeSide  string         `json:"lastTradeSide"`
-	BidVolume      string         `json:"bidVolume"`
-	AskVolume      string         `json:"askVolume"`
-	BidCount       NumericOrEmpty `json:"bidCount"`
-	AskCount       NumericOrEmpty `json:"askCount"`
+	BidVolume      StringOrNumber `json:"bidVolume"`
+	AskVolume      StringOrNumber `json:"askVolume"`
+	BidCount       IntOrEmpty     `json:"bidCount"`
+	AskCount       IntOrEmpty     `json:"askCount"`
 	Direction      Direction      `json:"direction"`
 }
 
+// BidPriceFloat returns the best bid price.
+func (s Stats) BidPriceFloat() float64 { return s.BidPrice.Float() }
+
+// AskPriceFloat returns the best ask price.
+func (s Stats) AskPriceFloat() float64 { return s.AskPrice.Float() }
+
+// LastPriceFloat returns the last traded price.
+func (s Stats) LastPriceFloat() float64 { return s.LastPrice.Float() }
+
+// LastQtyFloat returns the quantity of the last trade.
+func (s Stats) LastQtyFloat() float64 { return s.LastQty.Float() }
+
+// DayVolumeFloat returns the 24h base-asset volume.
+func (s Stats) DayVolumeFloat() float64 { return s.DayVolume.Float() }
+
+// WeekVolumeFloat returns the 7d base-asset volume.
+func (s Stats) WeekVolumeFloat() float64 { return s.WeekVolume.Float() }
+
+// QuoteVolumeDayFloat returns the 24h quote-asset volume.
+func (s Stats) QuoteVolumeDayFloat() float64 { return s.QuoteVolumeDay.Float() }
+
+// HighPriceDayFloat returns the 24h high.
+func (s Stats) HighPriceDayFloat() float64 { return s.HighPriceDay.Float() }
+
+// LowPriceDayFloat returns the 24h low.
+func (s Stats) LowPriceDayFloat() float64 { return s.LowPriceDay.Float() }
+
+// BidVolumeFloat returns the total volume resting on the bid side.
+func (s Stats) BidVolumeFloat() float64 { return s.BidVolume.Float() }
+
+// AskVolumeFloat returns the total volume resting on the ask side.
+func (s Stats) AskVolumeFloat() float64 { return s.AskVolume.Float() }
+
+// MidPrice returns the bid/ask mid-point, or 0 when either side is missing.
+func (s Stats) MidPrice() float64 {
+	bid, ask := s.BidPriceFloat(), s.AskPriceFloat()
+	if bid <= 0 || ask <= 0 {
+		return 0
+	}
+	return (bid + ask) / 2
+}
+
 // SymbolInfo represents the complete metadata of a trading symbol (market pair)
 // on Wallex. This structure includes asset identifiers, precision rules, minimum
 // trading constraints, tick sizes, and statistical market data.
@@ -83,27 +135,59 @@ type Stats struct {
 //   - minQty, maxQty, and minNotional are numeric and may include fractions.
 //   - stats contains real-time 24h/7d market information.
 type SymbolInfo struct {
-	Symbol             string    `json:"symbol"`
-	BaseAsset          string    `json:"baseAsset"`
-	BaseAssetPrecision int8      `json:"baseAssetPrecision"`
-	QuoteAsset         string    `json:"quoteAsset"`
-	QuotePrecision     int8      `json:"quotePrecision"`
-	FaName             string    `json:"faName"`
-	FaBaseAsset        string    `json:"faBaseAsset"`
-	FaQuoteAsset       string    `json:"faQuoteAsset"`
-	StepSize           int64     `json:"stepSize"`
-	TickSize           int64     `json:"tickSize"`
-	MinQty             float64   `json:"minQty"`
-	MinNotional        int64     `json:"minNotional"`
-	Stats              Stats     `json:"stats"`
-	CreatedAt          time.Time `json:"createdAt"`
-	EnName             string    `json:"enName"`
-	EnBaseAsset        string    `json:"enBaseAsset"`
-	EnQuoteAsset       string    `json:"enQuoteAsset"`
-	TmnVolumeDay       string    `json:"24h_tmnVolume"`
-	IsNew              bool      `json:"isNew"`
-	IsZeroFee          bool      `json:"isZeroFee"`
-	IsMarketTypeEnable bool      `json:"isMarketTypeEnable"`
+	Symbol             string         `json:"symbol"`
+	BaseAsset          string         `json:"baseAsset"`
+	BaseAssetPrecision int8           `json:"baseAssetPrecision"`
+	QuoteAsset         string         `json:"quoteAsset"`
+	QuotePrecision     int8           `json:"quotePrecision"`
+	FaName             string         `json:"faName"`
+	FaBaseAsset        string         `json:"faBaseAsset"`
+	FaQuoteAsset       string         `json:"faQuoteAsset"`
+	StepSize           int64          `json:"stepSize"`
+	TickSize           int64          `json:"tickSize"`
+	MinQty             NumericOrEmpty `json:"minQty"`
+	MinNotional        int64          `json:"minNotional"`
+	Stats              Stats          `json:"stats"`
+	CreatedAt          time.Time      `json:"createdAt"`
+	EnName             string         `json:"enName"`
+	EnBaseAsset        string         `json:"enBaseAsset"`
+	EnQuoteAsset       string         `json:"enQuoteAsset"`
+	TmnVolumeDay       StringOrNumber `json:"24h_tmnVolume"`
+	IsNew              bool           `json:"isNew"`
+	IsZeroFee          bool           `json:"isZeroFee"`
+	IsMarketTypeEnable bool           `json:"isMarketTypeEnable"`
+}
+
+// DisplayName returns the market name for display: FaName when persian is
+// true, EnName otherwise, falling back to the other name and then to Symbol
+// when one is empty.
+func (s SymbolInfo) DisplayName(persian bool) string {
+	return displayName(persian, s.FaName, s.EnName, s.Symbol)
+}
+
+// DisplayBaseAsset returns the name of the base asset for display, like
+// DisplayName.
+func (s SymbolInfo) DisplayBaseAsset(persian bool) string {
+	return displayName(persian, s.FaBaseAsset, s.EnBaseAsset, s.BaseAsset)
+}
+
+// DisplayQuoteAsset returns the name of the quote asset for display, like
+// DisplayName.
+func (s SymbolInfo) DisplayQuoteAsset(persian bool) string {
+	return displayName(persian, s.FaQuoteAsset, s.EnQuoteAsset, s.QuoteAsset)
+}
+
+func displayName(persian bool, fa, en, code string) string {
+	if !persian {
+		fa, en = en, fa
+	}
+	switch {
+	case fa != "":
+		return fa
+	case en != "":
+		return en
+	}
+	return code
 }
 
 // Symbols is a container type wrapping a map of symbol identifiers to their
@@ -119,6 +203,56 @@ type MarketInformation struct {
 	Result Symbols `json:"result"`
 }
 
+// Get returns the metadata of a single symbol, e.g. Get("BTCUSDT").
+func (m *MarketInformation) Get(symbol string) (SymbolInfo, bool) {
+	if m == nil {
+		return SymbolInfo{}, false
+	}
+	info, ok := m.Result.Symbols[symbol]
+	return info, ok
+}
+
+// AllSymbols returns every market, sorted by symbol.
+func (m *MarketInformation) AllSymbols() []SymbolInfo {
+	return m.Filter(func(SymbolInfo) bool { return true })
+}
+
+// ByQuoteAsset returns the markets quoted in asset (e.g. "TMN" or "USDT"),
+// sorted by symbol.
+func (m *MarketInformation) ByQuoteAsset(asset string) []SymbolInfo {
+	return m.Filter(func(s SymbolInfo) bool { return s.QuoteAsset == asset })
+}
+
+// ByBaseAsset returns the markets trading asset (e.g. "BTC"), sorted by
+// symbol.
+func (m *MarketInformation) ByBaseAsset(asset string) []SymbolInfo {
+	return m.Filter(func(s SymbolInfo) bool { return s.BaseAsset == asset })
+}
+
+// Filter returns the markets for which keep returns true, sorted by symbol.
+func (m *MarketInformation) Filter(keep func(SymbolInfo) bool) []SymbolInfo {
+	if m == nil {
+		return nil
+	}
+	out := make([]SymbolInfo, 0, len(m.Result.Symbols))
+	for _, info := range m.Result.Symbols {
+		if keep(info) {
+			out = append(out, info)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Symbol < out[j].Symbol })
+	return out
+}
+
+// Each calls fn for every market in symbol order until fn returns false.
+func (m *MarketInformation) Each(fn func(SymbolInfo) bool) {
+	for _, info := range m.AllSymbols() {
+		if !fn(info) {
+			return
+		}
+	}
+}
+
 // Order represents a single order book level (price level).
 // The price and sum fields are number-strings, while quantity is numeric.
 //
@@ -127,9 +261,9 @@ type MarketInformation struct {
 //	GET /v1/depth
 //	GET /v2/depth/all
 type Order struct {
-	Price    float64 `json:"price"`
-	Quantity float64 `json:"quantity"`
-	Sum      string  `json:"sum"`
+	Price    float64        `json:"price"`
+	Quantity NumericOrEmpty `json:"quantity"`
+	Sum      StringOrNumber `json:"sum"`
 }
 
 func (o *Order) UnmarshalJSON(data []byte) error {
@@ -152,12 +286,18 @@ func (o *Order) UnmarshalJSON(data []byte) error {
 	case string:
 		f, err := strconv.ParseFloat(v, 64)
 		if err != nil {
+			if StrictDecoding() && !isPlaceholder(v) {
+				return fmt.Errorf("wallex: cannot decode order price %q", v)
+			}
 			o.Price = 0
 		} else {
 			o.Price = f
 		}
 
 	default:
+		if StrictDecoding() && v != nil {
+			return fmt.Errorf("wallex: cannot decode order price %v", v)
+		}
 		o.Price = 0
 	}
 
@@ -175,6 +315,19 @@ type OrderBook struct {
 	Bid []Order `json:"bid"`
 }
 
+// Top returns a book holding the best n levels of each side of b. The
+// levels are copied, so b's deeper levels can be garbage collected. A
+// non-positive n returns b unchanged.
+func (b OrderBook) Top(n int) OrderBook {
+	if n <= 0 || (len(b.Ask) <= n && len(b.Bid) <= n) {
+		return b
+	}
+	return OrderBook{
+		Ask: append([]Order(nil), b.Ask[:min(n, len(b.Ask))]...),
+		Bid: append([]Order(nil), b.Bid[:min(n, len(b.Bid))]...),
+	}
+}
+
 // Depth wraps an orderbook response for a single market.
 //
 // Response shape:
@@ -190,20 +343,129 @@ type Depth struct {
 // Response shape:
 //
 //	{ "success": true, "result": { "BTCUSDT": { ... }, "ETHUSDT": { ... }, ... } }
+//
+// The books are decoded lazily; see OrderBooks.
 type AllDepths struct {
 	BaseResponse
-	Result map[string]OrderBook `json:"result"`
+	Result OrderBooks `json:"result"`
+}
+
+// OrderBooks holds the per-symbol books of GET /v2/depth/all.
+//
+// Only the outer object is parsed when the response is decoded; each book
+// stays raw until it is first requested through Get or Book and is then
+// cached. Consumers interested in a handful of markets therefore do not pay
+// for decoding hundreds of books. Because of this, malformed books are
+// reported by Book rather than by the initial decode.
+//
+// OrderBooks is safe for concurrent use.
+type OrderBooks struct {
+	mu    *sync.Mutex
+	raw   map[string]json.RawMessage
+	books map[string]OrderBook
+}
+
+// UnmarshalJSON implements json.Unmarshaler. Wallex may send [] or null
+// instead of an empty object.
+func (b *OrderBooks) UnmarshalJSON(data []byte) error {
+	b.mu = new(sync.Mutex)
+	b.books = nil
+	b.raw = nil
+
+	trimmed := bytes.TrimSpace(data)
+	if string(trimmed) == "null" || string(trimmed) == "[]" {
+		return nil
+	}
+	return json.Unmarshal(data, &b.raw)
+}
+
+// MarshalJSON implements json.Marshaler, re-encoding the books as received.
+func (b OrderBooks) MarshalJSON() ([]byte, error) {
+	if b.raw == nil {
+		return []byte("{}"), nil
+	}
+	return json.Marshal(b.raw)
+}
+
+// Len returns the number of symbols in the response.
+func (b *OrderBooks) Len() int {
+	return len(b.raw)
+}
+
+// Has reports whether the response contains a book for symbol, without
+// decoding it.
+func (b *OrderBooks) Has(symbol string) bool {
+	_, ok := b.raw[symbol]
+	return ok
+}
+
+// Symbols returns the symbols in the response in sorted order.
+func (b *OrderBooks) Symbols() []string {
+	out := make([]string, 0, len(b.raw))
+	for s := range b.raw {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Get returns the book of symbol, e.g. Get("BTCUSDT"). It returns false if
+// the symbol is absent or its book cannot be decoded.
+func (b *OrderBooks) Get(symbol string) (OrderBook, bool) {
+	book, err := b.Book(symbol)
+	return book, err == nil
+}
+
+// Book is like Get but reports why the book is unavailable.
+func (b *OrderBooks) Book(symbol string) (OrderBook, error) {
+	raw, ok := b.raw[symbol]
+	if !ok {
+		return OrderBook{}, fmt.Errorf("wallex: no order book for %q", symbol)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if book, ok := b.books[symbol]; ok {
+		return book, nil
+	}
+	var book OrderBook
+	if err := json.Unmarshal(raw, &book); err != nil {
+		return OrderBook{}, fmt.Errorf("wallex: cannot decode order book for %q: %w", symbol, err)
+	}
+	if b.books == nil {
+		b.books = make(map[string]OrderBook)
+	}
+	b.books[symbol] = book
+	return book, nil
+}
+
+// All decodes every book and returns them keyed by symbol. Books that fail
+// to decode are omitted; the first such error is returned.
+func (b *OrderBooks) All() (map[string]OrderBook, error) {
+	out := make(map[string]OrderBook, len(b.raw))
+	var firstErr error
+	for symbol := range b.raw {
+		book, err := b.Book(symbol)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		out[symbol] = book
+	}
+	return out, firstErr
 }
 
 // Trade represents a single executed trade on Wallex, returned in the recent
 // trades endpoint. Prices and quantities are number-strings; timestamp is ISO8601.
 type Trade struct {
-	Symbol     string    `json:"symbol"`     // Symbol the trade belongs to
-	Quantity   string    `json:"quantity"`   // Traded quantity (number-string)
-	Price      string    `json:"price"`      // Trade price (number-string)
-	Sum        string    `json:"sum"`        // price * quantity (number-string)
-	IsBuyOrder bool      `json:"isBuyOrder"` // true = taker was buying
-	Timestamp  time.Time `json:"timestamp"`  // Execution timestamp
+	Symbol     string         `json:"symbol"`     // Symbol the trade belongs to
+	Quantity   StringOrNumber `json:"quantity"`   // Traded quantity (number-string)
+	Price      StringOrNumber `json:"price"`      // Trade price (number-string)
+	Sum        StringOrNumber `json:"sum"`        // price * quantity (number-string)
+	IsBuyOrder bool           `json:"isBuyOrder"` // true = taker was buying
+	Timestamp  WallexTime     `json:"timestamp"`  // Execution timestamp
 }
 
 // LatestTrades wraps the trades array under result.latestTrades.
@@ -216,3 +478,18 @@ type Trades struct {
 	BaseResponse
 	Result LatestTrades `json:"result"`
 }
+
+// Newest returns the timestamp of the most recent trade, or the zero time
+// if there are none.
+func (t *Trades) Newest() time.Time {
+	var newest time.Time
+	if t == nil {
+		return newest
+	}
+	for _, tr := range t.Result.LatestTrades {
+		if tr.Timestamp.After(newest) {
+			newest = tr.Timestamp.Time
+		}
+	}
+	return newest
+}
@@ -0,0 +1,78 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// AssetNetwork describes one blockchain network an asset can be moved on,
+// together with its deposit/withdrawal constraints.
+//
+// Amounts are number-strings denominated in the asset itself.
+type AssetNetwork struct {
+	Network         string `json:"network"`         // Network code, e.g. "TRC20"
+	Name            string `json:"name"`            // Human-readable network name
+	DepositEnabled  bool   `json:"depositEnable"`   // Deposits currently accepted
+	WithdrawEnabled bool   `json:"withdrawEnable"`  // Withdrawals currently accepted
+	WithdrawMin     string `json:"withdrawMin"`     // Minimum withdrawal amount
+	WithdrawMax     string `json:"withdrawMax"`     // Maximum withdrawal amount ("0" = no limit)
+	WithdrawFee     string `json:"withdrawFee"`     // Flat fee deducted from each withdrawal
+	MinConfirm      int    `json:"minConfirm"`      // Confirmations before a deposit is credited
+	AddressRegex    string `json:"addressRegex"`    // Pattern valid addresses must match
+	MemoRequired    bool   `json:"memoRequired"`    // Whether a memo/tag is mandatory
+	IsDefault       bool   `json:"isDefault"`       // Network preselected by Wallex
+	Description     string `json:"withdrawDesc"`    // Free-form notes from Wallex
+	ContractAddress string `json:"contractAddress"` // Token contract, if any
+}
+
+// CheckWithdrawal reports whether amount may be withdrawn over this network,
+// returning a descriptive error when a constraint is violated.
+func (n AssetNetwork) CheckWithdrawal(amount string) error {
+	if !n.WithdrawEnabled {
+		return fmt.Errorf("withdrawals on network %s are disabled", n.Network)
+	}
+
+	amt, err := strconv.ParseFloat(amount, 64)
+	if err != nil {
+		return fmt.Errorf("invalid withdrawal amount %q: %w", amount, err)
+	}
+	fee, _ := strconv.ParseFloat(n.WithdrawFee, 64)
+	if amt <= fee {
+		return fmt.Errorf("withdrawal amount %s does not cover the %s network fee of %s", amount, n.Network, n.WithdrawFee)
+	}
+	if lo, err := strconv.ParseFloat(n.WithdrawMin, 64); err == nil && amt < lo {
+		return fmt.Errorf("withdrawal amount %s is below the %s minimum of %s", amount, n.Network, n.WithdrawMin)
+	}
+	if hi, err := strconv.ParseFloat(n.WithdrawMax, 64); err == nil && hi > 0 && amt > hi {
+		return fmt.Errorf("withdrawal amount %s exceeds the %s maximum of %s", amount, n.Network, n.WithdrawMax)
+	}
+	return nil
+}
+
+// AssetNetworks lists the supported networks of one asset.
+type AssetNetworks struct {
+	Asset    string         `json:"asset"`
+	Networks []AssetNetwork `json:"networks"`
+}
+
+// Get returns the network with the given code.
+func (a AssetNetworks) Get(network string) (AssetNetwork, bool) {
+	for _, n := range a.Networks {
+		if n.Network == network {
+			return n, true
+		}
+	}
+	return AssetNetwork{}, false
+}
+
+// AssetNetworksResponse wraps the response of:
+//
+//	GET /v1/account/networks?asset={ASSET}
+//
+// Response shape:
+//
+//	{ "success": true, "result": { "asset": "USDT", "networks": [ ... ] } }
+type AssetNetworksResponse struct {
+	BaseResponse
+	Result AssetNetworks `json:"result"`
+}
@@ -1,10 +1,41 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"sync/atomic"
 )
 
+var strictDecoding atomic.Bool
+
+// SetStrictDecoding toggles strict decoding for the tolerant numeric types
+// (NumericOrEmpty, IntOrEmpty, StringOrNumber) and the types built on them.
+//
+// By default (tolerant mode) values that cannot be interpreted as numbers
+// decode to zero so one malformed field cannot fail a whole response. In
+// strict mode such values produce a decode error instead. The documented
+// Wallex placeholders "-", "" and null are accepted in both modes.
+//
+// The setting is process-wide and safe to change concurrently.
+func SetStrictDecoding(strict bool) {
+	strictDecoding.Store(strict)
+}
+
+// StrictDecoding reports whether strict decoding is enabled.
+func StrictDecoding() bool {
+	return strictDecoding.Load()
+}
+
+// isPlaceholder reports whether s is a Wallex "no value" placeholder.
+func isPlaceholder(s string) bool {
+	return s == "" || s == "-"
+}
+
+// NumericOrEmpty is a float64 that tolerates Wallex's loose number encoding:
+// JSON numbers, number-strings, and the "-", "" and null placeholders (which
+// decode to zero).
 type NumericOrEmpty float64
 
 func (n *NumericOrEmpty) UnmarshalJSON(data []byte) error {
@@ -15,11 +46,16 @@ func (n *NumericOrEmpty) UnmarshalJSON(data []byte) error {
 		return nil
 	}
 
+	if bytes.Equal(data, []byte("null")) {
+		*n = 0
+		return nil
+	}
+
 	// Try string
 	var s string
 	if err := json.Unmarshal(data, &s); err == nil {
 		// "-" or empty → treat as zero
-		if s == "-" {
+		if isPlaceholder(s) {
 			*n = 0
 			return nil
 		}
@@ -30,12 +66,100 @@ func (n *NumericOrEmpty) UnmarshalJSON(data []byte) error {
 			return nil
 		}
 
+		if StrictDecoding() {
+			return fmt.Errorf("wallex: cannot decode %q as a number", s)
+		}
+
 		// Fallback
 		*n = 0
 		return nil
 	}
 
+	if StrictDecoding() {
+		return fmt.Errorf("wallex: cannot decode %s as a number", data)
+	}
+
 	// Final fallback
 	*n = 0
 	return nil
 }
+
+// Float64 returns the value as a float64.
+func (n NumericOrEmpty) Float64() float64 {
+	return float64(n)
+}
+
+// IntOrEmpty is an integer that tolerates Wallex placeholder values.
+//
+// It accepts JSON numbers (including ones encoded as floats, e.g. 12.0),
+// number-strings, and the "-" or "" placeholders Wallex returns for markets
+// without activity, which decode to zero. It is 64 bits wide so counters on
+// busy markets cannot overflow.
+type IntOrEmpty int64
+
+func (n *IntOrEmpty) UnmarshalJSON(data []byte) error {
+	var f NumericOrEmpty
+	if err := f.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*n = IntOrEmpty(f)
+	return nil
+}
+
+// StringOrNumber is a number-string that also accepts bare JSON numbers.
+//
+// Wallex documents most prices and amounts as number-strings but sometimes
+// sends numbers or null for the same fields. The raw text is preserved
+// exactly, so no precision is lost; Float parses it on demand. Placeholders
+// ("-", "") are kept verbatim and parse as zero. In strict mode any other
+// non-numeric string is a decode error.
+type StringOrNumber string
+
+func (s *StringOrNumber) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*s = ""
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err == nil {
+		if StrictDecoding() && !isPlaceholder(str) {
+			if _, err := strconv.ParseFloat(str, 64); err != nil {
+				return fmt.Errorf("wallex: cannot decode %q as a number", str)
+			}
+		}
+		*s = StringOrNumber(str)
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err == nil {
+		*s = StringOrNumber(num.String())
+		return nil
+	}
+
+	if StrictDecoding() {
+		return fmt.Errorf("wallex: cannot decode %s as a number", data)
+	}
+	*s = ""
+	return nil
+}
+
+// String returns the raw number-string.
+func (s StringOrNumber) String() string {
+	return string(s)
+}
+
+// Float returns the parsed value, or 0 for placeholders and invalid input.
+func (s StringOrNumber) Float() float64 {
+	f, err := strconv.ParseFloat(string(s), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// IsZero reports whether the value is zero or a placeholder.
+func (s StringOrNumber) IsZero() bool {
+	return s.Float() == 0
+}
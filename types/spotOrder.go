@@ -1,6 +1,33 @@
 package types
 
-import "time"
+import (
+	"encoding/json"
+	"sort"
+	"time"
+	"unicode/utf8"
+)
+
+// Order sides accepted by CreateOrderParams.Side and returned in BaseOrder.Side.
+const (
+	SideBuy  = "BUY"
+	SideSell = "SELL"
+)
+
+// Order types accepted by CreateOrderParams.Type and returned in BaseOrder.Type.
+const (
+	OrderTypeLimit  = "LIMIT"
+	OrderTypeMarket = "MARKET"
+)
+
+// Order statuses reported by Wallex in BaseOrder.Status and CancelOrder.Status.
+const (
+	OrderStatusNew             = "NEW"
+	OrderStatusPartiallyFilled = "PARTIALLY_FILLED"
+	OrderStatusFilled          = "FILLED"
+	OrderStatusCanceled        = "CANCELED"
+	OrderStatusRejected        = "REJECTED"
+	OrderStatusExpired         = "EXPIRED"
+)
 
 // BaseOrder represents a single user order as returned by the Wallex account
 // order endpoints. This model appears in create-order responses, open-orders
@@ -8,7 +35,9 @@ import "time"
 //
 // This structure mirrors Wallex's data model for both active and historical
 // user orders. All numeric values such as price, quantities, sums are returned
-// as **number-strings**.
+// as **number-strings**. Timestamps are decoded with WallexTime, so
+// transactTime (unix milliseconds) and created_at (ISO8601) are both exposed
+// as time values.
 //
 // Endpoint examples:
 //
@@ -29,20 +58,38 @@ import "time"
 //	  "created_at": "2022-06-17T11:53:02Z"
 //	}
 type BaseOrder struct {
-	Symbol          string    `json:"symbol"`
-	Type            string    `json:"type"`
-	Side            string    `json:"side"`
-	Price           string    `json:"price"`
-	OrigQty         string    `json:"origQty"`
-	OrigSum         string    `json:"origSum"`
-	ExecutedPrice   string    `json:"executedPrice"`
-	ExecutedQty     string    `json:"executedQty"`
-	ExecutedSum     string    `json:"executedSum"`
-	ExecutedPercent float64   `json:"executedPercent"`
-	Status          string    `json:"status"`
-	Active          bool      `json:"active"`
-	ClientOrderId   string    `json:"clientOrderId"`
-	CreatedAt       time.Time `json:"created_at"`
+	Symbol          string         `json:"symbol"`
+	Type            string         `json:"type"`
+	Side            string         `json:"side"`
+	Price           StringOrNumber `json:"price"`
+	OrigQty         StringOrNumber `json:"origQty"`
+	OrigSum         StringOrNumber `json:"origSum"`
+	ExecutedPrice   StringOrNumber `json:"executedPrice"`
+	ExecutedQty     StringOrNumber `json:"executedQty"`
+	ExecutedSum     StringOrNumber `json:"executedSum"`
+	ExecutedPercent float64        `json:"executedPercent"`
+	Status          string         `json:"status"`
+	Active          bool           `json:"active"`
+	ClientOrderId   string         `json:"clientOrderId"`
+	Fills           []Fill         `json:"fills"`
+	TransactTime    WallexTime     `json:"transactTime"`
+	CreatedAt       WallexTime     `json:"created_at"`
+}
+
+// RemainingQty returns the quantity still open on the book:
+// origQty - executedQty, never negative.
+func (o BaseOrder) RemainingQty() float64 {
+	rem := o.OrigQty.Float() - o.ExecutedQty.Float()
+	if rem < 0 {
+		return 0
+	}
+	return rem
+}
+
+// OpenNotional returns the quote value of the remaining quantity at the
+// order price. MARKET orders, which carry no price, report zero.
+func (o BaseOrder) OpenNotional() float64 {
+	return o.RemainingQty() * o.Price.Float()
 }
 
 // BaseOrderResponse wraps a single order object returned by Wallex.
@@ -81,6 +128,45 @@ type CreateOrderParams struct {
 	ClientOrderId string `json:"clientOrderId,omitempty"`
 }
 
+// MarshalJSON encodes the params without reflection. Order submission is
+// latency sensitive, so the payload is appended into a single buffer sized
+// up front; the output is identical to the struct-tag encoding.
+func (p CreateOrderParams) MarshalJSON() ([]byte, error) {
+	n := 70 + len(p.Symbol) + len(p.Type) + len(p.Side) + len(p.Price) + len(p.Quantity) + len(p.ClientOrderId)
+	b := make([]byte, 0, n)
+	b = append(b, `{"symbol":`...)
+	b = appendJSONString(b, p.Symbol)
+	b = append(b, `,"type":`...)
+	b = appendJSONString(b, p.Type)
+	b = append(b, `,"side":`...)
+	b = appendJSONString(b, p.Side)
+	b = append(b, `,"price":`...)
+	b = appendJSONString(b, p.Price)
+	b = append(b, `,"quantity":`...)
+	b = appendJSONString(b, p.Quantity)
+	if p.ClientOrderId != "" {
+		b = append(b, `,"clientOrderId":`...)
+		b = appendJSONString(b, p.ClientOrderId)
+	}
+	return append(b, '}'), nil
+}
+
+// appendJSONString appends s as a JSON string. Plain ASCII, which covers
+// symbols, numbers and client order ids, is copied directly; anything else
+// goes through encoding/json for correct escaping.
+func appendJSONString(b []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < 0x20 || c == '"' || c == '\\' || c == '<' || c == '>' || c == '&' || c >= utf8.RuneSelf {
+			q, _ := json.Marshal(s)
+			return append(b, q...)
+		}
+	}
+	b = append(b, '"')
+	b = append(b, s...)
+	return append(b, '"')
+}
+
 // OpenOrdersResponse contains all currently active (open) user orders.
 // Returned by:
 //
@@ -101,6 +187,62 @@ type OpenOrdersResponse struct {
 	} `json:"result"`
 }
 
+// BySymbol partitions the open orders by market symbol.
+func (r *OpenOrdersResponse) BySymbol() map[string][]BaseOrder {
+	out := make(map[string][]BaseOrder)
+	if r == nil {
+		return out
+	}
+	for _, o := range r.Result.Orders {
+		out[o.Symbol] = append(out[o.Symbol], o)
+	}
+	return out
+}
+
+// Symbols returns the sorted set of symbols with at least one open order.
+func (r *OpenOrdersResponse) Symbols() []string {
+	bySymbol := r.BySymbol()
+	out := make([]string, 0, len(bySymbol))
+	for s := range bySymbol {
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// TotalOpenNotional sums OpenNotional over the open orders of side
+// (SideBuy or SideSell); an empty side includes both.
+//
+// The sum spans all returned symbols and is therefore only meaningful when
+// they share a quote asset; use OpenNotionalBySymbol otherwise.
+func (r *OpenOrdersResponse) TotalOpenNotional(side string) float64 {
+	if r == nil {
+		return 0
+	}
+	var total float64
+	for _, o := range r.Result.Orders {
+		if side == "" || o.Side == side {
+			total += o.OpenNotional()
+		}
+	}
+	return total
+}
+
+// OpenNotionalBySymbol is like TotalOpenNotional but keeps the sums per
+// symbol.
+func (r *OpenOrdersResponse) OpenNotionalBySymbol(side string) map[string]float64 {
+	out := make(map[string]float64)
+	if r == nil {
+		return out
+	}
+	for _, o := range r.Result.Orders {
+		if side == "" || o.Side == side {
+			out[o.Symbol] += o.OpenNotional()
+		}
+	}
+	return out
+}
+
 // UserTradesParams defines the query parameters for retrieving private trade
 // history from the Wallex account trade endpoint.
 //
@@ -108,10 +250,14 @@ type OpenOrdersResponse struct {
 //
 //	GET /v1/account/trades
 //
-// Both fields are optional. If provided, filtering is applied server-side.
+// All fields are optional. If provided, filtering is applied server-side.
+// Page and PerPage select a page of the history; zero values use the server
+// defaults.
 type UserTradesParams struct {
-	Symbol string `json:"symbol"`
-	Side   string `json:"side"`
+	Symbol  string `json:"symbol" url:"symbol,omitempty"`
+	Side    string `json:"side" url:"side,omitempty"`
+	Page    int    `json:"page" url:"page,omitempty"`
+	PerPage int    `json:"per_page" url:"per_page,omitempty"`
 }
 
 // UserTrade represents a trade execution belonging to the authenticated user.
@@ -121,15 +267,15 @@ type UserTradesParams struct {
 //
 // Unlike the public trades endpoint, this includes fee information.
 type UserTrade struct {
-	Symbol         string    `json:"symbol"`         // Market symbol
-	Quantity       string    `json:"quantity"`       // Executed amount (string number)
-	Price          string    `json:"price"`          // Executed price
-	Sum            string    `json:"sum"`            // price * quantity
-	Fee            string    `json:"fee"`            // Exact fee paid
-	FeeCoefficient string    `json:"feeCoefficient"` // Fee rate (e.g. "0.001")
-	FeeAsset       string    `json:"feeAsset"`       // Asset fee was deducted in
-	IsBuyer        bool      `json:"isBuyer"`        // true if user was the buyer
-	Timestamp      time.Time `json:"timestamp"`      // Execution timestamp
+	Symbol         string         `json:"symbol"`         // Market symbol
+	Quantity       StringOrNumber `json:"quantity"`       // Executed amount (string number)
+	Price          StringOrNumber `json:"price"`          // Executed price
+	Sum            StringOrNumber `json:"sum"`            // price * quantity
+	Fee            StringOrNumber `json:"fee"`            // Exact fee paid
+	FeeCoefficient StringOrNumber `json:"feeCoefficient"` // Fee rate (e.g. "0.001")
+	FeeAsset       string         `json:"feeAsset"`       // Asset fee was deducted in
+	IsBuyer        bool           `json:"isBuyer"`        // true if user was the buyer
+	Timestamp      time.Time      `json:"timestamp"`      // Execution timestamp
 }
 
 // UserTradesResponse wraps the array of account trade executions returned by:
@@ -142,11 +288,47 @@ type UserTrade struct {
 //	  "success": true,
 //	  "result": {
 //	    "accountLatestTrades": [ ...list of UserTrade... ]
-//	  }
+//	  },
+//	  "result_info": { "page": 1, "per_page": 50, "total_count": 120 }
 //	}
+//
+// ResultInfo is zero when the server does not paginate the response.
 type UserTradesResponse struct {
 	BaseResponse
 	Result struct {
 		AccountLatestTrades []UserTrade `json:"accountLatestTrades"`
 	} `json:"result"`
+	ResultInfo PageInfo `json:"result_info"`
+}
+
+// OrderHistoryParams defines the query parameters for retrieving closed and
+// open orders of the account from:
+//
+//	GET /v1/account/orders/history
+//
+// All fields are optional.
+type OrderHistoryParams struct {
+	Symbol  string `json:"symbol" url:"symbol,omitempty"`
+	Side    string `json:"side" url:"side,omitempty"`
+	Page    int    `json:"page" url:"page,omitempty"`
+	PerPage int    `json:"per_page" url:"per_page,omitempty"`
+}
+
+// OrderHistoryResponse wraps a page of account orders returned by:
+//
+//	GET /v1/account/orders/history
+//
+// Response shape:
+//
+//	{
+//	  "success": true,
+//	  "result": { "orders": [ ...list of BaseOrder... ] },
+//	  "result_info": { "page": 1, "per_page": 50, "total_count": 120 }
+//	}
+type OrderHistoryResponse struct {
+	BaseResponse
+	Result struct {
+		Orders []BaseOrder `json:"orders"`
+	} `json:"result"`
+	ResultInfo PageInfo `json:"result_info"`
 }
@@ -1,23 +1,134 @@
 package types
 
+import (
+	"encoding/json"
+	"sort"
+)
+
 // Balance represents a user’s wallet entry for a specific currency,
 // including available and blocked balances.
+//
+// Value is the total balance of the asset and Locked the part of it reserved
+// by open orders or pending withdrawals; both are number-strings.
 type Balance struct {
-	Asset  string `json:"asset"`
-	FaName string `json:"faName"`
-	Fiat   bool   `json:"fiat"`
-	Value  string `json:"value"`
-	Locked string `json:"locked"`
+	Asset  string         `json:"asset"`
+	FaName string         `json:"faName"`
+	Fiat   bool           `json:"fiat"`
+	Value  StringOrNumber `json:"value"`
+	Locked StringOrNumber `json:"locked"`
+}
+
+// DisplayName returns FaName when persian is true and it is set, and Asset
+// otherwise.
+func (b Balance) DisplayName(persian bool) string {
+	if persian && b.FaName != "" {
+		return b.FaName
+	}
+	return b.Asset
+}
+
+// Total returns the full balance, including locked funds.
+func (b Balance) Total() float64 {
+	return b.Value.Float()
+}
+
+// LockedAmount returns the balance reserved by open orders and withdrawals.
+func (b Balance) LockedAmount() float64 {
+	return b.Locked.Float()
+}
+
+// Available returns the balance that can be traded or withdrawn right now.
+func (b Balance) Available() float64 {
+	avail := b.Total() - b.LockedAmount()
+	if avail < 0 {
+		return 0
+	}
+	return avail
 }
 
 // Balances defines a map struct of asset: balance
+//
+// Wallex returns balances as an object keyed by asset. Responses that carry
+// them as an array, or with entries missing their "asset" field, are
+// normalized so Balances is always keyed by asset code and every Balance has
+// Asset set.
 type Balances struct {
 	Balances map[string]Balance `json:"balances"`
 }
 
+func (b *Balances) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Balances json.RawMessage `json:"balances"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	b.Balances = make(map[string]Balance)
+	if len(raw.Balances) == 0 || string(raw.Balances) == "null" {
+		return nil
+	}
+
+	var byAsset map[string]Balance
+	if err := json.Unmarshal(raw.Balances, &byAsset); err == nil {
+		for asset, bal := range byAsset {
+			if bal.Asset == "" {
+				bal.Asset = asset
+			}
+			b.Balances[bal.Asset] = bal
+		}
+		return nil
+	}
+
+	var list []Balance
+	if err := json.Unmarshal(raw.Balances, &list); err != nil {
+		return err
+	}
+	for _, bal := range list {
+		b.Balances[bal.Asset] = bal
+	}
+	return nil
+}
+
 // Wallets represents a collection of wallet entries,
 // keyed by currency symbol and grouped under a Balances field under Result.
+//
+// Response shape (GET /v1/account/balances):
+//
+//	{
+//	  "success": true,
+//	  "result": {
+//	    "balances": {
+//	      "USDT": { "asset": "USDT", "fiat": false, "value": "512.44", "locked": "0" }
+//	    }
+//	  }
+//	}
 type Wallets struct {
 	BaseResponse
 	Result Balances `json:"result"`
 }
+
+// Get returns the balance of asset. A missing asset yields a zero Balance and
+// false.
+func (w *Wallets) Get(asset string) (Balance, bool) {
+	if w == nil {
+		return Balance{}, false
+	}
+	b, ok := w.Result.Balances[asset]
+	return b, ok
+}
+
+// NonZero returns all balances with a positive total, sorted by asset.
+func (w *Wallets) NonZero() []Balance {
+	if w == nil {
+		return nil
+	}
+	out := make([]Balance, 0, len(w.Result.Balances))
+	for _, b := range w.Result.Balances {
+		if b.Total() > 0 {
+			out = append(out, b)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Asset < out[j].Asset })
+	return out
+}
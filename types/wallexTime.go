@@ -0,0 +1,117 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// wallexTimeLayouts lists the string timestamp formats observed in Wallex
+// responses, tried in order.
+var wallexTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// WallexTime is a time.Time that decodes every timestamp format Wallex uses:
+//
+//   - ISO8601/RFC3339 strings, with or without fraction and zone
+//     ("2024-05-12T10:41:05Z", "2021-06-16T09:50:41.000000Z")
+//   - "2024-05-12 10:41:05" style strings (interpreted as UTC)
+//   - unix timestamps in milliseconds, as numbers or numeric strings
+//     (e.g. transactTime); values below 1e11 are taken as seconds
+//   - null, "" and "-", which decode to the zero time
+//
+// The embedded time.Time gives direct access to all time methods. It encodes
+// back to JSON as an RFC3339 string, or null when zero.
+type WallexTime struct {
+	time.Time
+}
+
+// NewWallexTime wraps t.
+func NewWallexTime(t time.Time) WallexTime {
+	return WallexTime{Time: t}
+}
+
+func (w *WallexTime) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		w.Time = time.Time{}
+		return nil
+	}
+
+	var num json.Number
+	if err := json.Unmarshal(data, &num); err == nil {
+		return w.setUnix(num.String())
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return w.fail(string(data))
+	}
+	s = strings.TrimSpace(s)
+	if isPlaceholder(s) {
+		w.Time = time.Time{}
+		return nil
+	}
+	if isDigits(s) {
+		return w.setUnix(s)
+	}
+
+	for _, layout := range wallexTimeLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			w.Time = parsed
+			return nil
+		}
+	}
+	return w.fail(s)
+}
+
+func (w WallexTime) MarshalJSON() ([]byte, error) {
+	if w.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(w.Time.Format(time.RFC3339Nano))
+}
+
+func (w *WallexTime) setUnix(s string) error {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return w.fail(s)
+	}
+	if v == 0 {
+		w.Time = time.Time{}
+		return nil
+	}
+	ms := int64(v)
+	if v < 1e11 {
+		ms = int64(v * 1000)
+	}
+	w.Time = time.UnixMilli(ms).UTC()
+	return nil
+}
+
+func (w *WallexTime) fail(raw string) error {
+	if StrictDecoding() {
+		return fmt.Errorf("wallex: cannot decode %q as a timestamp", raw)
+	}
+	w.Time = time.Time{}
+	return nil
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
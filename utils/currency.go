@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Currency codes for Iranian fiat units.
+//
+// Wallex quotes all fiat markets in Toman (TMN). Banks and most accounting
+// systems use the Rial (IRR). One Toman is ten Rials.
+const (
+	TMN = "TMN"
+	IRR = "IRR"
+
+	// RialsPerToman is the fixed TMN → IRR conversion factor.
+	RialsPerToman = 10
+)
+
+// TomanToRial converts an amount in Toman into Rial.
+func TomanToRial(toman float64) float64 {
+	return toman * RialsPerToman
+}
+
+// RialToToman converts an amount in Rial into Toman.
+func RialToToman(rial float64) float64 {
+	return rial / RialsPerToman
+}
+
+// ConvertFiat converts amount between TMN and IRR.
+//
+// It returns false if either unit is not TMN or IRR. Converting a unit to
+// itself returns the amount unchanged.
+func ConvertFiat(amount float64, from, to string) (float64, bool) {
+	switch {
+	case from == to && (from == TMN || from == IRR):
+		return amount, true
+	case from == TMN && to == IRR:
+		return TomanToRial(amount), true
+	case from == IRR && to == TMN:
+		return RialToToman(amount), true
+	}
+	return 0, false
+}
+
+// FiatRate returns the value of one unit of from expressed in to, for the
+// TMN/IRR pair. It returns false for any other pair.
+func FiatRate(from, to string) (float64, bool) {
+	return ConvertFiat(1, from, to)
+}
+
+var persianDigits = strings.NewReplacer(
+	"0", "۰", "1", "۱", "2", "۲", "3", "۳", "4", "۴",
+	"5", "۵", "6", "۶", "7", "۷", "8", "۸", "9", "۹",
+	".", "٫", ",", "٬",
+)
+
+var latinDigits = strings.NewReplacer(
+	"۰", "0", "۱", "1", "۲", "2", "۳", "3", "۴", "4",
+	"۵", "5", "۶", "6", "۷", "7", "۸", "8", "۹", "9",
+	"٠", "0", "١", "1", "٢", "2", "٣", "3", "٤", "4",
+	"٥", "5", "٦", "6", "٧", "7", "٨", "8", "٩", "9",
+	"٫", ".", "٬", ",",
+)
+
+// ToPersianDigits replaces ASCII digits with Persian digits, and the decimal
+// point and thousands separator with their Persian forms (٫ and ٬).
+func ToPersianDigits(s string) string {
+	return persianDigits.Replace(s)
+}
+
+// ToLatinDigits is the inverse of ToPersianDigits. Arabic-Indic digits are
+// converted as well, so user input from either keyboard layout can be parsed.
+func ToLatinDigits(s string) string {
+	return latinDigits.Replace(s)
+}
+
+// FormatAmount formats amount with the given number of decimals and comma
+// thousands separators, e.g. FormatAmount(1234567.5, 0, false) → "1,234,568".
+// When persian is true the result uses Persian digits and separators.
+func FormatAmount(amount float64, decimals int, persian bool) string {
+	s := strconv.FormatFloat(amount, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+
+	intPart, frac := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, frac = s[:i], s[i:]
+	}
+
+	var b strings.Builder
+	for i, r := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(r)
+	}
+
+	out := sign + b.String() + frac
+	if persian {
+		return ToPersianDigits(out)
+	}
+	return out
+}
+
+// FormatFiat formats an amount given in Toman for display in unit (TMN or
+// IRR), without decimals, e.g. FormatFiat(12500, IRR, false) → "125,000".
+// Unknown units are formatted as Toman.
+func FormatFiat(toman float64, unit string, persian bool) string {
+	if v, ok := ConvertFiat(toman, TMN, unit); ok {
+		toman = v
+	}
+	return FormatAmount(toman, 0, persian)
+}
@@ -0,0 +1,66 @@
+package utils
+
+import "testing"
+
+func TestConvertFiat(t *testing.T) {
+	cases := []struct {
+		amount   float64
+		from, to string
+		want     float64
+		ok       bool
+	}{
+		{12500, TMN, IRR, 125000, true},
+		{125000, IRR, TMN, 12500, true},
+		{42, TMN, TMN, 42, true},
+		{42, IRR, IRR, 42, true},
+		{42, "USDT", TMN, 0, false},
+		{42, "USDT", "USDT", 0, false},
+	}
+	for _, tc := range cases {
+		got, ok := ConvertFiat(tc.amount, tc.from, tc.to)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("ConvertFiat(%g, %s, %s) = %g, %v, want %g, %v", tc.amount, tc.from, tc.to, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	cases := []struct {
+		amount   float64
+		decimals int
+		persian  bool
+		want     string
+	}{
+		{0, 0, false, "0"},
+		{999, 0, false, "999"},
+		{1000, 0, false, "1,000"},
+		{1234567.5, 0, false, "1,234,568"},
+		{-1234567.25, 2, false, "-1,234,567.25"},
+		{1234567.25, 2, true, "۱٬۲۳۴٬۵۶۷٫۲۵"},
+	}
+	for _, tc := range cases {
+		if got := FormatAmount(tc.amount, tc.decimals, tc.persian); got != tc.want {
+			t.Errorf("FormatAmount(%g, %d, %v) = %q, want %q", tc.amount, tc.decimals, tc.persian, got, tc.want)
+		}
+	}
+}
+
+func TestFormatFiat(t *testing.T) {
+	if got := FormatFiat(12500, IRR, false); got != "125,000" {
+		t.Errorf("FormatFiat(12500, IRR) = %q", got)
+	}
+	if got := FormatFiat(12500, TMN, true); got != "۱۲٬۵۰۰" {
+		t.Errorf("FormatFiat(12500, TMN, persian) = %q", got)
+	}
+}
+
+func TestToLatinDigits(t *testing.T) {
+	for _, in := range []string{"۱۲۳٫۴۵", "١٢٣٫٤٥", "123.45"} {
+		if got := ToLatinDigits(in); got != "123.45" {
+			t.Errorf("ToLatinDigits(%q) = %q, want 123.45", in, got)
+		}
+	}
+	if got := ToLatinDigits(ToPersianDigits("1,234.5")); got != "1,234.5" {
+		t.Errorf("round trip = %q", got)
+	}
+}
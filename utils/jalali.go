@@ -0,0 +1,234 @@
+package utils
+
+import (
+	"fmt"
+	"time"
+)
+
+// Tehran is the Iran Standard Time zone. It is loaded from the system time
+// zone database and falls back to a fixed UTC+03:30 zone, which is exact
+// since Iran abolished daylight saving time in 2022.
+var Tehran = loadTehran()
+
+func loadTehran() *time.Location {
+	if loc, err := time.LoadLocation("Asia/Tehran"); err == nil {
+		return loc
+	}
+	return time.FixedZone("IRST", 3*3600+30*60)
+}
+
+// JalaliMonths are the Persian names of the Jalali (Shamsi) months, from
+// Farvardin to Esfand.
+var JalaliMonths = [12]string{
+	"فروردین", "اردیبهشت", "خرداد", "تیر", "مرداد", "شهریور",
+	"مهر", "آبان", "آذر", "دی", "بهمن", "اسفند",
+}
+
+// JalaliDate is a date of the Jalali (Solar Hijri) calendar used in Iran.
+// Month and Day are 1-based.
+type JalaliDate struct {
+	Year  int
+	Month int
+	Day   int
+}
+
+// String formats the date as YYYY/MM/DD, e.g. "1403/01/01".
+func (d JalaliDate) String() string {
+	return fmt.Sprintf("%04d/%02d/%02d", d.Year, d.Month, d.Day)
+}
+
+// MonthName returns the Persian name of the month, or "" if Month is out of
+// range.
+func (d JalaliDate) MonthName() string {
+	if d.Month < 1 || d.Month > 12 {
+		return ""
+	}
+	return JalaliMonths[d.Month-1]
+}
+
+// IsLeapJalaliYear reports whether the Jalali year has 366 days, Esfand
+// then having 30 days instead of 29.
+func IsLeapJalaliYear(year int) bool {
+	leap, _, _ := jalaliCalendar(year)
+	return leap == 0
+}
+
+// JalaliMonthDays returns the number of days of a Jalali month.
+func JalaliMonthDays(year, month int) int {
+	switch {
+	case month <= 6:
+		return 31
+	case month <= 11:
+		return 30
+	case IsLeapJalaliYear(year):
+		return 30
+	}
+	return 29
+}
+
+// ToJalali returns the Jalali date of t in t's location. Use t.In(Tehran)
+// for the date as seen in Iran.
+func ToJalali(t time.Time) JalaliDate {
+	day := dayNumber(t.Year(), t.Month(), t.Day())
+
+	gy := t.Year()
+	jy := gy - 621
+	leap, _, march := jalaliCalendar(jy)
+	k := day - dayNumber(gy, time.March, march)
+	if k >= 0 {
+		if k <= 185 {
+			return JalaliDate{Year: jy, Month: 1 + k/31, Day: k%31 + 1}
+		}
+		k -= 186
+	} else {
+		jy--
+		k += 179
+		if leap == 1 {
+			k++
+		}
+	}
+	return JalaliDate{Year: jy, Month: 7 + k/30, Day: k%30 + 1}
+}
+
+// FromJalali returns midnight of the Jalali date in loc. Out-of-range
+// months and days are normalized the way time.Date normalizes them, e.g.
+// day 32 of Farvardin is 1 Ordibehesht.
+func FromJalali(year, month, day int, loc *time.Location) time.Time {
+	month--
+	year += month / 12
+	month %= 12
+	if month < 0 {
+		year--
+		month += 12
+	}
+	_, gy, march := jalaliCalendar(year)
+	offset := month*31 - (month+1)/7*(month-6) + day - 1
+	return time.Date(gy, time.March, march+offset, 0, 0, 0, 0, loc)
+}
+
+// Time returns midnight of the date in loc.
+func (d JalaliDate) Time(loc *time.Location) time.Time {
+	return FromJalali(d.Year, d.Month, d.Day, loc)
+}
+
+// ParseJalali parses a date written as YYYY/MM/DD or YYYY-MM-DD, in Latin
+// or Persian digits, and returns midnight of it in loc.
+func ParseJalali(s string, loc *time.Location) (time.Time, error) {
+	var d JalaliDate
+	s = ToLatinDigits(s)
+	if _, err := fmt.Sscanf(s, "%d/%d/%d", &d.Year, &d.Month, &d.Day); err != nil {
+		if _, err := fmt.Sscanf(s, "%d-%d-%d", &d.Year, &d.Month, &d.Day); err != nil {
+			return time.Time{}, fmt.Errorf("invalid Jalali date %q", s)
+		}
+	}
+	if d.Month < 1 || d.Month > 12 || d.Day < 1 || d.Day > JalaliMonthDays(d.Year, d.Month) {
+		return time.Time{}, fmt.Errorf("invalid Jalali date %q", s)
+	}
+	return d.Time(loc), nil
+}
+
+// FormatJalali formats t, in Tehran time, as "YYYY/MM/DD HH:MM:SS". When
+// persian is true the result uses Persian digits.
+func FormatJalali(t time.Time, persian bool) string {
+	t = t.In(Tehran)
+	s := fmt.Sprintf("%s %02d:%02d:%02d", ToJalali(t), t.Hour(), t.Minute(), t.Second())
+	if persian {
+		return ToPersianDigits(s)
+	}
+	return s
+}
+
+// FormatJalaliDate formats the date of t, in Tehran time, in long form,
+// e.g. "۱ فروردین ۱۴۰۳" with persian, "1 فروردین 1403" without.
+func FormatJalaliDate(t time.Time, persian bool) string {
+	d := ToJalali(t.In(Tehran))
+	s := fmt.Sprintf("%d %s %d", d.Day, d.MonthName(), d.Year)
+	if persian {
+		return ToPersianDigits(s)
+	}
+	return s
+}
+
+// dayNumber returns the number of days from the Unix epoch to a Gregorian
+// date.
+func dayNumber(year int, month time.Month, day int) int {
+	return int(time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// jalaliBreaks are the Jalali years starting a new 33-year leap cycle
+// (Borkowski's algorithm).
+var jalaliBreaks = [...]int{
+	-61, 9, 38, 199, 426, 686, 756, 818, 1111, 1181, 1210,
+	1635, 2060, 2097, 2192, 2262, 2324, 2394, 2456, 3178,
+}
+
+// jalaliCalendar returns, for Jalali year jy, the number of years since
+// the last leap year (0 if jy is leap), the Gregorian year in which it
+// begins, and the March day of its first day.
+func jalaliCalendar(jy int) (leap, gy, march int) {
+	gy = jy + 621
+	leapJ := -14
+	jp := jalaliBreaks[0]
+	jump := 0
+	for _, jm := range jalaliBreaks[1:] {
+		jump = jm - jp
+		if jy < jm {
+			break
+		}
+		leapJ += jump/33*8 + jump%33/4
+		jp = jm
+	}
+	n := jy - jp
+
+	leapJ += n/33*8 + (n%33+3)/4
+	if jump%33 == 4 && jump-n == 4 {
+		leapJ++
+	}
+	leapG := gy/4 - (gy/100+1)*3/4 - 150
+	march = 20 + leapJ - leapG
+
+	if jump-n < 6 {
+		n = n - jump + (jump+4)/33*33
+	}
+	leap = ((n+1)%33 - 1) % 4
+	if leap == -1 {
+		leap = 4
+	}
+	return leap, gy, march
+}
+
+// JalaliRange returns the period from the start of the Jalali date from to
+// the end of the Jalali date to, in Tehran time. end is the last instant of
+// to, for APIs whose upper bound is inclusive.
+func JalaliRange(from, to JalaliDate) (start, end time.Time) {
+	start = from.Time(Tehran)
+	end = FromJalali(to.Year, to.Month, to.Day+1, Tehran).Add(-time.Nanosecond)
+	return start, end
+}
+
+// JalaliYearRange returns the period of a Jalali year, which is also the
+// Iranian fiscal year: 1 Farvardin to the last day of Esfand.
+func JalaliYearRange(year int) (start, end time.Time) {
+	return JalaliRange(JalaliDate{year, 1, 1}, JalaliDate{year, 12, JalaliMonthDays(year, 12)})
+}
+
+// JalaliMonthRange returns the period of a Jalali month.
+func JalaliMonthRange(year, month int) (start, end time.Time) {
+	return JalaliRange(JalaliDate{year, month, 1}, JalaliDate{year, month, JalaliMonthDays(year, month)})
+}
+
+// ParseJalaliRange parses two dates as ParseJalali does and returns their
+// period as JalaliRange does.
+func ParseJalaliRange(from, to string) (start, end time.Time, err error) {
+	if start, err = ParseJalali(from, Tehran); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end, err = ParseJalali(to, Tehran); err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if end.Before(start) {
+		return time.Time{}, time.Time{}, fmt.Errorf("Jalali range ends before it starts: %s > %s", from, to)
+	}
+	_, end = JalaliRange(ToJalali(end), ToJalali(end))
+	return start, end, nil
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIsLeapJalaliYear(t *testing.T) {
+	cases := []struct {
+		year int
+		leap bool
+	}{
+		{1395, true},
+		{1396, false},
+		{1399, true},
+		{1400, false},
+		{1401, false},
+		{1402, false},
+		{1403, true},
+		{1404, false},
+		{1407, false},
+		{1408, true},
+	}
+	for _, tc := range cases {
+		if got := IsLeapJalaliYear(tc.year); got != tc.leap {
+			t.Errorf("IsLeapJalaliYear(%d) = %v, want %v", tc.year, got, tc.leap)
+		}
+		want := 29
+		if tc.leap {
+			want = 30
+		}
+		if got := JalaliMonthDays(tc.year, 12); got != want {
+			t.Errorf("JalaliMonthDays(%d, 12) = %d, want %d", tc.year, got, want)
+		}
+	}
+}
+
+// TestNowruz checks 1 Farvardin, which falls on 20 or 21 March, and the
+// last day of Esfand before it.
+func TestNowruz(t *testing.T) {
+	cases := []struct {
+		year   int
+		nowruz string
+	}{
+		{1396, "2017-03-21"},
+		{1399, "2020-03-20"},
+		{1400, "2021-03-21"},
+		{1403, "2024-03-20"},
+		{1404, "2025-03-21"},
+		{1405, "2026-03-21"},
+	}
+	for _, tc := range cases {
+		want, err := time.ParseInLocation(time.DateOnly, tc.nowruz, Tehran)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FromJalali(tc.year, 1, 1, Tehran); !got.Equal(want) {
+			t.Errorf("FromJalali(%d, 1, 1) = %s, want %s", tc.year, got, want)
+		}
+		if got := ToJalali(want); got != (JalaliDate{tc.year, 1, 1}) {
+			t.Errorf("ToJalali(%s) = %s, want %d/01/01", tc.nowruz, got, tc.year)
+		}
+		eve := JalaliDate{tc.year - 1, 12, JalaliMonthDays(tc.year-1, 12)}
+		if got := ToJalali(want.AddDate(0, 0, -1)); got != eve {
+			t.Errorf("ToJalali(day before %s) = %s, want %s", tc.nowruz, got, eve)
+		}
+	}
+}
+
+func TestJalaliYearRange(t *testing.T) {
+	start, end := JalaliYearRange(1403)
+	if want := time.Date(2024, 3, 20, 0, 0, 0, 0, Tehran); !start.Equal(want) {
+		t.Errorf("start = %s, want %s", start, want)
+	}
+	if want := time.Date(2025, 3, 21, 0, 0, 0, 0, Tehran).Add(-time.Nanosecond); !end.Equal(want) {
+		t.Errorf("end = %s, want %s", end, want)
+	}
+}
+
+func TestParseJalali(t *testing.T) {
+	cases := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1403/12/30", want: "2025-03-20"},
+		{in: "1404-01-01", want: "2025-03-21"},
+		{in: "۱۴۰۳/۰۱/۰۱", want: "2024-03-20"},
+		{in: "1404/12/30", wantErr: true},
+		{in: "1403/13/01", wantErr: true},
+		{in: "1403/07/31", wantErr: true},
+		{in: "not a date", wantErr: true},
+	}
+	for _, tc := range cases {
+		got, err := ParseJalali(tc.in, Tehran)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseJalali(%q) = %s, want an error", tc.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseJalali(%q): %v", tc.in, err)
+			continue
+		}
+		if s := got.Format(time.DateOnly); s != tc.want {
+			t.Errorf("ParseJalali(%q) = %s, want %s", tc.in, s, tc.want)
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package utils
+
+import (
+	"encoding"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Params builds URL query parameters. Its zero value is ready to use and
+// its methods return the receiver so calls can be chained:
+//
+//	q := utils.NewParams().Set("symbol", "BTCUSDT").SetInt("page", 2)
+type Params struct {
+	values url.Values
+}
+
+// NewParams returns an empty Params.
+func NewParams() *Params {
+	return &Params{values: url.Values{}}
+}
+
+func (p *Params) init() {
+	if p.values == nil {
+		p.values = url.Values{}
+	}
+}
+
+// Set sets key to value, replacing any existing values.
+func (p *Params) Set(key, value string) *Params {
+	p.init()
+	p.values.Set(key, value)
+	return p
+}
+
+// Add appends value to key.
+func (p *Params) Add(key, value string) *Params {
+	p.init()
+	p.values.Add(key, value)
+	return p
+}
+
+// SetInt sets key to v in decimal.
+func (p *Params) SetInt(key string, v int64) *Params {
+	return p.Set(key, strconv.FormatInt(v, 10))
+}
+
+// SetFloat sets key to v in decimal notation without exponent.
+func (p *Params) SetFloat(key string, v float64) *Params {
+	return p.Set(key, strconv.FormatFloat(v, 'f', -1, 64))
+}
+
+// SetBool sets key to "true" or "false".
+func (p *Params) SetBool(key string, v bool) *Params {
+	return p.Set(key, strconv.FormatBool(v))
+}
+
+// SetTime sets key to v in unix seconds.
+func (p *Params) SetTime(key string, v time.Time) *Params {
+	return p.SetInt(key, v.Unix())
+}
+
+// Values returns the parameters. The result is shared with p.
+func (p *Params) Values() url.Values {
+	p.init()
+	return p.values
+}
+
+// Encode returns the parameters in URL-encoded form, sorted by key.
+func (p *Params) Encode() string {
+	if p == nil {
+		return ""
+	}
+	return p.values.Encode()
+}
+
+// ParamEncoder is implemented by types with a custom query encoding.
+type ParamEncoder interface {
+	EncodeParam() (string, error)
+}
+
+var (
+	paramEncoderType  = reflect.TypeOf((*ParamEncoder)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	timeType          = reflect.TypeOf(time.Time{})
+)
+
+// EncodeParams converts v into URL query parameters.
+//
+// v may be a *Params, url.Values, or a struct (or pointer to one) whose
+// fields carry `url` tags:
+//
+//	type HistoryParams struct {
+//	    Symbol string    `url:"symbol"`             // always sent
+//	    Side   string    `url:"side,omitempty"`     // omitted when empty
+//	    IDs    []int64   `url:"ids,comma"`          // ids=1,2,3
+//	    Tags   []string  `url:"tag"`                // tag=a&tag=b
+//	    From   time.Time `url:"from,unix,omitempty"` // unix seconds
+//	    To     time.Time `url:"to,unixmilli"`       // unix milliseconds
+//	}
+//
+// Fields without a url tag or tagged `url:"-"` are skipped; embedded
+// structs without a tag are flattened. Nil pointers are omitted. Values
+// implementing ParamEncoder or encoding.TextMarshaler use that encoding;
+// times without unix or unixmilli are formatted as RFC 3339.
+func EncodeParams(v interface{}) (url.Values, error) {
+	switch p := v.(type) {
+	case nil:
+		return url.Values{}, nil
+	case *Params:
+		return p.Values(), nil
+	case url.Values:
+		return p, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("params must be a struct, got %s", rv.Type())
+	}
+
+	values := url.Values{}
+	if err := encodeStruct(values, rv); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+type paramOptions struct {
+	omitempty bool
+	comma     bool
+	unix      bool
+	unixMilli bool
+}
+
+func encodeStruct(values url.Values, rv reflect.Value) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, tagged := field.Tag.Lookup("url")
+		if tag == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+
+		if field.Anonymous && !tagged {
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				if err := encodeStruct(values, fv); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !tagged || !field.IsExported() {
+			continue
+		}
+
+		name, rest, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		var opts paramOptions
+		for _, o := range strings.Split(rest, ",") {
+			switch o {
+			case "omitempty":
+				opts.omitempty = true
+			case "comma":
+				opts.comma = true
+			case "unix":
+				opts.unix = true
+			case "unixmilli":
+				opts.unixMilli = true
+			}
+		}
+
+		for fv.Kind() == reflect.Pointer {
+			if fv.IsNil() {
+				break
+			}
+			fv = fv.Elem()
+		}
+		if fv.Kind() == reflect.Pointer || opts.omitempty && fv.IsZero() {
+			continue
+		}
+
+		if (fv.Kind() == reflect.Slice || fv.Kind() == reflect.Array) && !isScalarParam(fv.Type()) {
+			items := make([]string, 0, fv.Len())
+			for j := 0; j < fv.Len(); j++ {
+				s, err := formatParam(fv.Index(j), opts)
+				if err != nil {
+					return fmt.Errorf("param %s: %w", name, err)
+				}
+				items = append(items, s)
+			}
+			if opts.comma {
+				values.Set(name, strings.Join(items, ","))
+				continue
+			}
+			for _, s := range items {
+				values.Add(name, s)
+			}
+			continue
+		}
+
+		s, err := formatParam(fv, opts)
+		if err != nil {
+			return fmt.Errorf("param %s: %w", name, err)
+		}
+		values.Set(name, s)
+	}
+	return nil
+}
+
+// isScalarParam reports whether a slice or array type encodes as a single
+// value, e.g. a TextMarshaler implemented on a byte slice.
+func isScalarParam(t reflect.Type) bool {
+	return t.Implements(paramEncoderType) || t.Implements(textMarshalerType)
+}
+
+func formatParam(v reflect.Value, opts paramOptions) (string, error) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		tm := v.Interface().(time.Time)
+		switch {
+		case opts.unix:
+			return strconv.FormatInt(tm.Unix(), 10), nil
+		case opts.unixMilli:
+			return strconv.FormatInt(tm.UnixMilli(), 10), nil
+		}
+		return tm.Format(time.RFC3339), nil
+	}
+	if v.Type().Implements(paramEncoderType) {
+		return v.Interface().(ParamEncoder).EncodeParam()
+	}
+	if v.Type().Implements(textMarshalerType) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
@@ -0,0 +1,24 @@
+package utils
+
+import "math"
+
+// RoundDown rounds v down to the given number of decimals, e.g. a quantity
+// to the step size of a market. The small tolerance keeps values that are
+// exact in decimal but not in binary, such as 0.29, from losing a step. A
+// negative decimals leaves v unchanged.
+func RoundDown(v float64, decimals int) float64 {
+	if decimals < 0 {
+		return v
+	}
+	p := math.Pow10(decimals)
+	return math.Floor(v*p+1e-9) / p
+}
+
+// RoundUp is RoundDown rounding up.
+func RoundUp(v float64, decimals int) float64 {
+	if decimals < 0 {
+		return v
+	}
+	p := math.Pow10(decimals)
+	return math.Ceil(v*p-1e-9) / p
+}
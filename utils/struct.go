@@ -53,6 +53,9 @@ import (
 // Limitations:
 //   - Only fields with `json` tags are considered.
 //   - Non-struct input will result in an error.
+//
+// Deprecated: StructToURLParams cannot express optional fields, list or
+// time encodings. Use EncodeParams with `url` struct tags instead.
 func StructToURLParams(inputStruct interface{}) (string, error) {
 	values := url.Values{}
 
@@ -0,0 +1,54 @@
+package wallex
+
+import "strconv"
+
+// MaxClientOrderIdLength is the longest clientOrderId accepted locally.
+const MaxClientOrderIdLength = 64
+
+// validateSymbol checks that symbol is a Wallex market or asset code:
+// ASCII letters and digits only, e.g. "BTCUSDT" or "USDT".
+func validateSymbol(param, symbol string) error {
+	if symbol == "" || len(symbol) > 32 {
+		return invalidParam(param, symbol, "must be 1 to 32 characters")
+	}
+	for i := 0; i < len(symbol); i++ {
+		if !isAlnum(symbol[i]) {
+			return invalidParam(param, symbol, "must contain only letters and digits")
+		}
+	}
+	return nil
+}
+
+// validateClientOrderId checks that id consists of ASCII letters, digits
+// and "-", "_", ".", ":" only, so it is safe in paths and queries.
+func validateClientOrderId(id string) error {
+	if id == "" || len(id) > MaxClientOrderIdLength {
+		return invalidParam("clientOrderId", id, "must be 1 to "+strconv.Itoa(MaxClientOrderIdLength)+" characters")
+	}
+	for i := 0; i < len(id); i++ {
+		switch b := id[i]; {
+		case isAlnum(b), b == '-', b == '_', b == '.', b == ':':
+		default:
+			return invalidParam("clientOrderId", id, "must contain only letters, digits and - _ . :")
+		}
+	}
+	if id == "." || id == ".." {
+		return invalidParam("clientOrderId", id, "must not be a path segment")
+	}
+	return nil
+}
+
+func isAlnum(b byte) bool {
+	return b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z' || b >= '0' && b <= '9'
+}
+
+func invalidParam(param, value, reason string) *InvalidParamError {
+	return &InvalidParamError{
+		GoWallexError: GoWallexError{
+			Message: "invalid " + param + " " + strconv.Quote(value) + ": " + reason,
+			Err:     nil,
+		},
+		Param: param,
+		Value: value,
+	}
+}
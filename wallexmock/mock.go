@@ -0,0 +1,488 @@
+// Package wallexmock provides a hand-written mock of wallex.WallexAPI.
+//
+// Each method has a matching XxxFunc field. Tests set only the functions
+// they expect to be called; invoking a method whose function is nil returns
+// ErrUnexpectedCall, or yields it for iterators. Methods taking a context
+// pass it on to their function. Every invocation is recorded and can be
+// inspected with Calls and CallCount.
+//
+// Example:
+//
+//	mock := &wallexmock.Client{
+//	    GetOrderBookFunc: func(symbol string) (*types.Depth, error) {
+//	        return &types.Depth{}, nil
+//	    },
+//	}
+//	_, _ = mock.GetOrderBook("BTCUSDT")
+//	mock.CallCount("GetOrderBook") // 1
+package wallexmock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"sync"
+	"time"
+
+	wallex "github.com/darhelm/go-wallex"
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// ErrUnexpectedCall is returned when a method is invoked without its
+// corresponding XxxFunc being set.
+var ErrUnexpectedCall = errors.New("wallexmock: unexpected call")
+
+// Call records a single method invocation on the mock.
+type Call struct {
+	// Method is the name of the invoked method, e.g. "CreateOrder".
+	Method string
+
+	// Args holds the arguments in declaration order, without the context
+	// and RequestOptions.
+	Args []interface{}
+}
+
+// Client is a programmable implementation of wallex.WallexAPI.
+//
+// It is safe for concurrent use.
+type Client struct {
+	GetMarketsInfoFunc            func() (*t.MarketInformation, error)
+	GetCurrencyStatsFunc          func() (*t.CurrencyStatsResponse, error)
+	GetOrderBookFunc              func(symbol string) (*t.Depth, error)
+	GetAllOrderBooksFunc          func() (*t.AllDepths, error)
+	GetRecentTradesFunc           func(symbol string) (*t.Trades, error)
+	GetCandlesFunc                func(params t.CandleParams) (*t.CandleHistory, error)
+	GetWalletsFunc                func() (*t.Wallets, error)
+	CreateOrderFunc               func(params t.CreateOrderParams) (*t.BaseOrderResponse, error)
+	CancelOrderFunc               func(clientOrderId string) (*t.CancelOrderResponse, error)
+	GetOpenOrdersFunc             func(symbol string) (*t.OpenOrdersResponse, error)
+	GetOrderStatusFunc            func(clientOrderId string) (*t.BaseOrderResponse, error)
+	GetUserTradesFunc             func(params t.UserTradesParams) (*t.UserTradesResponse, error)
+	GetOrderHistoryFunc           func(params t.OrderHistoryParams) (*t.OrderHistoryResponse, error)
+	GetAssetNetworksFunc          func(asset string) (*t.AssetNetworksResponse, error)
+	GetAccountFeesFunc            func() (*t.AccountFeesResponse, error)
+	WithdrawFiatFunc              func(params t.FiatWithdrawalParams) (*t.FiatWithdrawalResponse, error)
+	GetFiatDepositsFunc           func(params t.HistoryParams) (*t.FiatHistoryResponse, error)
+	GetFiatWithdrawalsFunc        func(params t.HistoryParams) (*t.FiatHistoryResponse, error)
+	GetCryptoDepositsFunc         func(params t.CryptoHistoryParams) (*t.CryptoHistoryResponse, error)
+	WithdrawCryptoFunc            func(params t.CryptoWithdrawalParams) (*t.CryptoWithdrawalResponse, error)
+	GetCryptoWithdrawalsFunc      func(params t.CryptoHistoryParams) (*t.CryptoHistoryResponse, error)
+	ReplaceOrderFunc              func(ctx context.Context, clientOrderId string, newPrice string, newQty string) (*wallex.ReplaceOrderResult, error)
+	CreateOrdersFunc              func(ctx context.Context, params []t.CreateOrderParams) []wallex.CreateOrderResult
+	CancelOrdersOlderThanFunc     func(ctx context.Context, symbol string, age time.Duration) ([]wallex.CancelOrderResult, error)
+	ListOpenOrdersFunc            func(ctx context.Context, symbols ...string) (*t.OpenOrdersResponse, error)
+	EnsureOrderFunc               func(ctx context.Context, params t.CreateOrderParams) (*t.BaseOrderResponse, error)
+	ExecuteMarketWithLimitFunc    func(ctx context.Context, symbol, side string, qty, maxSlippageBps float64) (*t.BaseOrderResponse, error)
+	AllocateBudgetFunc            func(symbol string, budget, price, feeRate float64) (*wallex.Allocation, error)
+	FeeBreakevenFunc              func(symbol string) (*wallex.Breakeven, error)
+	ProbeCapabilitiesFunc         func(ctx context.Context) (wallex.Capabilities, error)
+	UserTradesFunc                func(ctx context.Context, params t.UserTradesParams) iter.Seq2[t.UserTrade, error]
+	OrderHistoryFunc              func(ctx context.Context, params t.OrderHistoryParams) iter.Seq2[t.BaseOrder, error]
+	FiatDepositsFunc              func(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error]
+	FiatWithdrawalsFunc           func(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error]
+	CryptoDepositsFunc            func(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error]
+	CryptoWithdrawalsFunc         func(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error]
+	WaitForDepositFunc            func(ctx context.Context, asset, minAmount string) (*t.CryptoTransfer, error)
+	WaitForDepositWithOptionsFunc func(ctx context.Context, asset, minAmount string, opts wallex.DepositWaitOptions) (*t.CryptoTransfer, error)
+	SubmitWithdrawalFunc          func(ctx context.Context, params t.CryptoWithdrawalParams, opts wallex.WithdrawalTrackOptions) (*t.CryptoTransfer, error)
+	TrackWithdrawalFunc           func(ctx context.Context, withdrawal t.CryptoTransfer, opts wallex.WithdrawalTrackOptions) (*t.CryptoTransfer, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+var _ wallex.WallexAPI = (*Client)(nil)
+
+func (m *Client) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+func unexpected(method string) error {
+	return fmt.Errorf("%w: %s", ErrUnexpectedCall, method)
+}
+
+// Seq returns an iterator yielding items and then, if err is non-nil, err
+// with a zero value. It is meant for the functions of the iterator methods:
+//
+//	mock := &wallexmock.Client{
+//	    UserTradesFunc: func(context.Context, types.UserTradesParams) iter.Seq2[types.UserTrade, error] {
+//	        return wallexmock.Seq(trades, nil)
+//	    },
+//	}
+//
+// Iterator methods whose function is nil yield ErrUnexpectedCall this way.
+func Seq[T any](items []T, err error) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for _, item := range items {
+			if !yield(item, nil) {
+				return
+			}
+		}
+		if err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+func unexpectedOrders(params []t.CreateOrderParams) []wallex.CreateOrderResult {
+	out := make([]wallex.CreateOrderResult, len(params))
+	for i, p := range params {
+		out[i] = wallex.CreateOrderResult{Params: p, Err: unexpected("CreateOrders")}
+	}
+	return out
+}
+
+// Calls returns a copy of all recorded invocations in call order.
+func (m *Client) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]Call, len(m.calls))
+	copy(out, m.calls)
+	return out
+}
+
+// CallCount returns how many times the named method was invoked.
+func (m *Client) CallCount(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	n := 0
+	for _, c := range m.calls {
+		if c.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset clears the recorded invocations. Configured functions are kept.
+func (m *Client) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+}
+
+func (m *Client) GetMarketsInfo(_ ...wallex.RequestOption) (*t.MarketInformation, error) {
+	m.record("GetMarketsInfo")
+	if m.GetMarketsInfoFunc == nil {
+		return nil, unexpected("GetMarketsInfo")
+	}
+	return m.GetMarketsInfoFunc()
+}
+
+func (m *Client) GetCurrencyStats(_ ...wallex.RequestOption) (*t.CurrencyStatsResponse, error) {
+	m.record("GetCurrencyStats")
+	if m.GetCurrencyStatsFunc == nil {
+		return nil, unexpected("GetCurrencyStats")
+	}
+	return m.GetCurrencyStatsFunc()
+}
+
+func (m *Client) GetOrderBook(symbol string, _ ...wallex.RequestOption) (*t.Depth, error) {
+	m.record("GetOrderBook", symbol)
+	if m.GetOrderBookFunc == nil {
+		return nil, unexpected("GetOrderBook")
+	}
+	return m.GetOrderBookFunc(symbol)
+}
+
+func (m *Client) GetAllOrderBooks(_ ...wallex.RequestOption) (*t.AllDepths, error) {
+	m.record("GetAllOrderBooks")
+	if m.GetAllOrderBooksFunc == nil {
+		return nil, unexpected("GetAllOrderBooks")
+	}
+	return m.GetAllOrderBooksFunc()
+}
+
+func (m *Client) GetRecentTrades(symbol string, _ ...wallex.RequestOption) (*t.Trades, error) {
+	m.record("GetRecentTrades", symbol)
+	if m.GetRecentTradesFunc == nil {
+		return nil, unexpected("GetRecentTrades")
+	}
+	return m.GetRecentTradesFunc(symbol)
+}
+
+func (m *Client) GetCandles(params t.CandleParams, _ ...wallex.RequestOption) (*t.CandleHistory, error) {
+	m.record("GetCandles", params)
+	if m.GetCandlesFunc == nil {
+		return nil, unexpected("GetCandles")
+	}
+	return m.GetCandlesFunc(params)
+}
+
+func (m *Client) GetWallets(_ ...wallex.RequestOption) (*t.Wallets, error) {
+	m.record("GetWallets")
+	if m.GetWalletsFunc == nil {
+		return nil, unexpected("GetWallets")
+	}
+	return m.GetWalletsFunc()
+}
+
+func (m *Client) CreateOrder(params t.CreateOrderParams, _ ...wallex.RequestOption) (*t.BaseOrderResponse, error) {
+	m.record("CreateOrder", params)
+	if m.CreateOrderFunc == nil {
+		return nil, unexpected("CreateOrder")
+	}
+	return m.CreateOrderFunc(params)
+}
+
+func (m *Client) CancelOrder(clientOrderId string, _ ...wallex.RequestOption) (*t.CancelOrderResponse, error) {
+	m.record("CancelOrder", clientOrderId)
+	if m.CancelOrderFunc == nil {
+		return nil, unexpected("CancelOrder")
+	}
+	return m.CancelOrderFunc(clientOrderId)
+}
+
+func (m *Client) GetOpenOrders(symbol string, _ ...wallex.RequestOption) (*t.OpenOrdersResponse, error) {
+	m.record("GetOpenOrders", symbol)
+	if m.GetOpenOrdersFunc == nil {
+		return nil, unexpected("GetOpenOrders")
+	}
+	return m.GetOpenOrdersFunc(symbol)
+}
+
+func (m *Client) GetOrderStatus(clientOrderId string, _ ...wallex.RequestOption) (*t.BaseOrderResponse, error) {
+	m.record("GetOrderStatus", clientOrderId)
+	if m.GetOrderStatusFunc == nil {
+		return nil, unexpected("GetOrderStatus")
+	}
+	return m.GetOrderStatusFunc(clientOrderId)
+}
+
+func (m *Client) GetUserTrades(params t.UserTradesParams, _ ...wallex.RequestOption) (*t.UserTradesResponse, error) {
+	m.record("GetUserTrades", params)
+	if m.GetUserTradesFunc == nil {
+		return nil, unexpected("GetUserTrades")
+	}
+	return m.GetUserTradesFunc(params)
+}
+
+func (m *Client) GetOrderHistory(params t.OrderHistoryParams, _ ...wallex.RequestOption) (*t.OrderHistoryResponse, error) {
+	m.record("GetOrderHistory", params)
+	if m.GetOrderHistoryFunc == nil {
+		return nil, unexpected("GetOrderHistory")
+	}
+	return m.GetOrderHistoryFunc(params)
+}
+
+func (m *Client) GetAssetNetworks(asset string, _ ...wallex.RequestOption) (*t.AssetNetworksResponse, error) {
+	m.record("GetAssetNetworks", asset)
+	if m.GetAssetNetworksFunc == nil {
+		return nil, unexpected("GetAssetNetworks")
+	}
+	return m.GetAssetNetworksFunc(asset)
+}
+
+func (m *Client) GetAccountFees(_ ...wallex.RequestOption) (*t.AccountFeesResponse, error) {
+	m.record("GetAccountFees")
+	if m.GetAccountFeesFunc == nil {
+		return nil, unexpected("GetAccountFees")
+	}
+	return m.GetAccountFeesFunc()
+}
+
+func (m *Client) WithdrawFiat(params t.FiatWithdrawalParams, _ ...wallex.RequestOption) (*t.FiatWithdrawalResponse, error) {
+	m.record("WithdrawFiat", params)
+	if m.WithdrawFiatFunc == nil {
+		return nil, unexpected("WithdrawFiat")
+	}
+	return m.WithdrawFiatFunc(params)
+}
+
+func (m *Client) GetFiatDeposits(params t.HistoryParams, _ ...wallex.RequestOption) (*t.FiatHistoryResponse, error) {
+	m.record("GetFiatDeposits", params)
+	if m.GetFiatDepositsFunc == nil {
+		return nil, unexpected("GetFiatDeposits")
+	}
+	return m.GetFiatDepositsFunc(params)
+}
+
+func (m *Client) GetFiatWithdrawals(params t.HistoryParams, _ ...wallex.RequestOption) (*t.FiatHistoryResponse, error) {
+	m.record("GetFiatWithdrawals", params)
+	if m.GetFiatWithdrawalsFunc == nil {
+		return nil, unexpected("GetFiatWithdrawals")
+	}
+	return m.GetFiatWithdrawalsFunc(params)
+}
+
+func (m *Client) GetCryptoDeposits(params t.CryptoHistoryParams, _ ...wallex.RequestOption) (*t.CryptoHistoryResponse, error) {
+	m.record("GetCryptoDeposits", params)
+	if m.GetCryptoDepositsFunc == nil {
+		return nil, unexpected("GetCryptoDeposits")
+	}
+	return m.GetCryptoDepositsFunc(params)
+}
+
+func (m *Client) WithdrawCrypto(params t.CryptoWithdrawalParams, _ ...wallex.RequestOption) (*t.CryptoWithdrawalResponse, error) {
+	m.record("WithdrawCrypto", params)
+	if m.WithdrawCryptoFunc == nil {
+		return nil, unexpected("WithdrawCrypto")
+	}
+	return m.WithdrawCryptoFunc(params)
+}
+
+func (m *Client) GetCryptoWithdrawals(params t.CryptoHistoryParams, _ ...wallex.RequestOption) (*t.CryptoHistoryResponse, error) {
+	m.record("GetCryptoWithdrawals", params)
+	if m.GetCryptoWithdrawalsFunc == nil {
+		return nil, unexpected("GetCryptoWithdrawals")
+	}
+	return m.GetCryptoWithdrawalsFunc(params)
+}
+
+func (m *Client) ReplaceOrder(ctx context.Context, clientOrderId string, newPrice string, newQty string) (*wallex.ReplaceOrderResult, error) {
+	m.record("ReplaceOrder", clientOrderId, newPrice, newQty)
+	if m.ReplaceOrderFunc == nil {
+		return nil, unexpected("ReplaceOrder")
+	}
+	return m.ReplaceOrderFunc(ctx, clientOrderId, newPrice, newQty)
+}
+
+// CreateOrders returns a result failing with ErrUnexpectedCall for every
+// order when CreateOrdersFunc is nil.
+func (m *Client) CreateOrders(ctx context.Context, params []t.CreateOrderParams) []wallex.CreateOrderResult {
+	m.record("CreateOrders", params)
+	if m.CreateOrdersFunc == nil {
+		return unexpectedOrders(params)
+	}
+	return m.CreateOrdersFunc(ctx, params)
+}
+
+func (m *Client) CancelOrdersOlderThan(ctx context.Context, symbol string, age time.Duration) ([]wallex.CancelOrderResult, error) {
+	m.record("CancelOrdersOlderThan", symbol, age)
+	if m.CancelOrdersOlderThanFunc == nil {
+		return nil, unexpected("CancelOrdersOlderThan")
+	}
+	return m.CancelOrdersOlderThanFunc(ctx, symbol, age)
+}
+
+func (m *Client) ListOpenOrders(ctx context.Context, symbols ...string) (*t.OpenOrdersResponse, error) {
+	m.record("ListOpenOrders", symbols)
+	if m.ListOpenOrdersFunc == nil {
+		return nil, unexpected("ListOpenOrders")
+	}
+	return m.ListOpenOrdersFunc(ctx, symbols...)
+}
+
+func (m *Client) EnsureOrder(ctx context.Context, params t.CreateOrderParams) (*t.BaseOrderResponse, error) {
+	m.record("EnsureOrder", params)
+	if m.EnsureOrderFunc == nil {
+		return nil, unexpected("EnsureOrder")
+	}
+	return m.EnsureOrderFunc(ctx, params)
+}
+
+func (m *Client) ExecuteMarketWithLimit(ctx context.Context, symbol, side string, qty, maxSlippageBps float64) (*t.BaseOrderResponse, error) {
+	m.record("ExecuteMarketWithLimit", symbol, side, qty, maxSlippageBps)
+	if m.ExecuteMarketWithLimitFunc == nil {
+		return nil, unexpected("ExecuteMarketWithLimit")
+	}
+	return m.ExecuteMarketWithLimitFunc(ctx, symbol, side, qty, maxSlippageBps)
+}
+
+func (m *Client) AllocateBudget(symbol string, budget, price, feeRate float64, _ ...wallex.RequestOption) (*wallex.Allocation, error) {
+	m.record("AllocateBudget", symbol, budget, price, feeRate)
+	if m.AllocateBudgetFunc == nil {
+		return nil, unexpected("AllocateBudget")
+	}
+	return m.AllocateBudgetFunc(symbol, budget, price, feeRate)
+}
+
+func (m *Client) FeeBreakeven(symbol string, _ ...wallex.RequestOption) (*wallex.Breakeven, error) {
+	m.record("FeeBreakeven", symbol)
+	if m.FeeBreakevenFunc == nil {
+		return nil, unexpected("FeeBreakeven")
+	}
+	return m.FeeBreakevenFunc(symbol)
+}
+
+func (m *Client) ProbeCapabilities(ctx context.Context) (wallex.Capabilities, error) {
+	m.record("ProbeCapabilities")
+	if m.ProbeCapabilitiesFunc == nil {
+		return wallex.Capabilities{}, unexpected("ProbeCapabilities")
+	}
+	return m.ProbeCapabilitiesFunc(ctx)
+}
+
+func (m *Client) UserTrades(ctx context.Context, params t.UserTradesParams) iter.Seq2[t.UserTrade, error] {
+	m.record("UserTrades", params)
+	if m.UserTradesFunc == nil {
+		return Seq[t.UserTrade](nil, unexpected("UserTrades"))
+	}
+	return m.UserTradesFunc(ctx, params)
+}
+
+func (m *Client) OrderHistory(ctx context.Context, params t.OrderHistoryParams) iter.Seq2[t.BaseOrder, error] {
+	m.record("OrderHistory", params)
+	if m.OrderHistoryFunc == nil {
+		return Seq[t.BaseOrder](nil, unexpected("OrderHistory"))
+	}
+	return m.OrderHistoryFunc(ctx, params)
+}
+
+func (m *Client) FiatDeposits(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error] {
+	m.record("FiatDeposits", params)
+	if m.FiatDepositsFunc == nil {
+		return Seq[t.FiatTransfer](nil, unexpected("FiatDeposits"))
+	}
+	return m.FiatDepositsFunc(ctx, params)
+}
+
+func (m *Client) FiatWithdrawals(ctx context.Context, params t.HistoryParams) iter.Seq2[t.FiatTransfer, error] {
+	m.record("FiatWithdrawals", params)
+	if m.FiatWithdrawalsFunc == nil {
+		return Seq[t.FiatTransfer](nil, unexpected("FiatWithdrawals"))
+	}
+	return m.FiatWithdrawalsFunc(ctx, params)
+}
+
+func (m *Client) CryptoDeposits(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error] {
+	m.record("CryptoDeposits", params)
+	if m.CryptoDepositsFunc == nil {
+		return Seq[t.CryptoTransfer](nil, unexpected("CryptoDeposits"))
+	}
+	return m.CryptoDepositsFunc(ctx, params)
+}
+
+func (m *Client) CryptoWithdrawals(ctx context.Context, params t.CryptoHistoryParams) iter.Seq2[t.CryptoTransfer, error] {
+	m.record("CryptoWithdrawals", params)
+	if m.CryptoWithdrawalsFunc == nil {
+		return Seq[t.CryptoTransfer](nil, unexpected("CryptoWithdrawals"))
+	}
+	return m.CryptoWithdrawalsFunc(ctx, params)
+}
+
+func (m *Client) WaitForDeposit(ctx context.Context, asset, minAmount string) (*t.CryptoTransfer, error) {
+	m.record("WaitForDeposit", asset, minAmount)
+	if m.WaitForDepositFunc == nil {
+		return nil, unexpected("WaitForDeposit")
+	}
+	return m.WaitForDepositFunc(ctx, asset, minAmount)
+}
+
+func (m *Client) WaitForDepositWithOptions(ctx context.Context, asset, minAmount string, opts wallex.DepositWaitOptions) (*t.CryptoTransfer, error) {
+	m.record("WaitForDepositWithOptions", asset, minAmount, opts)
+	if m.WaitForDepositWithOptionsFunc == nil {
+		return nil, unexpected("WaitForDepositWithOptions")
+	}
+	return m.WaitForDepositWithOptionsFunc(ctx, asset, minAmount, opts)
+}
+
+func (m *Client) SubmitWithdrawal(ctx context.Context, params t.CryptoWithdrawalParams, opts wallex.WithdrawalTrackOptions) (*t.CryptoTransfer, error) {
+	m.record("SubmitWithdrawal", params, opts)
+	if m.SubmitWithdrawalFunc == nil {
+		return nil, unexpected("SubmitWithdrawal")
+	}
+	return m.SubmitWithdrawalFunc(ctx, params, opts)
+}
+
+func (m *Client) TrackWithdrawal(ctx context.Context, withdrawal t.CryptoTransfer, opts wallex.WithdrawalTrackOptions) (*t.CryptoTransfer, error) {
+	m.record("TrackWithdrawal", withdrawal, opts)
+	if m.TrackWithdrawalFunc == nil {
+		return nil, unexpected("TrackWithdrawal")
+	}
+	return m.TrackWithdrawalFunc(ctx, withdrawal, opts)
+}
@@ -0,0 +1,350 @@
+package wallex
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// Default polling bounds used by WatchOrderBook.
+const (
+	DefaultBookPollMinInterval = 250 * time.Millisecond
+	DefaultBookPollMaxInterval = 2 * time.Second
+)
+
+// BookUpdate is delivered by WatchOrderBook whenever the watched book
+// changes, or when fetching it failed.
+type BookUpdate struct {
+	Symbol string
+
+	// Book is the latest full order book. On error it holds the last good
+	// book.
+	Book t.OrderBook
+
+	// ReceivedAt is when the book was fetched.
+	ReceivedAt time.Time
+
+	// Err is set when the latest refresh failed. Watching continues; the
+	// next successful refresh is delivered normally.
+	Err error
+
+	// Skipped is the number of earlier updates this one replaced because
+	// the consumer had not received them yet; see BookWatchOptions.Conflate.
+	Skipped int
+
+	// Events are the significant changes since the previous book, see
+	// BookWatchOptions.Events. Events of skipped updates are carried over
+	// to this one.
+	Events []BookEvent
+}
+
+// BookWatchOptions tunes WatchOrderBookWithOptions.
+type BookWatchOptions struct {
+	// MinInterval is the polling interval while the book is changing.
+	// Defaults to DefaultBookPollMinInterval.
+	MinInterval time.Duration
+
+	// MaxInterval is the interval the poller backs off to while the book is
+	// unchanged. Defaults to DefaultBookPollMaxInterval.
+	MaxInterval time.Duration
+
+	// Buffer is the capacity of the update channel. Defaults to 16.
+	Buffer int
+
+	// Overflow is applied when the update channel is full. Discarded
+	// updates are counted in the Skipped field of the next update
+	// delivered. Defaults to Block: polling pauses until the consumer
+	// catches up.
+	Overflow OverflowPolicy
+
+	// Depth, if positive, keeps only the best Depth levels of each side.
+	// Deeper levels are dropped as soon as a book is received, and changes
+	// confined to them do not produce updates, which saves memory and
+	// work when many symbols are watched.
+	Depth int
+
+	// Verify checks every fetched book with VerifyOrderBook. Books that
+	// fail are not delivered as the current book; instead an update with an
+	// *IntegrityError is sent, OnIntegrity is called and the book is
+	// resynced at MinInterval.
+	Verify bool
+
+	// MaxStaleness, if positive, reports an IssueStale integrity problem
+	// when no valid book was received for that long.
+	MaxStaleness time.Duration
+
+	// OnIntegrity receives diagnostic events for failed verifications and
+	// staleness. It is called from the polling goroutine.
+	OnIntegrity func(IntegrityEvent)
+
+	// Conflate, if positive, decouples polling from a slow consumer:
+	// instead of waiting for the consumer, the poller replaces an update
+	// not yet received with the newer one and counts it in Skipped, and
+	// updates are delivered at most once per Conflate. Overflow is then
+	// not used.
+	Conflate time.Duration
+
+	// Events sets the thresholds of the book events, large orders appearing
+	// or disappearing and sweeps, detected between consecutive books and
+	// delivered in BookUpdate.Events. Disabled by default.
+	Events BookEventOptions
+}
+
+func (o BookWatchOptions) withDefaults() BookWatchOptions {
+	if o.MinInterval <= 0 {
+		o.MinInterval = DefaultBookPollMinInterval
+	}
+	if o.MaxInterval < o.MinInterval {
+		o.MaxInterval = DefaultBookPollMaxInterval
+		if o.MaxInterval < o.MinInterval {
+			o.MaxInterval = o.MinInterval
+		}
+	}
+	if o.Buffer <= 0 {
+		o.Buffer = 16
+	}
+	o.Overflow = o.Overflow.or(Block)
+	return o
+}
+
+// WatchOrderBook returns the current order book of symbol together with a
+// channel of subsequent updates, using the default BookWatchOptions.
+//
+// Wallex's REST API is the only transport currently supported, so updates
+// come from smart polling of GET /v1/depth: the poller runs at MinInterval
+// while the book changes and doubles its interval up to MaxInterval while it
+// is unchanged. Only changed books are delivered. The channel is closed
+// when ctx is done or the client is shut down.
+//
+// Authentication: NOT required.
+func (c *Client) WatchOrderBook(ctx context.Context, symbol string) (*t.OrderBook, <-chan BookUpdate, error) {
+	return c.WatchOrderBookWithOptions(ctx, symbol, BookWatchOptions{})
+}
+
+// WatchOrderBookWithOptions is WatchOrderBook with explicit options.
+func (c *Client) WatchOrderBookWithOptions(ctx context.Context, symbol string, opts BookWatchOptions) (*t.OrderBook, <-chan BookUpdate, error) {
+	opts = opts.withDefaults()
+
+	depth, err := c.getOrderBook(ctx, symbol)
+	if err != nil {
+		return nil, nil, err
+	}
+	snapshot := depth.Result.Top(opts.Depth)
+	if opts.Verify {
+		if issues := VerifyOrderBook(snapshot); len(issues) > 0 {
+			return nil, nil, newIntegrityError(symbol, issues)
+		}
+	}
+
+	updates := make(chan BookUpdate, opts.Buffer)
+	go c.pollOrderBook(ctx, symbol, snapshot, opts, updates)
+
+	return &snapshot, updates, nil
+}
+
+func (c *Client) pollOrderBook(ctx context.Context, symbol string, last t.OrderBook, opts BookWatchOptions, out chan BookUpdate) {
+	carried := 0
+	var carriedEvents []BookEvent
+	skip := func(dropped BookUpdate, next *BookUpdate) {
+		if next == nil {
+			carried += 1 + dropped.Skipped
+			carriedEvents = append(carriedEvents, dropped.Events...)
+			return
+		}
+		next.Skipped += 1 + dropped.Skipped
+		next.Events = append(dropped.Events, next.Events...)
+	}
+	send := func(u BookUpdate) bool {
+		u.Skipped, carried = u.Skipped+carried, 0
+		if len(carriedEvents) > 0 {
+			u.Events, carriedEvents = append(carriedEvents, u.Events...), nil
+		}
+		return offer(ctx, out, u, opts.Overflow, skip)
+	}
+	if opts.Conflate > 0 {
+		cf := newBookConflator(opts.Conflate)
+		go cf.run(ctx, out)
+		defer cf.stop()
+		send = cf.put
+	} else {
+		defer close(out)
+	}
+
+	interval := opts.MinInterval
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	lastValid := time.Now()
+	staleReported := false
+	report := func(issues []BookIssue, book t.OrderBook, now time.Time) *BookUpdate {
+		if opts.OnIntegrity != nil {
+			opts.OnIntegrity(IntegrityEvent{Symbol: symbol, Issues: issues, Book: book, DetectedAt: now, LastValid: lastValid})
+		}
+		return &BookUpdate{Symbol: symbol, Book: last, ReceivedAt: now, Err: newIntegrityError(symbol, issues)}
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c.Done():
+			return
+		case <-timer.C:
+		}
+
+		depth, err := c.getOrderBook(ctx, symbol)
+		now := time.Now()
+
+		var book t.OrderBook
+		var issues []BookIssue
+		if err == nil {
+			book = depth.Result.Top(opts.Depth)
+			if opts.Verify {
+				issues = VerifyOrderBook(book)
+			}
+		}
+
+		var update *BookUpdate
+		switch {
+		case err != nil:
+			if ctx.Err() != nil {
+				return
+			}
+			update = &BookUpdate{Symbol: symbol, Book: last, ReceivedAt: now, Err: err}
+			interval = min(interval*2, opts.MaxInterval)
+		case len(issues) > 0:
+			update = report(issues, book, now)
+			interval = opts.MinInterval
+		case reflect.DeepEqual(book, last):
+			lastValid, staleReported = now, false
+			interval = min(interval*2, opts.MaxInterval)
+		default:
+			lastValid, staleReported = now, false
+			events := DetectBookEvents(last, book, opts.Events)
+			for _, ev := range events {
+				c.metrics().Add("wallex_book_events_total", 1,
+					Label{Name: "symbol", Value: symbol}, Label{Name: "kind", Value: string(ev.Kind)})
+			}
+			last = book
+			update = &BookUpdate{Symbol: symbol, Book: last, ReceivedAt: now, Events: events}
+			interval = opts.MinInterval
+		}
+
+		if opts.MaxStaleness > 0 && !staleReported && now.Sub(lastValid) > opts.MaxStaleness {
+			staleReported = true
+			stale := []BookIssue{{
+				Kind:   IssueStale,
+				Level:  -1,
+				Detail: fmt.Sprintf("no valid book for %s", now.Sub(lastValid).Round(time.Millisecond)),
+			}}
+			if update != nil && !send(*update) {
+				return
+			}
+			update = report(stale, last, now)
+		}
+
+		if update != nil && !send(*update) {
+			return
+		}
+
+		timer.Reset(interval)
+	}
+}
+
+// bookConflator delivers the latest of the updates put into it, at most
+// once per interval, replacing updates the consumer has not received.
+type bookConflator struct {
+	interval time.Duration
+
+	mu      sync.Mutex
+	pending *BookUpdate
+	skipped int
+
+	ready chan struct{}
+	done  chan struct{}
+}
+
+func newBookConflator(interval time.Duration) *bookConflator {
+	return &bookConflator{
+		interval: interval,
+		ready:    make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+// put stores u as the pending update without blocking.
+func (cf *bookConflator) put(u BookUpdate) bool {
+	cf.mu.Lock()
+	if cf.pending != nil {
+		cf.skipped++
+		u.Events = append(cf.pending.Events, u.Events...)
+	}
+	cf.pending = &u
+	cf.mu.Unlock()
+
+	select {
+	case cf.ready <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+// stop makes run return and close its channel.
+func (cf *bookConflator) stop() {
+	close(cf.done)
+}
+
+// run delivers pending updates to out until ctx is done or stop is
+// called, then closes out.
+func (cf *bookConflator) run(ctx context.Context, out chan<- BookUpdate) {
+	defer close(out)
+
+	var last time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-cf.done:
+			return
+		case <-cf.ready:
+		}
+
+		if wait := cf.interval - time.Since(last); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-cf.done:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		cf.mu.Lock()
+		u := cf.pending
+		cf.pending = nil
+		if u != nil {
+			u.Skipped += cf.skipped
+			cf.skipped = 0
+		}
+		cf.mu.Unlock()
+		if u == nil {
+			continue
+		}
+
+		select {
+		case out <- *u:
+			last = time.Now()
+		case <-ctx.Done():
+			return
+		case <-cf.done:
+			return
+		}
+	}
+}
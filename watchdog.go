@@ -0,0 +1,273 @@
+package wallex
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// StaleData is emitted by a Watchdog when a symbol's data is older than the
+// configured window.
+type StaleData struct {
+	Symbol string
+
+	// LastUpdate is when data for Symbol last arrived; zero if never.
+	LastUpdate time.Time
+
+	// Age is how old the data was when staleness was detected.
+	Age time.Duration
+
+	DetectedAt time.Time
+}
+
+// TradingSwitch can halt and resume a trading subsystem. *risk.Checker
+// implements it through its kill switch.
+type TradingSwitch interface {
+	Kill(reason string)
+	Resume()
+}
+
+// WatchdogOptions configures a Watchdog.
+type WatchdogOptions struct {
+	// Window is the maximum accepted age of a symbol's data. Required.
+	Window time.Duration
+
+	// CheckInterval is how often ages are checked. Defaults to Window/4.
+	CheckInterval time.Duration
+
+	// OnStale is called once when a symbol turns stale.
+	OnStale func(StaleData)
+
+	// OnRecover is called when a stale symbol receives data again.
+	OnRecover func(symbol string)
+
+	// Pause lists switches that are killed while any symbol is stale and
+	// resumed once all symbols are fresh again.
+	Pause []TradingSwitch
+}
+
+type watchedSymbol struct {
+	last  time.Time
+	since time.Time
+	stale bool
+}
+
+// Watchdog tracks the age of the latest market data per symbol.
+//
+// Data sources report arrivals through Touch, or by routing a book watcher
+// through ObserveBooks. Symbols must be registered with Watch; a symbol
+// that never received data turns stale Window after it was registered.
+//
+// Watchdog implements Closer and is safe for concurrent use.
+type Watchdog struct {
+	opts WatchdogOptions
+
+	mu      sync.Mutex
+	symbols map[string]*watchedSymbol
+	paused  bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	loops    sync.WaitGroup
+}
+
+// NewWatchdog returns a Watchdog. Call Run to start checking.
+func NewWatchdog(opts WatchdogOptions) *Watchdog {
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = opts.Window / 4
+	}
+	if opts.CheckInterval <= 0 {
+		opts.CheckInterval = time.Second
+	}
+	return &Watchdog{
+		opts:    opts,
+		symbols: make(map[string]*watchedSymbol),
+		stop:    make(chan struct{}),
+	}
+}
+
+// Watch registers symbols for staleness tracking.
+func (w *Watchdog) Watch(symbols ...string) {
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, s := range symbols {
+		if _, ok := w.symbols[s]; !ok {
+			w.symbols[s] = &watchedSymbol{since: now}
+		}
+	}
+}
+
+// Unwatch stops tracking symbols.
+func (w *Watchdog) Unwatch(symbols ...string) {
+	w.mu.Lock()
+	for _, s := range symbols {
+		delete(w.symbols, s)
+	}
+	w.mu.Unlock()
+	w.Check()
+}
+
+// Touch records that data for symbol arrived now. Unregistered symbols are
+// ignored.
+func (w *Watchdog) Touch(symbol string) {
+	w.TouchAt(symbol, time.Now())
+}
+
+// TouchAt records that data for symbol was produced at at. Older
+// timestamps than the latest recorded one are ignored.
+func (w *Watchdog) TouchAt(symbol string, at time.Time) {
+	w.mu.Lock()
+	ws, ok := w.symbols[symbol]
+	if !ok || at.Before(ws.last) {
+		w.mu.Unlock()
+		return
+	}
+	ws.last = at
+	recovered := ws.stale && time.Since(at) <= w.opts.Window
+	if recovered {
+		ws.stale = false
+	}
+	w.mu.Unlock()
+
+	if recovered {
+		if w.opts.OnRecover != nil {
+			w.opts.OnRecover(symbol)
+		}
+		w.updatePause()
+	}
+}
+
+// ObserveBooks forwards the updates of a book watcher, touching the symbol
+// for every update that carries a valid book.
+func (w *Watchdog) ObserveBooks(in <-chan BookUpdate) <-chan BookUpdate {
+	out := make(chan BookUpdate, cap(in))
+	go func() {
+		defer close(out)
+		for u := range in {
+			if u.Err == nil {
+				w.TouchAt(u.Symbol, u.ReceivedAt)
+			}
+			out <- u
+		}
+	}()
+	return out
+}
+
+// Age returns how old the data of symbol is. It reports false for
+// unregistered symbols.
+func (w *Watchdog) Age(symbol string) (time.Duration, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	ws, ok := w.symbols[symbol]
+	if !ok {
+		return 0, false
+	}
+	return time.Since(ws.reference()), true
+}
+
+// Stale returns the currently stale symbols in sorted order.
+func (w *Watchdog) Stale() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var out []string
+	for s, ws := range w.symbols {
+		if ws.stale {
+			out = append(out, s)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Check evaluates all symbols once, emitting StaleData for those that just
+// turned stale. Run calls it every CheckInterval.
+func (w *Watchdog) Check() {
+	now := time.Now()
+	var events []StaleData
+
+	w.mu.Lock()
+	for s, ws := range w.symbols {
+		age := now.Sub(ws.reference())
+		if ws.stale || age <= w.opts.Window {
+			continue
+		}
+		ws.stale = true
+		events = append(events, StaleData{Symbol: s, LastUpdate: ws.last, Age: age, DetectedAt: now})
+	}
+	w.mu.Unlock()
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Symbol < events[j].Symbol })
+	if w.opts.OnStale != nil {
+		for _, e := range events {
+			w.opts.OnStale(e)
+		}
+	}
+	w.updatePause()
+}
+
+// updatePause kills the switches when a symbol is stale and resumes them
+// once none is, acting only on transitions.
+func (w *Watchdog) updatePause() {
+	w.mu.Lock()
+	anyStale := false
+	var first string
+	for s, ws := range w.symbols {
+		if ws.stale {
+			if !anyStale || s < first {
+				first = s
+			}
+			anyStale = true
+		}
+	}
+	changed := anyStale != w.paused
+	w.paused = anyStale
+	w.mu.Unlock()
+
+	if !changed {
+		return
+	}
+	for _, sw := range w.opts.Pause {
+		if anyStale {
+			sw.Kill("stale market data for " + first)
+		} else {
+			sw.Resume()
+		}
+	}
+}
+
+// Run checks every CheckInterval until ctx is done or the watchdog is
+// closed.
+func (w *Watchdog) Run(ctx context.Context) {
+	w.loops.Add(1)
+	defer w.loops.Done()
+
+	ticker := time.NewTicker(w.opts.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Check()
+		}
+	}
+}
+
+// Close implements Closer. It stops Run loops and waits for them to return.
+func (w *Watchdog) Close(ctx context.Context) error {
+	w.stopOnce.Do(func() { close(w.stop) })
+	return waitGroupDone(ctx, &w.loops)
+}
+
+// reference is the time the symbol's age is measured from.
+func (ws *watchedSymbol) reference() time.Time {
+	if ws.last.IsZero() {
+		return ws.since
+	}
+	return ws.last
+}
@@ -0,0 +1,176 @@
+package wallex
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	t "github.com/darhelm/go-wallex/types"
+)
+
+// DefaultWithdrawalPollInterval is the polling interval of TrackWithdrawal.
+const DefaultWithdrawalPollInterval = 15 * time.Second
+
+// WithdrawalEvent reports an observed change of a tracked withdrawal: its
+// status, its transaction id or its confirmation count.
+type WithdrawalEvent struct {
+	Transfer t.CryptoTransfer
+
+	// PreviousStatus is the status before this change, or "" for the
+	// first observation.
+	PreviousStatus t.CryptoTransferStatus
+
+	ObservedAt time.Time
+}
+
+// WithdrawalTrackOptions tunes SubmitWithdrawal and TrackWithdrawal.
+type WithdrawalTrackOptions struct {
+	// Interval between polls of the withdrawal history. Defaults to
+	// DefaultWithdrawalPollInterval.
+	Interval time.Duration
+
+	// OnEvent is called for every observed change, including the final
+	// one. It runs on the calling goroutine.
+	OnEvent func(WithdrawalEvent)
+}
+
+// WithdrawalError is returned when a tracked withdrawal ends rejected or
+// canceled. Transfer holds its final state.
+type WithdrawalError struct {
+	GoWallexError
+	Transfer t.CryptoTransfer
+}
+
+// SubmitWithdrawal validates params against the asset's network
+// constraints, submits the withdrawal and tracks it to a terminal state
+// with TrackWithdrawal.
+//
+// A withdrawal that could not be validated or submitted returns the error
+// and a nil transfer. Once submitted, the transfer is always returned, even
+// when tracking ends with an error, so callers keep its id.
+//
+// Authentication: REQUIRED (withdrawal permission).
+func (c *Client) SubmitWithdrawal(ctx context.Context, params t.CryptoWithdrawalParams, opts WithdrawalTrackOptions) (*t.CryptoTransfer, error) {
+	networks, err := c.getAssetNetworks(ctx, params.Asset)
+	if err != nil {
+		return nil, err
+	}
+	network, ok := networks.Result.Get(params.Network)
+	if !ok {
+		return nil, &GoWallexError{
+			Message: "asset " + params.Asset + " has no network " + params.Network,
+			Err:     nil,
+		}
+	}
+	if err := network.CheckWithdrawal(params.Amount); err != nil {
+		return nil, &GoWallexError{
+			Message: "withdrawal rejected locally",
+			Err:     err,
+		}
+	}
+	if network.MemoRequired && params.Memo == "" {
+		return nil, &GoWallexError{
+			Message: "network " + params.Network + " requires a memo",
+			Err:     nil,
+		}
+	}
+
+	created, err := c.withdrawCrypto(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	final, err := c.TrackWithdrawal(ctx, created.Result, opts)
+	if final == nil {
+		final = &created.Result
+	}
+	return final, err
+}
+
+// TrackWithdrawal polls GET /v1/account/crypto-withdrawal until the given
+// withdrawal reaches a terminal state, reporting every change to
+// opts.OnEvent, and returns its final state.
+//
+// DONE returns the transfer, whose TxID identifies the on-chain
+// transaction. REJECTED and CANCELED return the transfer together with a
+// *WithdrawalError. Request errors are not fatal; when ctx is done, the last
+// observed state is returned with the last request error or ctx.Err().
+//
+// Authentication: REQUIRED.
+func (c *Client) TrackWithdrawal(ctx context.Context, withdrawal t.CryptoTransfer, opts WithdrawalTrackOptions) (*t.CryptoTransfer, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWithdrawalPollInterval
+	}
+
+	current := withdrawal
+	var previous *t.CryptoTransfer
+	var lastErr error
+	for {
+		if previous == nil || withdrawalChanged(*previous, current) {
+			ev := WithdrawalEvent{Transfer: current, ObservedAt: time.Now()}
+			if previous != nil {
+				ev.PreviousStatus = previous.Status
+			}
+			if opts.OnEvent != nil {
+				opts.OnEvent(ev)
+			}
+			snapshot := current
+			previous = &snapshot
+		}
+
+		if current.Status.IsFinal() {
+			if current.Status != t.CryptoStatusDone {
+				return &current, &WithdrawalError{
+					GoWallexError: GoWallexError{
+						Message: "withdrawal " + strconv.FormatInt(current.ID, 10) + " ended " + string(current.Status),
+						Err:     nil,
+					},
+					Transfer: current,
+				}
+			}
+			return &current, nil
+		}
+
+		timer := time.NewTimer(opts.Interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if lastErr != nil {
+				return &current, lastErr
+			}
+			return &current, ctx.Err()
+		case <-c.Done():
+			timer.Stop()
+			return &current, ErrClientClosed
+		case <-timer.C:
+		}
+
+		found, err := c.findWithdrawal(ctx, withdrawal.Asset, withdrawal.ID)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		lastErr = nil
+		if found != nil {
+			current = *found
+		}
+	}
+}
+
+// findWithdrawal looks a withdrawal up in the history of asset. It returns
+// nil when the withdrawal is not listed.
+func (c *Client) findWithdrawal(ctx context.Context, asset string, id int64) (*t.CryptoTransfer, error) {
+	for w, err := range c.cryptoHistory(ctx, "/account/crypto-withdrawal", t.CryptoHistoryParams{Asset: asset}) {
+		if err != nil {
+			return nil, err
+		}
+		if w.ID == id {
+			return &w, nil
+		}
+	}
+	return nil, nil
+}
+
+func withdrawalChanged(a, b t.CryptoTransfer) bool {
+	return a.Status != b.Status || a.TxID != b.TxID || a.Confirmations != b.Confirmations
+}